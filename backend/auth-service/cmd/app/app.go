@@ -1,21 +1,41 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	_ "github.com/lib/pq"
 	"go-forum-project/auth-service/cmd/app/grpcapp"
+	"go-forum-project/auth-service/internal/avatar"
 	"go-forum-project/auth-service/internal/config"
+	"go-forum-project/auth-service/internal/keys"
+	"go-forum-project/auth-service/internal/lockout"
+	"go-forum-project/auth-service/internal/oidc"
+	"go-forum-project/auth-service/internal/password"
 	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/auth-service/internal/totp"
 	"go-forum-project/auth-service/internal/usecase"
+	"go-forum-project/auth-service/internal/userdata"
+	"go-forum-project/auth-service/internal/username"
+	"go-forum-project/events"
+	"go-forum-project/mailer"
 )
 
 type App struct {
-	GRPCApp *grpcapp.App
+	GRPCApp   *grpcapp.App
+	AccountUC usecase.AccountUseCase
+	AuditUC   usecase.AuditUseCase
+	Guard     *lockout.Guard
+	Bus       events.Bus
+	Relay     *events.Relay
+
+	// challengeTTL - сколько живёт MFA токен, нужен для очистки счётчиков
+	challengeTTL time.Duration
 }
 
 func NewApp(cfg *config.Config) *App {
@@ -30,12 +50,103 @@ func NewApp(cfg *config.Config) *App {
 
 	userRepo := repo.NewUserRepo(db)
 	tokenRepo := repo.NewTokenRepo(db)
+	resetTokenRepo := repo.NewResetTokenRepo(db)
+	verificationTokenRepo := repo.NewVerificationTokenRepo(db)
+	mfaRepo := repo.NewMFARepo(db)
+	oidcRepo := repo.NewOIDCRepo(db)
+	personalTokenRepo := repo.NewPersonalTokenRepo(db)
+	profileRepo := repo.NewProfileRepo(db)
+	usernameHistoryRepo := repo.NewUsernameHistoryRepo(db)
+	banRepo := repo.NewBanRepo(db)
+	userAdminRepo := repo.NewUserAdminRepo(db)
+	accountRepo := repo.NewAccountRepo(db)
+	auditRepo := repo.NewAuditRepo(db)
+
+	signingKey, err := keys.Load(cfg.Security.SigningKeyPath)
+	if err != nil {
+		log.Fatalf("failed to load signing key: %v", err)
+	}
+
+	passwordPolicy, err := password.NewPolicy(cfg.Security.PasswordPolicy)
+	if err != nil {
+		log.Fatalf("failed to load password policy: %v", err)
+	}
+
+	usernamePolicy, err := username.NewPolicy(cfg.Security.UsernamePolicy)
+	if err != nil {
+		log.Fatalf("failed to load username policy: %v", err)
+	}
+
+	mfaKey := cfg.Security.MFA.EncryptionKey
+	if mfaKey == "" {
+		mfaKey = cfg.Security.SecretKey
+	}
+
+	mfaCipher, err := totp.NewCipher(mfaKey)
+	if err != nil {
+		log.Fatalf("failed to create mfa cipher: %v", err)
+	}
+
+	lockoutStore, err := lockout.NewStore(cfg.Security.LoginThrottle, db)
+	if err != nil {
+		log.Fatalf("failed to create login throttle store: %v", err)
+	}
+	guard := lockout.NewGuard(lockoutStore, lockout.NewPostgresAuditor(db), cfg.Security.LoginThrottle)
+
+	authUC := usecase.NewAuthUseCase(userRepo, tokenRepo, mfaRepo, banRepo, signingKey, passwordPolicy,
+		usernamePolicy, mfaCipher, guard, cfg.Security)
+
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		log.Fatalf("failed to create mailer: %v", err)
+	}
 
-	authUC := usecase.NewAuthUseCase(userRepo, tokenRepo, cfg.Security.SecretKey)
+	resetUC := usecase.NewPasswordResetUseCase(userRepo, tokenRepo, resetTokenRepo, passwordPolicy, mail,
+		cfg.Security.PasswordResetTTL, cfg.Frontend.BaseURL)
 
-	gRPCApp := grpcapp.NewGRPCApp(cfg.Server.GRPCPort, authUC)
+	verifyUC := usecase.NewEmailVerificationUseCase(userRepo, verificationTokenRepo, mail,
+		cfg.Security.EmailVerification.TokenTTL, cfg.Frontend.BaseURL)
 
-	return &App{GRPCApp: gRPCApp}
+	var providers []*oidc.Provider
+	for _, providerCfg := range cfg.Security.OIDC.Providers {
+		providers = append(providers, oidc.NewProvider(providerCfg))
+	}
+	oidcUC := usecase.NewOIDCUseCase(userRepo, oidcRepo, authUC, usernamePolicy, providers, cfg.Security.OIDC.StateTTL)
+
+	tokensUC := usecase.NewPersonalTokenUseCase(userRepo, personalTokenRepo, banRepo,
+		cfg.Security.PersonalTokens)
+
+	avatars, err := avatar.NewStore(cfg.Profiles)
+	if err != nil {
+		log.Fatalf("failed to create avatar store: %v", err)
+	}
+	profileUC := usecase.NewProfileUseCase(profileRepo, usernameHistoryRepo, usernamePolicy, avatars,
+		cfg.Security.UsernameChange)
+
+	dataServices := userdata.NewClients(cfg.Accounts)
+	adminUC := usecase.NewAdminUseCase(userRepo, userAdminRepo, banRepo, tokenRepo)
+	auditUC := usecase.NewAuditUseCase(auditRepo, dataServices)
+
+	accountUC := usecase.NewAccountUseCase(authUC, userRepo, accountRepo, profileRepo, personalTokenRepo, oidcRepo,
+		avatars, dataServices, cfg.Accounts)
+
+	gRPCApp := grpcapp.NewGRPCApp(cfg.Server.GRPCPort, authUC, resetUC, verifyUC, oidcUC, tokensUC, profileUC,
+		adminUC, accountUC, auditUC, cfg.Security.LoginThrottle.TrustedProxies)
+
+	bus, err := events.NewBus(cfg.Events)
+	if err != nil {
+		log.Fatalf("failed to create event bus: %v", err)
+	}
+	return &App{
+		GRPCApp:   gRPCApp,
+		AccountUC: accountUC,
+		AuditUC:   auditUC,
+		Guard:     guard,
+		Bus:       bus,
+		Relay:     events.NewRelay(db, bus, "auth", cfg.Events),
+
+		challengeTTL: cfg.Security.MFA.ChallengeTTL,
+	}
 }
 
 func (app *App) Run() {
@@ -45,10 +156,39 @@ func (app *App) Run() {
 		}
 	}()
 
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	go app.Relay.Run(relayCtx)
+
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		for range ticker.C {
+			if err := app.AccountUC.CleanupExports(context.Background()); err != nil {
+				log.Printf("Failed to cleanup data exports: %v", err)
+			}
+			if err := app.AccountUC.RetryErasures(context.Background()); err != nil {
+				log.Printf("Failed to retry data erasures: %v", err)
+			}
+			if err := app.Guard.Cleanup(context.Background(), app.challengeTTL); err != nil {
+				log.Printf("Failed to cleanup login attempts: %v", err)
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		for range ticker.C {
+			if err := app.AuditUC.Sync(context.Background()); err != nil {
+				log.Printf("Failed to sync audit log: %v", err)
+			}
+		}
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
 
 	app.GRPCApp.Stop()
+	stopRelay()
+	app.Bus.Close()
 	log.Println("Shutting down gracefully")
 }
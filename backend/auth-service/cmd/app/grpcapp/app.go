@@ -16,10 +16,14 @@ type App struct {
 	port       int
 }
 
-func NewGRPCApp(port int, authUC usecase.AuthUseCase) *App {
+func NewGRPCApp(port int, authUC usecase.AuthUseCase, resetUC usecase.PasswordResetUseCase,
+	verifyUC usecase.EmailVerificationUseCase, oidcUC usecase.OIDCUseCase, tokensUC usecase.PersonalTokenUseCase,
+	profileUC usecase.ProfileUseCase, adminUC usecase.AdminUseCase, accountUC usecase.AccountUseCase,
+	auditUC usecase.AuditUseCase, trustedProxies []string) *App {
 	gRPCServer := grpc.NewServer()
 
-	authHandler := handlers.NewAuthHandler(authUC)
+	authHandler := handlers.NewAuthHandler(authUC, resetUC, verifyUC, oidcUC, tokensUC, profileUC, adminUC,
+		accountUC, auditUC, handlers.ParseTrustedProxies(trustedProxies))
 	pb.RegisterAuthServiceServer(gRPCServer, authHandler)
 
 	return &App{
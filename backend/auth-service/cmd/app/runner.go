@@ -12,6 +12,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"go-forum-project/auth-service/internal/avatar"
 	"go-forum-project/auth-service/internal/config"
 )
 
@@ -33,7 +34,7 @@ func RunAuthApp() {
 	go func() {
 		defer wg.Done()
 		ctx := context.Background()
-		mux := runtime.NewServeMux()
+		mux := runtime.NewServeMux(runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher))
 
 		opts := []grpc.DialOption{
 			grpc.WithTransportCredentials(insecure.NewCredentials()),
@@ -49,6 +50,18 @@ func RunAuthApp() {
 			log.Fatalf("Failed to register gateway: %v", err)
 		}
 
+		avatars, err := avatar.NewStore(cfg.Profiles)
+		if err != nil {
+			log.Fatalf("Failed to create avatar store: %v", err)
+		}
+		err = mux.HandlePath(http.MethodGet, "/avatars/{name}",
+			func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+				avatars.Handler()(w, r)
+			})
+		if err != nil {
+			log.Fatalf("Failed to register avatars handler: %v", err)
+		}
+
 		corsHandler := allowCORS(mux)
 
 		log.Printf("Starting gateway server on: %d", cfg.Server.GRPCGatewayPort)
@@ -60,12 +73,22 @@ func RunAuthApp() {
 	wg.Wait()
 }
 
+// outgoingHeaderMatcher отдаёт content-disposition как обычный HTTP заголовок
+// (для скачивания выгрузки), остальную metadata - с префиксом Grpc-Metadata-.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if key == "content-disposition" {
+		return "Content-Disposition", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
 func allowCORS(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
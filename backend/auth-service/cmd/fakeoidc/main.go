@@ -0,0 +1,44 @@
+// fakeoidc запускает локальный OIDC провайдер для разработки.
+// В config.yaml auth-service он описан как провайдер "local".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"go-forum-project/auth-service/internal/oidc"
+)
+
+func main() {
+	var (
+		port         int
+		clientID     string
+		clientSecret string
+		subject      string
+	)
+
+	flag.IntVar(&port, "port", 9096, "Порт провайдера")
+	flag.StringVar(&clientID, "client-id", "go-forum", "Ожидаемый client_id")
+	flag.StringVar(&clientSecret, "client-secret", "go-forum-secret", "Ожидаемый client_secret")
+	flag.StringVar(&subject, "subject", "local-user", "Пользователь по умолчанию")
+	flag.Parse()
+
+	issuer := fmt.Sprintf("http://localhost:%d", port)
+	provider, err := oidc.NewFakeProvider(issuer, clientID, clientSecret, oidc.FakeUser{
+		Subject:           subject,
+		Email:             subject + "@example.test",
+		EmailVerified:     true,
+		PreferredUsername: subject,
+		Name:              subject,
+	})
+	if err != nil {
+		log.Fatalf("failed to create provider: %v", err)
+	}
+
+	log.Printf("Fake OIDC provider listening on %s", issuer)
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), provider); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
 	"go-forum-project/auth-service/internal/config"
+	"go-forum-project/migrator"
 )
 
 func main() {
@@ -49,11 +50,9 @@ func main() {
 
 	switch action {
 	case "up":
-		if steps > 0 {
-			err = m.Steps(steps)
-		} else {
-			err = m.Up()
-		}
+		err = migrator.Up(m, steps, map[uint]migrator.Backfill{
+			3: backfillUsernameSkeletons(db),
+		})
 	case "down":
 		if steps > 0 {
 			err = m.Steps(-steps)
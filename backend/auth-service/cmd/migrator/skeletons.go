@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go-forum-project/auth-service/internal/username"
+)
+
+// backfillUsernameSkeletons заполняет скелеты имён, созданных до миграции
+// 000003, тем же username.Skeleton, что и регистрация. Если скелеты разных
+// пользователей совпали, ничего не сохраняет и перечисляет их: похожие
+// имена нужно развести вручную до уникального индекса из 000016.
+func backfillUsernameSkeletons(db *sql.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		rows, err := tx.QueryContext(ctx, "SELECT id, username FROM users WHERE username_skeleton IS NULL")
+		if err != nil {
+			return err
+		}
+
+		names := make(map[int]string)
+		for rows.Next() {
+			var (
+				id   int
+				name string
+			)
+			if err := rows.Scan(&id, &name); err != nil {
+				rows.Close()
+				return err
+			}
+			names[id] = name
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for id, name := range names {
+			_, err := tx.ExecContext(ctx, "UPDATE users SET username_skeleton = $2 WHERE id = $1",
+				id, username.Skeleton(name))
+			if err != nil {
+				return err
+			}
+		}
+
+		rows, err = tx.QueryContext(ctx,
+			`SELECT string_agg(username || ' (id ' || id || ')', ', ' ORDER BY id)
+			 FROM users WHERE username_skeleton IS NOT NULL
+			 GROUP BY username_skeleton HAVING COUNT(*) > 1`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var lookalikes []string
+		for rows.Next() {
+			var group string
+			if err := rows.Scan(&group); err != nil {
+				return err
+			}
+			lookalikes = append(lookalikes, group)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(lookalikes) > 0 {
+			return fmt.Errorf("usernames look alike, rename all but one in each group and rerun: %s",
+				strings.Join(lookalikes, "; "))
+		}
+
+		return tx.Commit()
+	}
+}
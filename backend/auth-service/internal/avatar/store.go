@@ -0,0 +1,113 @@
+package avatar
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-forum-project/auth-service/internal/config"
+)
+
+var (
+	ErrTooLarge        = errors.New("avatar is too large")
+	ErrUnsupportedType = errors.New("avatar must be a PNG, JPEG, GIF or WebP image")
+)
+
+var extensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Store хранит загруженные аватары в каталоге на диске. Отдаёт их
+// grpc-gateway по адресу /avatars/{name}.
+type Store struct {
+	dir     string
+	baseURL string
+	maxSize int
+}
+
+func NewStore(cfg config.ProfilesConfig) (*Store, error) {
+	if err := os.MkdirAll(cfg.AvatarDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create avatar dir: %w", err)
+	}
+
+	return &Store{
+		dir:     cfg.AvatarDir,
+		baseURL: strings.TrimRight(cfg.AvatarBaseURL, "/"),
+		maxSize: cfg.MaxAvatarSize,
+	}, nil
+}
+
+// Save сохраняет изображение и возвращает его публичный URL. Тип
+// определяется по содержимому, а не по тому, что прислал клиент.
+func (s *Store) Save(userID int, data []byte) (string, error) {
+	if s.maxSize > 0 && len(data) > s.maxSize {
+		return "", ErrTooLarge
+	}
+
+	ext, ok := extensions[http.DetectContentType(data)]
+	if !ok {
+		return "", ErrUnsupportedType
+	}
+
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate avatar name: %w", err)
+	}
+
+	name := fmt.Sprintf("%d-%s%s", userID, hex.EncodeToString(random), ext)
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to save avatar: %w", err)
+	}
+
+	return s.baseURL + "/" + name, nil
+}
+
+// Delete удаляет ранее загруженный аватар. Внешние URL игнорируются.
+func (s *Store) Delete(avatarURL string) error {
+	name, ok := strings.CutPrefix(avatarURL, s.baseURL+"/")
+	if !ok || !validName(name) {
+		return nil
+	}
+
+	err := os.Remove(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Handler отдаёт файлы аватаров. Имя проверяется, чтобы нельзя было
+// выйти за пределы каталога.
+func (s *Store) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := filepath.Base(r.URL.Path)
+		if !validName(name) {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeFile(w, r, filepath.Join(s.dir, name))
+	}
+}
+
+func validName(name string) bool {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return false
+	}
+	for _, ext := range extensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
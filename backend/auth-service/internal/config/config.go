@@ -5,6 +5,8 @@ import (
 	"os"
 	"time"
 
+	"go-forum-project/events"
+	"go-forum-project/mailer"
 	"gopkg.in/yaml.v3"
 )
 
@@ -12,6 +14,11 @@ type Config struct {
 	Server   ServerConfig   `yaml:"server"`
 	Database DatabaseConfig `yaml:"database"`
 	Security SecurityConfig `yaml:"security"`
+	Mail     mailer.Config  `yaml:"mail"`
+	Frontend FrontendConfig `yaml:"frontend"`
+	Profiles ProfilesConfig `yaml:"profiles"`
+	Accounts AccountsConfig `yaml:"accounts"`
+	Events   events.Config  `yaml:"events"`
 }
 
 type ServerConfig struct {
@@ -32,6 +39,136 @@ type SecurityConfig struct {
 	SecretKey       string        `yaml:"secret_key"`
 	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
 	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+	SigningKeyPath  string        `yaml:"signing_key_path"`
+
+	PasswordResetTTL time.Duration `yaml:"password_reset_ttl"`
+
+	EmailVerification EmailVerificationConfig `yaml:"email_verification"`
+	MFA               MFAConfig               `yaml:"mfa"`
+	LoginThrottle     LoginThrottleConfig     `yaml:"login_throttle"`
+	OIDC              OIDCConfig              `yaml:"oidc"`
+	PersonalTokens    PersonalTokensConfig    `yaml:"personal_tokens"`
+
+	PasswordPolicy PasswordPolicyConfig `yaml:"password_policy"`
+	UsernamePolicy UsernamePolicyConfig `yaml:"username_policy"`
+	UsernameChange UsernameChangeConfig `yaml:"username_change"`
+}
+
+type PasswordPolicyConfig struct {
+	MinLength        int     `yaml:"min_length"`
+	MaxLength        int     `yaml:"max_length"`
+	RequireUpper     bool    `yaml:"require_upper"`
+	RequireLower     bool    `yaml:"require_lower"`
+	RequireDigit     bool    `yaml:"require_digit"`
+	RequireSpecial   bool    `yaml:"require_special"`
+	MinEntropyBits   float64 `yaml:"min_entropy_bits"`
+	BreachedListPath string  `yaml:"breached_list_path"`
+}
+
+type UsernamePolicyConfig struct {
+	MinLength      int      `yaml:"min_length"`
+	MaxLength      int      `yaml:"max_length"`
+	AllowedPattern string   `yaml:"allowed_pattern"`
+	Reserved       []string `yaml:"reserved"`
+}
+
+type UsernameChangeConfig struct {
+	// Cooldown - минимальный интервал между сменами имени
+	Cooldown time.Duration `yaml:"cooldown"`
+	// ReserveFor - сколько освобождённое имя недоступно другим пользователям
+	ReserveFor time.Duration `yaml:"reserve_for"`
+}
+
+type EmailVerificationConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	TokenTTL time.Duration `yaml:"token_ttl"`
+}
+
+type MFAConfig struct {
+	Issuer           string        `yaml:"issuer"`
+	ChallengeTTL     time.Duration `yaml:"challenge_ttl"`
+	RecoveryCodes    int           `yaml:"recovery_codes"`
+	EncryptionKey    string        `yaml:"encryption_key"`
+	RequiredForRoles []string      `yaml:"required_for_roles"`
+	// MaxAttempts - сколько неверных кодов принимается по одному MFA токену
+	MaxAttempts int `yaml:"max_attempts"`
+}
+
+type LoginThrottleConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Store - memory или postgres
+	Store string `yaml:"store"`
+
+	// Ошибки без задержки, дальше задержка BaseDelay удваивается до MaxDelay
+	FreeAttempts      int           `yaml:"free_attempts"`
+	FreeAttemptsPerIP int           `yaml:"free_attempts_per_ip"`
+	BaseDelay         time.Duration `yaml:"base_delay"`
+	MaxDelay          time.Duration `yaml:"max_delay"`
+	// Window - через сколько после последней ошибки счётчик обнуляется
+	Window time.Duration `yaml:"window"`
+
+	MaxAttemptsPerUser int           `yaml:"max_attempts_per_user"`
+	MaxAttemptsPerIP   int           `yaml:"max_attempts_per_ip"`
+	LockoutDuration    time.Duration `yaml:"lockout_duration"`
+
+	// TrustedProxies - адреса, которым доверяем X-Forwarded-For (CIDR или IP)
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+type OIDCConfig struct {
+	// StateTTL - сколько живёт незавершённый вход через провайдера
+	StateTTL  time.Duration        `yaml:"state_ttl"`
+	Providers []OIDCProviderConfig `yaml:"providers"`
+}
+
+type OIDCProviderConfig struct {
+	Name         string   `yaml:"name"`
+	DisplayName  string   `yaml:"display_name"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+
+	// LinkByEmail - привязывать вход к существующему пользователю с тем же
+	// email, если провайдер подтвердил адрес
+	LinkByEmail bool `yaml:"link_by_email"`
+	// AutoRegister - создавать пользователя при первом входе
+	AutoRegister bool `yaml:"auto_register"`
+}
+
+type PersonalTokensConfig struct {
+	MaxPerUser int `yaml:"max_per_user"`
+	// MaxTTL - 0 разрешает бессрочные токены
+	MaxTTL time.Duration `yaml:"max_ttl"`
+}
+
+type ProfilesConfig struct {
+	AvatarDir string `yaml:"avatar_dir"`
+	// AvatarBaseURL - публичный адрес, по которому gateway отдаёт аватары
+	AvatarBaseURL string `yaml:"avatar_base_url"`
+	// MaxAvatarSize - в байтах
+	MaxAvatarSize int `yaml:"max_avatar_size"`
+}
+
+type AccountsConfig struct {
+	// ExportDir - каталог для готовых архивов с выгрузкой данных
+	ExportDir string `yaml:"export_dir"`
+	// ExportTTL - сколько архив доступен для скачивания
+	ExportTTL time.Duration `yaml:"export_ttl"`
+	// InternalToken - общий секрет со служебным API остальных сервисов
+	InternalToken string `yaml:"internal_token"`
+	// DataServices - сервисы, которые хранят данные пользователя
+	DataServices []DataServiceConfig `yaml:"data_services"`
+}
+
+type DataServiceConfig struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+type FrontendConfig struct {
+	BaseURL string `yaml:"base_url"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -50,8 +187,10 @@ func LoadConfig(path string) (*Config, error) {
 	return config, nil
 }
 
+// GetConnectionString открывает сессии в UTC. Колонки TIMESTAMP хранятся без
+// часового пояса: сроки пишутся в UTC и сравниваются с NOW() той же сессии.
 func (d *DatabaseConfig) GetConnectionString() string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s&timezone=UTC",
 		d.User,
 		d.Password,
 		d.Host,
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/lockout"
+	"go-forum-project/auth-service/internal/usecase"
+	grpc "go-forum-project/proto/gRPC"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (h *AuthHandler) DeleteAccount(ctx context.Context, req *grpc.DeleteAccountRequest) (*grpc.DeleteAccountResponse,
+	error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return &grpc.DeleteAccountResponse{Success: false}, err
+	}
+
+	if err := h.accountUC.DeleteAccount(ctx, claims.UserID, req.Password, req.MfaCode); err != nil {
+		log.Printf("Failed delete account: %v", err)
+		var lockedErr *lockout.LockedError
+		if errors.As(err, &lockedErr) {
+			return &grpc.DeleteAccountResponse{Success: false}, lockedStatus(lockedErr)
+		}
+		if errors.Is(err, usecase.ErrWrongPassword) || errors.Is(err, usecase.ErrInvalidMFACode) {
+			return &grpc.DeleteAccountResponse{Success: false}, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return &grpc.DeleteAccountResponse{Success: false}, status.Error(codes.Unavailable,
+			"failed to delete account, please try again later")
+	}
+
+	return &grpc.DeleteAccountResponse{Success: true}, nil
+}
+
+func (h *AuthHandler) RequestDataExport(ctx context.Context, req *grpc.RequestDataExportRequest) (*grpc.DataExport,
+	error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := h.accountUC.RequestExport(ctx, claims.UserID)
+	if err != nil {
+		log.Printf("Failed request data export: %v", err)
+		return nil, exportStatus(err)
+	}
+
+	return dataExportToProto(export), nil
+}
+
+func (h *AuthHandler) GetDataExport(ctx context.Context, req *grpc.GetDataExportRequest) (*grpc.DataExport, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := h.accountUC.GetExport(ctx, claims.UserID, int(req.Id))
+	if err != nil {
+		log.Printf("Failed get data export: %v", err)
+		return nil, exportStatus(err)
+	}
+
+	return dataExportToProto(export), nil
+}
+
+func (h *AuthHandler) DownloadDataExport(ctx context.Context,
+	req *grpc.DownloadDataExportRequest) (*httpbody.HttpBody, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	export, data, err := h.accountUC.ReadExport(ctx, claims.UserID, int(req.Id))
+	if err != nil {
+		log.Printf("Failed download data export: %v", err)
+		return nil, exportStatus(err)
+	}
+
+	// gateway превращает этот заголовок в Content-Disposition
+	disposition := fmt.Sprintf(`attachment; filename="account-export-%d.zip"`, export.ID)
+	if err := gogrpc.SetHeader(ctx, metadata.Pairs("content-disposition", disposition)); err != nil {
+		log.Printf("Failed to set content disposition: %v", err)
+	}
+
+	return &httpbody.HttpBody{
+		ContentType: "application/zip",
+		Data:        data,
+	}, nil
+}
+
+func (h *AuthHandler) GetUserContacts(ctx context.Context,
+	req *grpc.GetUserContactsRequest) (*grpc.GetUserContactsResponse, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("x-internal-token"); len(values) > 0 {
+			token = values[0]
+		}
+	}
+
+	userIDs := make([]int, 0, len(req.UserIds))
+	for _, id := range req.UserIds {
+		userIDs = append(userIDs, int(id))
+	}
+
+	users, err := h.accountUC.GetContacts(ctx, token, userIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidInternalToken):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, usecase.ErrTooManyContacts):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		log.Printf("Failed get user contacts: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get user contacts")
+	}
+
+	resp := &grpc.GetUserContactsResponse{}
+	for _, user := range users {
+		resp.Contacts = append(resp.Contacts, &grpc.UserContact{
+			UserId:        int64(user.ID),
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+		})
+	}
+	return resp, nil
+}
+
+func exportStatus(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrExportNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrExportInProgress), errors.Is(err, usecase.ErrExportNotReady):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, "data export failed")
+}
+
+func dataExportToProto(export *entity.DataExport) *grpc.DataExport {
+	resp := &grpc.DataExport{
+		Id:          int64(export.ID),
+		Status:      export.Status,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt.Unix(),
+		CompletedAt: unixOrZero(export.CompletedAt),
+		ExpiresAt:   unixOrZero(export.ExpiresAt),
+	}
+	if export.Status == entity.ExportReady {
+		resp.DownloadUrl = fmt.Sprintf("/auth/account/exports/%d/download", export.ID)
+	}
+	return resp
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/usecase"
+	grpc "go-forum-project/proto/gRPC"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reason в ErrorInfo, по которому клиенты отличают блокировку от прочих отказов
+const bannedReason = "USER_BANNED"
+
+// requireAdmin пропускает только access токены с ролью admin.
+func (h *AuthHandler) requireAdmin(ctx context.Context) (*entity.TokenClaims, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Role != entity.RoleAdmin {
+		return nil, status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	return claims, nil
+}
+
+func (h *AuthHandler) AdminListUsers(ctx context.Context,
+	req *grpc.AdminListUsersRequest) (*grpc.AdminListUsersResponse, error) {
+	if _, err := h.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	items, total, err := h.adminUC.ListUsers(ctx, entity.UserFilter{
+		Query:    req.Query,
+		Role:     req.Role,
+		Page:     int(req.Page),
+		PageSize: int(req.PageSize),
+	})
+	if err != nil {
+		log.Printf("Failed list users: %v", err)
+		if st := validationStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, status.Error(codes.Internal, "failed to list users")
+	}
+
+	resp := &grpc.AdminListUsersResponse{Total: int32(total)}
+	for _, item := range items {
+		resp.Users = append(resp.Users, adminUserToProto(item))
+	}
+
+	return resp, nil
+}
+
+func (h *AuthHandler) AdminSetUserRole(ctx context.Context, req *grpc.AdminSetUserRoleRequest) (*grpc.AdminUser,
+	error) {
+	claims, err := h.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.adminUC.SetRole(ctx, claims.UserID, int(req.UserId), req.Role); err != nil {
+		log.Printf("Failed set user role: %v", err)
+		return nil, adminStatus(err)
+	}
+
+	return h.adminUser(ctx, int(req.UserId))
+}
+
+func (h *AuthHandler) AdminBanUser(ctx context.Context, req *grpc.AdminBanUserRequest) (*grpc.AdminUser, error) {
+	claims, err := h.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(req.DurationSeconds) * time.Second
+	if _, err := h.adminUC.BanUser(ctx, claims.UserID, int(req.UserId), req.Reason, duration); err != nil {
+		log.Printf("Failed ban user: %v", err)
+		return nil, adminStatus(err)
+	}
+
+	return h.adminUser(ctx, int(req.UserId))
+}
+
+func (h *AuthHandler) AdminUnbanUser(ctx context.Context, req *grpc.AdminUnbanUserRequest) (*grpc.AdminUser, error) {
+	claims, err := h.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.adminUC.UnbanUser(ctx, claims.UserID, int(req.UserId)); err != nil {
+		log.Printf("Failed unban user: %v", err)
+		return nil, adminStatus(err)
+	}
+
+	return h.adminUser(ctx, int(req.UserId))
+}
+
+// adminUser отдаёт актуальное состояние пользователя после изменения.
+func (h *AuthHandler) adminUser(ctx context.Context, userID int) (*grpc.AdminUser, error) {
+	item, err := h.adminUC.GetUser(ctx, userID)
+	if err != nil {
+		log.Printf("Failed get user %d: %v", userID, err)
+		return nil, adminStatus(err)
+	}
+	return adminUserToProto(item), nil
+}
+
+func adminStatus(err error) error {
+	if st := validationStatus(err); st != nil {
+		return st
+	}
+
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrCannotTargetSelf), errors.Is(err, usecase.ErrCannotBanAdmin),
+		errors.Is(err, usecase.ErrUserNotBanned):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, "admin operation failed")
+}
+
+// bannedStatus возвращает PermissionDenied с причиной и сроком блокировки
+// в ErrorInfo или nil, если err не связана с блокировкой.
+func bannedStatus(err error) error {
+	var bannedErr *usecase.BannedError
+	if !errors.As(err, &bannedErr) {
+		return nil
+	}
+
+	metadata := map[string]string{"reason": bannedErr.Reason}
+	if !bannedErr.ExpiresAt.IsZero() {
+		metadata["expires_at"] = bannedErr.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	st, detailsErr := status.New(codes.PermissionDenied, bannedErr.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   bannedReason,
+		Domain:   "auth",
+		Metadata: metadata,
+	})
+	if detailsErr != nil {
+		return status.Error(codes.PermissionDenied, bannedErr.Error())
+	}
+	return st.Err()
+}
+
+func adminUserToProto(item *entity.UserListItem) *grpc.AdminUser {
+	user := &grpc.AdminUser{
+		Id:            int64(item.User.ID),
+		Username:      item.User.Username,
+		Email:         item.User.Email,
+		EmailVerified: item.User.EmailVerified,
+		Role:          item.User.Role,
+		MfaEnabled:    item.User.MFAEnabled,
+		CreatedAt:     item.User.CreatedAt.Unix(),
+	}
+	if item.Ban != nil {
+		user.Ban = &grpc.UserBan{
+			Reason:    item.Ban.Reason,
+			BannedBy:  int64(item.Ban.BannedBy),
+			CreatedAt: item.Ban.CreatedAt.Unix(),
+			ExpiresAt: unixOrZero(item.Ban.ExpiresAt),
+		}
+	}
+	return user
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/usecase"
+	grpc "go-forum-project/proto/gRPC"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (h *AuthHandler) AdminListAuditLog(ctx context.Context,
+	req *grpc.AdminListAuditLogRequest) (*grpc.AdminListAuditLogResponse, error) {
+	if _, err := h.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	filter := auditFilterFromProto(req.Filter)
+	filter.Page = int(req.Page)
+	filter.PageSize = int(req.PageSize)
+
+	entries, total, err := h.auditUC.List(ctx, filter)
+	if err != nil {
+		log.Printf("Failed list audit log: %v", err)
+		return nil, auditStatus(err)
+	}
+
+	resp := &grpc.AdminListAuditLogResponse{Total: int32(total)}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &grpc.AuditEntry{
+			Id:         int64(e.ID),
+			Service:    e.Service,
+			ActorId:    int64(e.ActorID),
+			ActorRole:  e.ActorRole,
+			Action:     e.Action,
+			TargetType: e.TargetType,
+			TargetId:   int64(e.TargetID),
+			Reason:     e.Reason,
+			Before:     e.Before,
+			After:      e.After,
+			OccurredAt: e.OccurredAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+func (h *AuthHandler) AdminExportAuditLog(ctx context.Context,
+	req *grpc.AdminExportAuditLogRequest) (*httpbody.HttpBody, error) {
+	if _, err := h.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := h.auditUC.ExportCSV(ctx, auditFilterFromProto(req.Filter))
+	if err != nil {
+		log.Printf("Failed export audit log: %v", err)
+		return nil, auditStatus(err)
+	}
+
+	disposition := fmt.Sprintf(`attachment; filename="audit-log-%s.csv"`, time.Now().UTC().Format("20060102-150405"))
+	if err := gogrpc.SetHeader(ctx, metadata.Pairs("content-disposition", disposition)); err != nil {
+		log.Printf("Failed to set content disposition: %v", err)
+	}
+
+	return &httpbody.HttpBody{
+		ContentType: "text/csv; charset=utf-8",
+		Data:        data,
+	}, nil
+}
+
+func auditFilterFromProto(f *grpc.AuditLogFilter) entity.AuditFilter {
+	if f == nil {
+		return entity.AuditFilter{}
+	}
+
+	filter := entity.AuditFilter{
+		ActorID:    int(f.ActorId),
+		Action:     f.Action,
+		Service:    f.Service,
+		TargetType: f.TargetType,
+		TargetID:   int(f.TargetId),
+	}
+	if f.Since > 0 {
+		filter.Since = time.Unix(f.Since, 0)
+	}
+	if f.Until > 0 {
+		filter.Until = time.Unix(f.Until, 0)
+	}
+	return filter
+}
+
+func auditStatus(err error) error {
+	if st := validationStatus(err); st != nil {
+		return st
+	}
+	if errors.Is(err, usecase.ErrAuditExportTooLarge) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, "failed to read audit log")
+}
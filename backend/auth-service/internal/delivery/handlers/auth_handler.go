@@ -2,41 +2,99 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/lockout"
 	"go-forum-project/auth-service/internal/usecase"
 	grpc "go-forum-project/proto/gRPC"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"log"
+	"net/netip"
 )
 
 type AuthHandler struct {
 	grpc.UnimplementedAuthServiceServer
-	uc usecase.AuthUseCase
+	uc        usecase.AuthUseCase
+	resetUC   usecase.PasswordResetUseCase
+	verifyUC  usecase.EmailVerificationUseCase
+	oidcUC    usecase.OIDCUseCase
+	tokensUC  usecase.PersonalTokenUseCase
+	profileUC usecase.ProfileUseCase
+	adminUC   usecase.AdminUseCase
+	accountUC usecase.AccountUseCase
+	auditUC   usecase.AuditUseCase
+
+	trustedProxies []netip.Prefix
 }
 
-func NewAuthHandler(uc usecase.AuthUseCase) *AuthHandler {
-	return &AuthHandler{uc: uc}
+func NewAuthHandler(uc usecase.AuthUseCase, resetUC usecase.PasswordResetUseCase,
+	verifyUC usecase.EmailVerificationUseCase, oidcUC usecase.OIDCUseCase, tokensUC usecase.PersonalTokenUseCase,
+	profileUC usecase.ProfileUseCase, adminUC usecase.AdminUseCase, accountUC usecase.AccountUseCase,
+	auditUC usecase.AuditUseCase, trustedProxies []netip.Prefix) *AuthHandler {
+	return &AuthHandler{
+		uc:             uc,
+		resetUC:        resetUC,
+		verifyUC:       verifyUC,
+		oidcUC:         oidcUC,
+		tokensUC:       tokensUC,
+		profileUC:      profileUC,
+		adminUC:        adminUC,
+		accountUC:      accountUC,
+		auditUC:        auditUC,
+		trustedProxies: trustedProxies,
+	}
 }
 
 func (h *AuthHandler) Register(ctx context.Context, req *grpc.RegisterRequest) (*grpc.RegisterResponse, error) {
-	err := h.uc.Register(req.Username, req.Password)
+	user, err := h.uc.Register(req.Username, req.Password, req.Email)
 	if err != nil {
 		log.Printf("Failed register: %v", err)
+		if st := validationStatus(err); st != nil {
+			return &grpc.RegisterResponse{Success: false, Error: err.Error()}, st
+		}
+		if !errors.Is(err, usecase.ErrUsernameTaken) && !errors.Is(err, usecase.ErrEmailTaken) {
+			return &grpc.RegisterResponse{Success: false}, status.Error(codes.Internal, "Failed register")
+		}
 		return &grpc.RegisterResponse{
 			Success: false,
 			Error:   err.Error(),
 		}, status.Error(codes.AlreadyExists, "Failed register")
 	}
+
+	if !user.EmailVerified {
+		// Письмо можно отправить повторно через ResendVerificationEmail,
+		// поэтому ошибка отправки не отменяет регистрацию
+		if err := h.verifyUC.SendVerification(ctx, user); err != nil {
+			log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+		}
+	}
+
 	return &grpc.RegisterResponse{Success: true}, nil
 }
 
 func (h *AuthHandler) Login(ctx context.Context, req *grpc.LoginRequest) (*grpc.TokenResponse, error) {
-	tokens, err := h.uc.Login(req.Username, req.Password)
+	tokens, err := h.uc.Login(ctx, req.Username, req.Password, h.clientIP(ctx))
 	if err != nil {
+		var lockedErr *lockout.LockedError
+		if errors.As(err, &lockedErr) {
+			return nil, lockedStatus(lockedErr)
+		}
+		if st := bannedStatus(err); st != nil {
+			return nil, st
+		}
 		log.Printf("User not found: %v", err)
 		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
 	}
 
+	if tokens.MFAToken != "" {
+		return &grpc.TokenResponse{
+			MfaRequired:           true,
+			MfaToken:              tokens.MFAToken,
+			MfaEnrollmentRequired: tokens.MFAEnrollmentRequired,
+		}, nil
+	}
+
 	return &grpc.TokenResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
@@ -61,6 +119,9 @@ func (h *AuthHandler) Logout(ctx context.Context, req *grpc.LogoutRequest) (*grp
 func (h *AuthHandler) Refresh(ctx context.Context, req *grpc.RefreshRequest) (*grpc.TokenResponse, error) {
 	newTokens, err := h.uc.RefreshTokens(ctx, req.RefreshToken)
 	if err != nil {
+		if st := bannedStatus(err); st != nil {
+			return nil, st
+		}
 		return nil, status.Error(codes.Unauthenticated, "refresh failed")
 	}
 
@@ -72,20 +133,138 @@ func (h *AuthHandler) Refresh(ctx context.Context, req *grpc.RefreshRequest) (*g
 
 func (h *AuthHandler) ValidateToken(ctx context.Context, req *grpc.ValidateTokenRequest) (*grpc.ValidateTokenResponse,
 	error) {
-	username, isValid, err := h.uc.ValidateToken(ctx, req.AccessToken)
+	var (
+		claims *entity.TokenClaims
+		err    error
+	)
+	if usecase.IsPersonalToken(req.AccessToken) {
+		claims, err = h.tokensUC.Validate(ctx, req.AccessToken)
+	} else {
+		claims, err = h.uc.ValidateToken(ctx, req.AccessToken)
+	}
 	if err != nil {
 		log.Printf("Token validation failed: %v", err)
+		if st := bannedStatus(err); st != nil {
+			return nil, st
+		}
 		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
 
-	if isValid {
-		return &grpc.ValidateTokenResponse{
-			Username: username,
-			Valid:    true,
-		}, nil
-	}
-
 	return &grpc.ValidateTokenResponse{
-		Valid: false,
+		Username:      claims.Username,
+		Valid:         true,
+		ExpiresAt:     unixOrZero(claims.ExpiresAt),
+		EmailVerified: claims.EmailVerified,
+		Scopes:        claims.Scopes,
+		UserId:        int64(claims.UserID),
+		Role:          claims.Role,
 	}, nil
 }
+
+func (h *AuthHandler) ChangePassword(ctx context.Context, req *grpc.ChangePasswordRequest) (*grpc.ChangePasswordResponse,
+	error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return &grpc.ChangePasswordResponse{Success: false}, err
+	}
+
+	err = h.uc.ChangePassword(ctx, claims.UserID, req.CurrentPassword, req.NewPassword, req.RefreshToken)
+	if err != nil {
+		log.Printf("Failed change password: %v", err)
+		if st := validationStatus(err); st != nil {
+			return &grpc.ChangePasswordResponse{Success: false}, st
+		}
+		if errors.Is(err, usecase.ErrWrongPassword) {
+			return &grpc.ChangePasswordResponse{Success: false}, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return &grpc.ChangePasswordResponse{Success: false}, status.Error(codes.Internal, "change password failed")
+	}
+
+	return &grpc.ChangePasswordResponse{Success: true}, nil
+}
+
+func (h *AuthHandler) RequestPasswordReset(ctx context.Context,
+	req *grpc.RequestPasswordResetRequest) (*grpc.RequestPasswordResetResponse, error) {
+	if req.Email == "" {
+		return &grpc.RequestPasswordResetResponse{Success: false}, status.Error(codes.InvalidArgument, "email required")
+	}
+
+	if err := h.resetUC.RequestPasswordReset(ctx, req.Email); err != nil {
+		log.Printf("Failed request password reset: %v", err)
+		return &grpc.RequestPasswordResetResponse{Success: false}, status.Error(codes.Internal, "password reset failed")
+	}
+
+	return &grpc.RequestPasswordResetResponse{Success: true}, nil
+}
+
+func (h *AuthHandler) ResetPassword(ctx context.Context, req *grpc.ResetPasswordRequest) (*grpc.ResetPasswordResponse,
+	error) {
+	if err := h.resetUC.ResetPassword(ctx, req.Token, req.NewPassword); err != nil {
+		log.Printf("Failed reset password: %v", err)
+		if st := validationStatus(err); st != nil {
+			return &grpc.ResetPasswordResponse{Success: false}, st
+		}
+		if errors.Is(err, usecase.ErrInvalidResetToken) {
+			return &grpc.ResetPasswordResponse{Success: false}, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return &grpc.ResetPasswordResponse{Success: false}, status.Error(codes.Internal, "password reset failed")
+	}
+
+	return &grpc.ResetPasswordResponse{Success: true}, nil
+}
+
+func (h *AuthHandler) VerifyEmail(ctx context.Context, req *grpc.VerifyEmailRequest) (*grpc.VerifyEmailResponse, error) {
+	if err := h.verifyUC.VerifyEmail(ctx, req.Token); err != nil {
+		log.Printf("Failed verify email: %v", err)
+		if errors.Is(err, usecase.ErrInvalidVerificationToken) {
+			return &grpc.VerifyEmailResponse{Success: false}, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return &grpc.VerifyEmailResponse{Success: false}, status.Error(codes.Internal, "email verification failed")
+	}
+
+	return &grpc.VerifyEmailResponse{Success: true}, nil
+}
+
+func (h *AuthHandler) ResendVerificationEmail(ctx context.Context,
+	req *grpc.ResendVerificationEmailRequest) (*grpc.ResendVerificationEmailResponse, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return &grpc.ResendVerificationEmailResponse{Success: false}, err
+	}
+
+	if err := h.verifyUC.ResendVerification(ctx, claims.UserID); err != nil {
+		log.Printf("Failed resend verification email: %v", err)
+		switch {
+		case errors.Is(err, usecase.ErrEmailAlreadyVerified):
+			return &grpc.ResendVerificationEmailResponse{Success: false},
+				status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, usecase.ErrNoEmail):
+			return &grpc.ResendVerificationEmailResponse{Success: false},
+				status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return &grpc.ResendVerificationEmailResponse{Success: false},
+			status.Error(codes.Internal, "failed to send verification email")
+	}
+
+	return &grpc.ResendVerificationEmailResponse{Success: true}, nil
+}
+
+func (h *AuthHandler) GetPublicKeys(ctx context.Context, req *grpc.GetPublicKeysRequest) (*grpc.GetPublicKeysResponse,
+	error) {
+	publicKeys, err := h.uc.PublicKeys(ctx)
+	if err != nil {
+		log.Printf("Failed to get public keys: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get public keys")
+	}
+
+	resp := &grpc.GetPublicKeysResponse{}
+	for _, key := range publicKeys {
+		resp.Keys = append(resp.Keys, &grpc.PublicKey{
+			KeyId:     key.KeyID,
+			Algorithm: key.Algorithm,
+			PublicKey: key.PublicKey,
+		})
+	}
+
+	return resp, nil
+}
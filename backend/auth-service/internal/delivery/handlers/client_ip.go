@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/netip"
+	"strings"
+
+	"go-forum-project/auth-service/internal/lockout"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ParseTrustedProxies разбирает список адресов из конфига. Допускаются
+// как подсети, так и отдельные IP.
+func ParseTrustedProxies(values []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(value); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		log.Printf("Invalid trusted proxy %q, skipped", value)
+	}
+	return prefixes
+}
+
+// clientIP определяет адрес клиента. Берётся адрес gRPC peer; если это
+// доверенный прокси (например, grpc-gateway), адрес ищется в x-forwarded-for
+// справа налево до первого недоверенного адреса.
+func (h *AuthHandler) clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr, ok := parseIP(p.Addr.String())
+	if !ok {
+		return ""
+	}
+	if !h.trusted(addr) {
+		return addr.String()
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var forwarded []string
+	for _, value := range md.Get("x-forwarded-for") {
+		forwarded = append(forwarded, strings.Split(value, ",")...)
+	}
+
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedAddr, ok := parseIP(strings.TrimSpace(forwarded[i]))
+		if !ok {
+			break
+		}
+		addr = forwardedAddr
+		if !h.trusted(addr) {
+			break
+		}
+	}
+
+	return addr.String()
+}
+
+func (h *AuthHandler) trusted(addr netip.Addr) bool {
+	for _, prefix := range h.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIP принимает как "host:port", так и голый адрес.
+func parseIP(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func lockedStatus(err *lockout.LockedError) error {
+	st, detailsErr := status.New(codes.ResourceExhausted, err.Error()).WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(err.RetryAfter),
+	})
+	if detailsErr != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return st.Err()
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-forum-project/auth-service/internal/entity"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// accessTokenFromContext достаёт токен из metadata "authorization: Bearer <token>".
+// grpc-gateway прокидывает HTTP заголовок Authorization под тем же ключом.
+func accessTokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+
+	parts := strings.SplitN(values[0], " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return ""
+	}
+
+	return parts[1]
+}
+
+func (h *AuthHandler) authenticate(ctx context.Context) (*entity.TokenClaims, error) {
+	accessToken := accessTokenFromContext(ctx)
+	if accessToken == "" {
+		return nil, status.Error(codes.Unauthenticated, "access token required")
+	}
+
+	claims, err := h.uc.ValidateToken(ctx, accessToken)
+	if err != nil {
+		if st := bannedStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return claims, nil
+}
+
+// validationStatus превращает entity.ValidationError в InvalidArgument с
+// errdetails.BadRequest. Для остальных ошибок возвращает nil.
+func validationStatus(err error) error {
+	var validationErr *entity.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range validationErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	st, detailsErr := status.New(codes.InvalidArgument, validationErr.Error()).WithDetails(badRequest)
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, validationErr.Error())
+	}
+
+	return st.Err()
+}
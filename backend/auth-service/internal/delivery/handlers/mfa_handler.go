@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-forum-project/auth-service/internal/lockout"
+	"go-forum-project/auth-service/internal/usecase"
+	grpc "go-forum-project/proto/gRPC"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mfaUserID определяет пользователя для EnrollMFA/ConfirmMFA: по mfa_token,
+// если вход ещё не завершён (MFA обязательна для роли), иначе по access токену.
+func (h *AuthHandler) mfaUserID(ctx context.Context, mfaToken string) (int, bool, error) {
+	if mfaToken != "" {
+		userID, err := h.uc.ParseMFAToken(mfaToken, usecase.MFAPurposeEnroll)
+		if err != nil {
+			return 0, false, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return userID, true, nil
+	}
+
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return claims.UserID, false, nil
+}
+
+func mfaStatus(err error, fallback string) error {
+	var lockedErr *lockout.LockedError
+	if errors.As(err, &lockedErr) {
+		return lockedStatus(lockedErr)
+	}
+
+	switch {
+	case errors.Is(err, usecase.ErrInvalidMFAToken), errors.Is(err, usecase.ErrMFAAttemptsExceeded):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, usecase.ErrInvalidMFACode), errors.Is(err, usecase.ErrWrongPassword):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, usecase.ErrMFAAlreadyEnabled), errors.Is(err, usecase.ErrMFANotEnrolled),
+		errors.Is(err, usecase.ErrMFANotEnabled), errors.Is(err, usecase.ErrMFARequiredForRole):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, fallback)
+}
+
+func (h *AuthHandler) VerifyMFA(ctx context.Context, req *grpc.VerifyMFARequest) (*grpc.TokenResponse, error) {
+	tokens, err := h.uc.VerifyMFA(ctx, req.MfaToken, req.Code, h.clientIP(ctx))
+	if err != nil {
+		log.Printf("Failed verify mfa: %v", err)
+		return nil, mfaStatus(err, "mfa verification failed")
+	}
+
+	return &grpc.TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+func (h *AuthHandler) EnrollMFA(ctx context.Context, req *grpc.EnrollMFARequest) (*grpc.EnrollMFAResponse, error) {
+	userID, _, err := h.mfaUserID(ctx, req.MfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := h.uc.EnrollMFA(ctx, userID)
+	if err != nil {
+		log.Printf("Failed enroll mfa: %v", err)
+		return nil, mfaStatus(err, "mfa enrollment failed")
+	}
+
+	return &grpc.EnrollMFAResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	}, nil
+}
+
+func (h *AuthHandler) ConfirmMFA(ctx context.Context, req *grpc.ConfirmMFARequest) (*grpc.ConfirmMFAResponse, error) {
+	userID, login, err := h.mfaUserID(ctx, req.MfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, tokens, err := h.uc.ConfirmMFA(ctx, userID, req.Code, login)
+	if err != nil {
+		log.Printf("Failed confirm mfa: %v", err)
+		return nil, mfaStatus(err, "mfa confirmation failed")
+	}
+
+	resp := &grpc.ConfirmMFAResponse{RecoveryCodes: recoveryCodes}
+	if tokens != nil {
+		resp.AccessToken = tokens.AccessToken
+		resp.RefreshToken = tokens.RefreshToken
+	}
+
+	return resp, nil
+}
+
+func (h *AuthHandler) DisableMFA(ctx context.Context, req *grpc.DisableMFARequest) (*grpc.DisableMFAResponse, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return &grpc.DisableMFAResponse{Success: false}, err
+	}
+
+	if err := h.uc.DisableMFA(ctx, claims.UserID, req.Password, req.Code); err != nil {
+		log.Printf("Failed disable mfa: %v", err)
+		return &grpc.DisableMFAResponse{Success: false}, mfaStatus(err, "failed to disable mfa")
+	}
+
+	return &grpc.DisableMFAResponse{Success: true}, nil
+}
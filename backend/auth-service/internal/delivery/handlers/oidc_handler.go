@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-forum-project/auth-service/internal/usecase"
+	grpc "go-forum-project/proto/gRPC"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *AuthHandler) ListOIDCProviders(ctx context.Context,
+	req *grpc.ListOIDCProvidersRequest) (*grpc.ListOIDCProvidersResponse, error) {
+	resp := &grpc.ListOIDCProvidersResponse{}
+	for _, provider := range h.oidcUC.Providers() {
+		resp.Providers = append(resp.Providers, &grpc.OIDCProvider{
+			Name:        provider.Name,
+			DisplayName: provider.DisplayName,
+		})
+	}
+
+	return resp, nil
+}
+
+func (h *AuthHandler) StartOIDCLogin(ctx context.Context,
+	req *grpc.StartOIDCLoginRequest) (*grpc.StartOIDCLoginResponse, error) {
+	linkUserID := 0
+	if req.Link {
+		claims, err := h.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		linkUserID = claims.UserID
+	}
+
+	authURL, err := h.oidcUC.StartLogin(ctx, req.Provider, linkUserID)
+	if err != nil {
+		log.Printf("Failed start oidc login: %v", err)
+		if errors.Is(err, usecase.ErrUnknownProvider) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Unavailable, "identity provider unavailable")
+	}
+
+	return &grpc.StartOIDCLoginResponse{AuthorizationUrl: authURL}, nil
+}
+
+func (h *AuthHandler) FinishOIDCLogin(ctx context.Context,
+	req *grpc.FinishOIDCLoginRequest) (*grpc.FinishOIDCLoginResponse, error) {
+	if req.State == "" || req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "state and code required")
+	}
+
+	tokens, linked, err := h.oidcUC.FinishLogin(ctx, req.State, req.Code)
+	if err != nil {
+		log.Printf("Failed finish oidc login: %v", err)
+		switch {
+		case errors.Is(err, usecase.ErrInvalidOIDCState):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, usecase.ErrOIDCAccountNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, usecase.ErrIdentityLinkedToUser), errors.Is(err, usecase.ErrProviderAlreadyLinked):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		if st := bannedStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, status.Error(codes.Unauthenticated, "oidc login failed")
+	}
+
+	if linked {
+		return &grpc.FinishOIDCLoginResponse{Linked: true}, nil
+	}
+
+	return &grpc.FinishOIDCLoginResponse{
+		AccessToken:           tokens.AccessToken,
+		RefreshToken:          tokens.RefreshToken,
+		MfaRequired:           tokens.MFAToken != "",
+		MfaToken:              tokens.MFAToken,
+		MfaEnrollmentRequired: tokens.MFAEnrollmentRequired,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/usecase"
+	grpc "go-forum-project/proto/gRPC"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *AuthHandler) CreatePersonalToken(ctx context.Context,
+	req *grpc.CreatePersonalTokenRequest) (*grpc.CreatePersonalTokenResponse, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	token, info, err := h.tokensUC.Create(ctx, claims.UserID, req.Name, req.Scopes, ttl)
+	if err != nil {
+		log.Printf("Failed create personal token: %v", err)
+		if st := validationStatus(err); st != nil {
+			return nil, st
+		}
+		if errors.Is(err, usecase.ErrTooManyPersonalTokens) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "failed to create personal token")
+	}
+
+	return &grpc.CreatePersonalTokenResponse{
+		Token: token,
+		Info:  personalTokenToProto(info),
+	}, nil
+}
+
+func (h *AuthHandler) ListPersonalTokens(ctx context.Context,
+	req *grpc.ListPersonalTokensRequest) (*grpc.ListPersonalTokensResponse, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := h.tokensUC.List(ctx, claims.UserID)
+	if err != nil {
+		log.Printf("Failed list personal tokens: %v", err)
+		return nil, status.Error(codes.Internal, "failed to list personal tokens")
+	}
+
+	resp := &grpc.ListPersonalTokensResponse{}
+	for _, token := range tokens {
+		resp.Tokens = append(resp.Tokens, personalTokenToProto(token))
+	}
+
+	return resp, nil
+}
+
+func (h *AuthHandler) RevokePersonalToken(ctx context.Context,
+	req *grpc.RevokePersonalTokenRequest) (*grpc.RevokePersonalTokenResponse, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return &grpc.RevokePersonalTokenResponse{Success: false}, err
+	}
+
+	if err := h.tokensUC.Revoke(ctx, claims.UserID, int(req.Id)); err != nil {
+		log.Printf("Failed revoke personal token: %v", err)
+		if errors.Is(err, usecase.ErrPersonalTokenNotFound) {
+			return &grpc.RevokePersonalTokenResponse{Success: false}, status.Error(codes.NotFound, err.Error())
+		}
+		return &grpc.RevokePersonalTokenResponse{Success: false},
+			status.Error(codes.Internal, "failed to revoke personal token")
+	}
+
+	return &grpc.RevokePersonalTokenResponse{Success: true}, nil
+}
+
+func personalTokenToProto(token *entity.PersonalToken) *grpc.PersonalToken {
+	return &grpc.PersonalToken{
+		Id:         int64(token.ID),
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		CreatedAt:  token.CreatedAt.Unix(),
+		ExpiresAt:  unixOrZero(token.ExpiresAt),
+		LastUsedAt: unixOrZero(token.LastUsedAt),
+	}
+}
+
+// unixOrZero отдаёт 0 вместо отрицательного Unix времени для пустого time.Time.
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
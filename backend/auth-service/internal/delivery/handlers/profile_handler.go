@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/usecase"
+	grpc "go-forum-project/proto/gRPC"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *AuthHandler) GetProfile(ctx context.Context, req *grpc.GetProfileRequest) (*grpc.Profile, error) {
+	profile, err := h.profileUC.GetProfile(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, usecase.ErrProfileNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		log.Printf("Failed get profile: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get profile")
+	}
+
+	return profileToProto(profile), nil
+}
+
+func (h *AuthHandler) UpdateProfile(ctx context.Context, req *grpc.UpdateProfileRequest) (*grpc.Profile, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := h.profileUC.UpdateProfile(ctx, claims.UserID, entity.ProfileUpdate{
+		DisplayName: req.DisplayName,
+		Bio:         req.Bio,
+		AvatarURL:   req.AvatarUrl,
+		Location:    req.Location,
+	})
+	if err != nil {
+		log.Printf("Failed update profile: %v", err)
+		if st := validationStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, status.Error(codes.Internal, "failed to update profile")
+	}
+
+	return profileToProto(profile), nil
+}
+
+func (h *AuthHandler) UploadAvatar(ctx context.Context, req *grpc.UploadAvatarRequest) (*grpc.Profile, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := h.profileUC.UploadAvatar(ctx, claims.UserID, req.Data)
+	if err != nil {
+		log.Printf("Failed upload avatar: %v", err)
+		if st := validationStatus(err); st != nil {
+			return nil, st
+		}
+		return nil, status.Error(codes.Internal, "failed to upload avatar")
+	}
+
+	return profileToProto(profile), nil
+}
+
+func (h *AuthHandler) ChangeUsername(ctx context.Context, req *grpc.ChangeUsernameRequest) (*grpc.Profile, error) {
+	claims, err := h.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := h.profileUC.ChangeUsername(ctx, claims.UserID, req.Username)
+	if err != nil {
+		log.Printf("Failed change username: %v", err)
+		if st := validationStatus(err); st != nil {
+			return nil, st
+		}
+		switch {
+		case errors.Is(err, usecase.ErrUsernameTaken):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		case errors.Is(err, usecase.ErrUsernameUnchanged):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, usecase.ErrUsernameCooldown):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "failed to change username")
+	}
+
+	return profileToProto(profile), nil
+}
+
+func profileToProto(profile *entity.Profile) *grpc.Profile {
+	return &grpc.Profile{
+		UserId:      int64(profile.UserID),
+		Username:    profile.Username,
+		DisplayName: profile.DisplayName,
+		Bio:         profile.Bio,
+		AvatarUrl:   profile.AvatarURL,
+		Location:    profile.Location,
+		JoinedAt:    profile.JoinedAt.Unix(),
+	}
+}
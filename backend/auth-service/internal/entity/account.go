@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+const (
+	ExportPending = "pending"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+)
+
+// DataExport - задача на выгрузку данных пользователя в ZIP архив.
+type DataExport struct {
+	ID          int
+	UserID      int
+	Status      string
+	FileName    string
+	Error       string
+	CreatedAt   time.Time
+	CompletedAt time.Time
+	ExpiresAt   time.Time
+}
+
+// AccountData - данные auth-service в выгрузке (account.json).
+type AccountData struct {
+	ID             int
+	Username       string
+	Email          string
+	EmailVerified  bool
+	Role           string
+	MFAEnabled     bool
+	CreatedAt      time.Time
+	Profile        *Profile
+	PersonalTokens []*PersonalToken
+	Identities     []*OIDCIdentity
+}
+
+// PendingErasure - сервис, в котором ещё не стёрты данные удалённого пользователя.
+type PendingErasure struct {
+	UserID    int
+	Service   string
+	Attempts  int
+	CreatedAt time.Time
+}
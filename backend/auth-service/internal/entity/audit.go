@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// AuditEntry - запись журнала действий модераторов и администраторов всех
+// сервисов. Before и After - состояние объекта в JSON, пустые, если его нет.
+type AuditEntry struct {
+	ID      int
+	Service string
+	// SourceID - ID записи в журнале сервиса Service, 0 у записей auth-service
+	SourceID   int64
+	ActorID    int
+	ActorRole  string
+	Action     string
+	TargetType string
+	TargetID   int
+	Reason     string
+	Before     string
+	After      string
+	OccurredAt time.Time
+	RecordedAt time.Time
+}
+
+// AuditFilter - параметры поиска по журналу. Нулевые поля не фильтруют.
+type AuditFilter struct {
+	ActorID    int
+	Action     string
+	Service    string
+	TargetType string
+	TargetID   int
+	Since      time.Time
+	Until      time.Time
+	Page       int
+	PageSize   int
+}
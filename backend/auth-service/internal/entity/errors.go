@@ -0,0 +1,22 @@
+package entity
+
+import "strings"
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError возвращается, когда входные данные не проходят проверку.
+// Хендлеры превращают его в InvalidArgument с деталями по каждому полю.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	descriptions := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		descriptions = append(descriptions, v.Field+": "+v.Description)
+	}
+	return "validation failed: " + strings.Join(descriptions, "; ")
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+type OIDCProvider struct {
+	Name        string
+	DisplayName string
+}
+
+// OIDCLoginState - незавершённый вход через провайдера. LinkUserID задан,
+// если пользователь привязывает провайдера к уже существующему аккаунту.
+type OIDCLoginState struct {
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	LinkUserID   int
+	ExpireAt     time.Time
+}
+
+type OIDCIdentity struct {
+	UserID   int
+	Provider string
+	Subject  string
+	Email    string
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Profile - публичные данные пользователя.
+type Profile struct {
+	UserID      int
+	Username    string
+	DisplayName string
+	Bio         string
+	AvatarURL   string
+	Location    string
+	JoinedAt    time.Time
+}
+
+// ProfileUpdate - изменяемые поля профиля. nil означает "не менять".
+type ProfileUpdate struct {
+	DisplayName *string
+	Bio         *string
+	AvatarURL   *string
+	Location    *string
+}
@@ -7,6 +7,10 @@ type TokenPair struct {
 	RefreshToken     string
 	AccessExpiresAt  time.Time
 	RefreshExpiresAt time.Time
+
+	// MFAToken выдаётся вместо пары токенов, если для входа нужен второй фактор
+	MFAToken              string
+	MFAEnrollmentRequired bool
 }
 type RefreshToken struct {
 	ID        int
@@ -14,3 +18,40 @@ type RefreshToken struct {
 	TokenHash string
 	ExpiresAt time.Time
 }
+
+type PublicKey struct {
+	KeyID     string
+	Algorithm string
+	PublicKey []byte
+}
+
+type TokenClaims struct {
+	UserID        int
+	Username      string
+	Role          string
+	EmailVerified bool
+	ExpiresAt     time.Time
+	// Scopes заполняется только для персональных токенов
+	Scopes []string
+}
+
+// Права персональных токенов. Токены сессии (JWT) скоупов не имеют
+// и дают полный доступ.
+const (
+	ScopeRead = "read"
+	ScopePost = "post"
+	ScopeChat = "chat"
+)
+
+// PersonalToken - долгоживущий токен для скриптов и ботов. Сам токен
+// не хранится, Prefix позволяет узнать его в списке.
+type PersonalToken struct {
+	ID         int
+	UserID     int
+	Name       string
+	Prefix     string
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+}
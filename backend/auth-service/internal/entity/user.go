@@ -1,8 +1,52 @@
 package entity
 
+import "time"
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
-	ID       int
-	Username string
-	Password string
+	ID               int
+	Username         string
+	UsernameSkeleton string
+	Email            string
+	EmailVerified    bool
+	Password         string
+	Role             string
+	MFAEnabled       bool
+	MFASecret        string
+	MFALastStep      int64
+	CreatedAt        time.Time
+}
+
+// Ban - блокировка пользователя. Нулевой ExpiresAt означает бессрочную.
+type Ban struct {
+	ID        int
+	UserID    int
+	Reason    string
+	BannedBy  int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// UserFilter - параметры поиска пользователей в админке.
+type UserFilter struct {
+	Query    string
 	Role     string
+	Page     int
+	PageSize int
+}
+
+// UserListItem - пользователь в списке админки вместе с действующей блокировкой.
+type UserListItem struct {
+	User *User
+	Ban  *Ban
+}
+
+type MFAEnrollment struct {
+	Secret string
+	URI    string
 }
@@ -0,0 +1,102 @@
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"go-forum-project/auth-service/internal/entity"
+)
+
+const Algorithm = "EdDSA"
+
+// KeySet держит ключ, которым auth-service подписывает access токены.
+// Публичная часть раздаётся через GetPublicKeys, чтобы остальные сервисы
+// могли проверять токены без обращения к auth-service.
+type KeySet struct {
+	keyID      string
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// Load читает PKCS#8 ключ Ed25519 из PEM файла. Если путь пустой, генерируется
+// временный ключ, который живёт до перезапуска сервиса.
+func Load(path string) (*KeySet, error) {
+	if path == "" {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		log.Println("Signing key path is not set, using ephemeral signing key")
+		return newKeySet(privateKey), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	privateKey, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("signing key must be an Ed25519 key")
+	}
+
+	return newKeySet(privateKey), nil
+}
+
+func newKeySet(privateKey ed25519.PrivateKey) *KeySet {
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	sum := sha256.Sum256(publicKey)
+
+	return &KeySet{
+		keyID:      hex.EncodeToString(sum[:8]),
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}
+}
+
+func (k *KeySet) KeyID() string {
+	return k.keyID
+}
+
+func (k *KeySet) PrivateKey() ed25519.PrivateKey {
+	return k.privateKey
+}
+
+// PublicKey возвращает публичный ключ по его идентификатору.
+func (k *KeySet) PublicKey(keyID string) (ed25519.PublicKey, bool) {
+	if keyID != k.keyID {
+		return nil, false
+	}
+	return k.publicKey, true
+}
+
+func (k *KeySet) PublicKeys() ([]entity.PublicKey, error) {
+	der, err := x509.MarshalPKIXPublicKey(k.publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	return []entity.PublicKey{{
+		KeyID:     k.keyID,
+		Algorithm: Algorithm,
+		PublicKey: der,
+	}}, nil
+}
@@ -0,0 +1,268 @@
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-forum-project/auth-service/internal/config"
+)
+
+// Attempts - состояние счётчика неудачных входов для одного ключа
+// (имя пользователя или IP).
+type Attempts struct {
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+type Store interface {
+	Get(ctx context.Context, key string) (Attempts, error)
+	// RecordFailure увеличивает счётчик. Если последняя ошибка была раньше
+	// resetBefore, счёт начинается заново.
+	RecordFailure(ctx context.Context, key string, now, resetBefore time.Time) (Attempts, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+	// Cleanup удаляет ключи без ошибок с resetBefore и без действующей на
+	// now блокировки.
+	Cleanup(ctx context.Context, now, resetBefore time.Time) error
+}
+
+type Event struct {
+	Scope       string
+	Username    string
+	IP          string
+	Failures    int
+	LockedUntil time.Time
+}
+
+type Auditor interface {
+	RecordLockout(ctx context.Context, event Event) error
+}
+
+// LockedError возвращается, пока действует задержка или блокировка.
+type LockedError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LockedError) Error() string {
+	retryAfter := e.RetryAfter.Round(time.Second)
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	if e.Locked {
+		return fmt.Sprintf("too many failed login attempts, account temporarily locked, retry in %s", retryAfter)
+	}
+	return fmt.Sprintf("too many failed login attempts, retry in %s", retryAfter)
+}
+
+const (
+	ScopeUser = "user"
+	ScopeIP   = "ip"
+	ScopeMFA  = "mfa"
+)
+
+type Guard struct {
+	store   Store
+	auditor Auditor
+	cfg     config.LoginThrottleConfig
+	now     func() time.Time
+}
+
+func NewGuard(store Store, auditor Auditor, cfg config.LoginThrottleConfig) *Guard {
+	return &Guard{store: store, auditor: auditor, cfg: cfg, now: time.Now}
+}
+
+// NewStore выбирает хранилище по login_throttle.store: "memory" держит
+// счётчики в процессе, "postgres" разделяет их между экземплярами сервиса.
+func NewStore(cfg config.LoginThrottleConfig, db *sql.DB) (Store, error) {
+	switch cfg.Store {
+	case "memory", "":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown login throttle store: %s", cfg.Store)
+	}
+}
+
+func userKey(username string) string {
+	return ScopeUser + ":" + strings.ToLower(username)
+}
+
+func ipKey(ip string) string {
+	return ScopeIP + ":" + ip
+}
+
+func challengeKey(challengeID string) string {
+	return ScopeMFA + ":" + challengeID
+}
+
+// Check возвращает *LockedError, если попытку входа нужно отклонить
+// без проверки пароля.
+func (g *Guard) Check(ctx context.Context, username, ip string) error {
+	if !g.cfg.Enabled {
+		return nil
+	}
+
+	now := g.now()
+	var retryAfter time.Duration
+	locked := false
+
+	for _, key := range g.keys(username, ip) {
+		attempts, err := g.store.Get(ctx, key.key)
+		if err != nil {
+			return fmt.Errorf("failed to get login attempts: %w", err)
+		}
+
+		if attempts.LockedUntil.After(now) {
+			locked = true
+			retryAfter = max(retryAfter, attempts.LockedUntil.Sub(now))
+			continue
+		}
+
+		if wait := g.backoff(attempts, key.freeAttempts).Sub(now); wait > 0 {
+			retryAfter = max(retryAfter, wait)
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter, Locked: locked}
+	}
+	return nil
+}
+
+// Failure учитывает неудачную попытку и блокирует ключ по достижении лимита.
+func (g *Guard) Failure(ctx context.Context, username, ip string) error {
+	if !g.cfg.Enabled {
+		return nil
+	}
+
+	now := g.now()
+	for _, key := range g.keys(username, ip) {
+		attempts, err := g.store.RecordFailure(ctx, key.key, now, now.Add(-g.cfg.Window))
+		if err != nil {
+			return fmt.Errorf("failed to record login failure: %w", err)
+		}
+
+		if key.maxAttempts <= 0 || attempts.Failures < key.maxAttempts {
+			continue
+		}
+
+		lockedUntil := now.Add(g.cfg.LockoutDuration)
+		if err := g.store.Lock(ctx, key.key, lockedUntil); err != nil {
+			return fmt.Errorf("failed to lock %s: %w", key.key, err)
+		}
+
+		event := Event{
+			Scope:       key.scope,
+			Username:    username,
+			IP:          ip,
+			Failures:    attempts.Failures,
+			LockedUntil: lockedUntil,
+		}
+		log.Printf("Login locked: scope=%s username=%q ip=%s failures=%d until=%s",
+			event.Scope, event.Username, event.IP, event.Failures, event.LockedUntil.Format(time.RFC3339))
+		if g.auditor != nil {
+			if err := g.auditor.RecordLockout(ctx, event); err != nil {
+				log.Printf("Failed to audit lockout: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Success сбрасывает счётчик пользователя. Счётчик IP не сбрасывается,
+// иначе перебор можно было бы чередовать со входом в собственный аккаунт.
+func (g *Guard) Success(ctx context.Context, username string) error {
+	if !g.cfg.Enabled {
+		return nil
+	}
+	return g.store.Reset(ctx, userKey(username))
+}
+
+// ChallengeExhausted сообщает, исчерпаны ли попытки ввода кода для одного
+// MFA токена. Лимит на токен действует и при выключенном login_throttle:
+// без него каждый новый вход давал бы свежую серию попыток.
+func (g *Guard) ChallengeExhausted(ctx context.Context, challengeID string, maxAttempts int) (bool, error) {
+	attempts, err := g.store.Get(ctx, challengeKey(challengeID))
+	if err != nil {
+		return false, fmt.Errorf("failed to get mfa attempts: %w", err)
+	}
+	return attempts.Failures >= maxAttempts, nil
+}
+
+// ChallengeFailure учитывает неверный код для MFA токена. Записи старше ttl
+// относятся к уже истёкшим токенам и сбрасываются.
+func (g *Guard) ChallengeFailure(ctx context.Context, challengeID string, ttl time.Duration) error {
+	now := g.now()
+	// resetBefore общий для очистки всех ключей в MemoryStore, поэтому
+	// не короче окна счётчиков входа
+	resetBefore := now.Add(-max(g.cfg.Window, ttl))
+	if _, err := g.store.RecordFailure(ctx, challengeKey(challengeID), now, resetBefore); err != nil {
+		return fmt.Errorf("failed to record mfa failure: %w", err)
+	}
+	return nil
+}
+
+// Cleanup удаляет счётчики, которые уже ни на что не влияют. challengeTTL -
+// время жизни MFA токена, его счётчик нужен, пока токен действует.
+func (g *Guard) Cleanup(ctx context.Context, challengeTTL time.Duration) error {
+	now := g.now()
+	if err := g.store.Cleanup(ctx, now, now.Add(-max(g.cfg.Window, challengeTTL))); err != nil {
+		return fmt.Errorf("failed to cleanup login attempts: %w", err)
+	}
+	return nil
+}
+
+type guardKey struct {
+	key          string
+	scope        string
+	freeAttempts int
+	maxAttempts  int
+}
+
+func (g *Guard) keys(username, ip string) []guardKey {
+	keys := []guardKey{{
+		key:          userKey(username),
+		scope:        ScopeUser,
+		freeAttempts: g.cfg.FreeAttempts,
+		maxAttempts:  g.cfg.MaxAttemptsPerUser,
+	}}
+	if ip != "" {
+		keys = append(keys, guardKey{
+			key:          ipKey(ip),
+			scope:        ScopeIP,
+			freeAttempts: g.cfg.FreeAttemptsPerIP,
+			maxAttempts:  g.cfg.MaxAttemptsPerIP,
+		})
+	}
+	return keys
+}
+
+// backoff возвращает момент, раньше которого следующая попытка не принимается:
+// после freeAttempts ошибок задержка удваивается с каждой новой ошибкой.
+func (g *Guard) backoff(attempts Attempts, freeAttempts int) time.Time {
+	if attempts.Failures <= freeAttempts || g.cfg.BaseDelay <= 0 {
+		return time.Time{}
+	}
+	if attempts.LastFailure.Before(g.now().Add(-g.cfg.Window)) {
+		return time.Time{}
+	}
+
+	delay := g.cfg.BaseDelay
+	for i := freeAttempts + 1; i < attempts.Failures; i++ {
+		delay *= 2
+		if g.cfg.MaxDelay > 0 && delay >= g.cfg.MaxDelay {
+			delay = g.cfg.MaxDelay
+			break
+		}
+	}
+
+	return attempts.LastFailure.Add(delay)
+}
@@ -0,0 +1,102 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-forum-project/auth-service/internal/config"
+)
+
+func TestChallengeAttempts(t *testing.T) {
+	ctx := context.Background()
+	// Лимит на MFA токен не зависит от login_throttle.enabled
+	guard := NewGuard(NewMemoryStore(), nil, config.LoginThrottleConfig{})
+
+	for i := 0; i < 3; i++ {
+		exhausted, err := guard.ChallengeExhausted(ctx, "token-a", 3)
+		if err != nil {
+			t.Fatalf("ChallengeExhausted: %v", err)
+		}
+		if exhausted {
+			t.Fatalf("exhausted after %d failures, want after 3", i)
+		}
+		if err := guard.ChallengeFailure(ctx, "token-a", 5*time.Minute); err != nil {
+			t.Fatalf("ChallengeFailure: %v", err)
+		}
+	}
+
+	if exhausted, _ := guard.ChallengeExhausted(ctx, "token-a", 3); !exhausted {
+		t.Errorf("token-a not exhausted after 3 failures")
+	}
+	if exhausted, _ := guard.ChallengeExhausted(ctx, "token-b", 3); exhausted {
+		t.Errorf("failures of token-a counted for token-b")
+	}
+}
+
+func TestFailureLocksUser(t *testing.T) {
+	ctx := context.Background()
+	guard := NewGuard(NewMemoryStore(), nil, config.LoginThrottleConfig{
+		Enabled:            true,
+		Window:             time.Hour,
+		MaxAttemptsPerUser: 3,
+		LockoutDuration:    time.Minute,
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := guard.Check(ctx, "Alice", "10.0.0.1"); err != nil {
+			t.Fatalf("Check before lockout: %v", err)
+		}
+		if err := guard.Failure(ctx, "Alice", "10.0.0.1"); err != nil {
+			t.Fatalf("Failure: %v", err)
+		}
+	}
+
+	var lockedErr *LockedError
+	if err := guard.Check(ctx, "alice", "10.0.0.2"); !errors.As(err, &lockedErr) || !lockedErr.Locked {
+		t.Fatalf("Check after lockout = %v, want locked", err)
+	}
+
+	if err := guard.Success(ctx, "alice"); err != nil {
+		t.Fatalf("Success: %v", err)
+	}
+	if err := guard.Check(ctx, "alice", "10.0.0.2"); err != nil {
+		t.Errorf("Check after Success = %v, want nil", err)
+	}
+}
+
+func TestCleanupKeepsActiveLocks(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	guard := NewGuard(store, nil, config.LoginThrottleConfig{
+		Enabled:            true,
+		Window:             time.Hour,
+		MaxAttemptsPerUser: 2,
+		LockoutDuration:    3 * time.Hour,
+	})
+	start := time.Now()
+	guard.now = func() time.Time { return start }
+
+	for i := 0; i < 2; i++ {
+		if err := guard.Failure(ctx, "locked", "10.0.0.1"); err != nil {
+			t.Fatalf("Failure: %v", err)
+		}
+	}
+	guard.now = func() time.Time { return start.Add(30 * time.Minute) }
+	if err := guard.Failure(ctx, "recent", "10.0.0.2"); err != nil {
+		t.Fatalf("Failure: %v", err)
+	}
+
+	guard.now = func() time.Time { return start.Add(2 * time.Hour) }
+	if err := guard.Cleanup(ctx, 5*time.Minute); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+
+	if _, ok := store.attempts[userKey("locked")]; !ok {
+		t.Error("key with an active lock was removed")
+	}
+	if _, ok := store.attempts[userKey("recent")]; ok {
+		t.Error("key without lock and failures in the window was kept")
+	}
+}
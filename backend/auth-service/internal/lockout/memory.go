@@ -0,0 +1,80 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]Attempts
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{attempts: make(map[string]Attempts)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.attempts[key], nil
+}
+
+func (s *MemoryStore) RecordFailure(ctx context.Context, key string, now, resetBefore time.Time) (Attempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleanup(now, resetBefore)
+
+	attempts := s.attempts[key]
+	if attempts.LastFailure.Before(resetBefore) {
+		attempts.Failures = 0
+	}
+	attempts.Failures++
+	attempts.LastFailure = now
+	s.attempts[key] = attempts
+
+	return attempts, nil
+}
+
+// Lock блокирует ключ и обнуляет счётчик: после окончания блокировки
+// отсчёт попыток начинается заново.
+func (s *MemoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts := s.attempts[key]
+	attempts.Failures = 0
+	attempts.LockedUntil = until
+	s.attempts[key] = attempts
+
+	return nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+func (s *MemoryStore) Cleanup(ctx context.Context, now, resetBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleanup(now, resetBefore)
+	return nil
+}
+
+// cleanup удаляет устаревшие записи, чтобы перебор случайных имён
+// не раздувал карту бесконечно.
+func (s *MemoryStore) cleanup(now, resetBefore time.Time) {
+	for key, attempts := range s.attempts {
+		if attempts.LastFailure.Before(resetBefore) && !attempts.LockedUntil.After(now) {
+			delete(s.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type PostgresStore struct {
+	Db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{Db: db}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) (Attempts, error) {
+	var (
+		attempts    Attempts
+		lockedUntil sql.NullTime
+	)
+	err := s.Db.QueryRowContext(ctx,
+		"SELECT failures, last_failure, locked_until FROM login_attempts WHERE key = $1",
+		key,
+	).Scan(&attempts.Failures, &attempts.LastFailure, &lockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Attempts{}, nil
+	}
+	if err != nil {
+		return Attempts{}, err
+	}
+
+	attempts.LockedUntil = lockedUntil.Time
+	return attempts, nil
+}
+
+// RecordFailure увеличивает счётчик одним запросом, чтобы параллельные
+// попытки с разных экземпляров не теряли инкременты.
+func (s *PostgresStore) RecordFailure(ctx context.Context, key string, now, resetBefore time.Time) (Attempts, error) {
+	var (
+		attempts    Attempts
+		lockedUntil sql.NullTime
+	)
+	err := s.Db.QueryRowContext(ctx,
+		`INSERT INTO login_attempts (key, failures, last_failure) VALUES ($1, 1, $2)
+		 ON CONFLICT (key) DO UPDATE SET
+		     failures = CASE WHEN login_attempts.last_failure < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+		     last_failure = EXCLUDED.last_failure
+		 RETURNING failures, last_failure, locked_until`,
+		key, now.UTC(), resetBefore.UTC(),
+	).Scan(&attempts.Failures, &attempts.LastFailure, &lockedUntil)
+	if err != nil {
+		return Attempts{}, err
+	}
+
+	attempts.LockedUntil = lockedUntil.Time
+	return attempts, nil
+}
+
+// Время передаётся в UTC: колонки TIMESTAMP хранятся без часового пояса.
+func (s *PostgresStore) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := s.Db.ExecContext(ctx,
+		"UPDATE login_attempts SET failures = 0, locked_until = $1 WHERE key = $2",
+		until.UTC(), key,
+	)
+	return err
+}
+
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	_, err := s.Db.ExecContext(ctx, "DELETE FROM login_attempts WHERE key = $1", key)
+	return err
+}
+
+func (s *PostgresStore) Cleanup(ctx context.Context, now, resetBefore time.Time) error {
+	_, err := s.Db.ExecContext(ctx,
+		"DELETE FROM login_attempts WHERE last_failure < $1 AND (locked_until IS NULL OR locked_until <= $2)",
+		resetBefore.UTC(), now.UTC(),
+	)
+	return err
+}
+
+// PostgresAuditor сохраняет события блокировки в login_lockouts.
+type PostgresAuditor struct {
+	Db *sql.DB
+}
+
+func NewPostgresAuditor(db *sql.DB) *PostgresAuditor {
+	return &PostgresAuditor{Db: db}
+}
+
+func (a *PostgresAuditor) RecordLockout(ctx context.Context, event Event) error {
+	_, err := a.Db.ExecContext(ctx,
+		`INSERT INTO login_lockouts (scope, username, ip, failures, locked_until)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		event.Scope, event.Username, event.IP, event.Failures, event.LockedUntil.UTC(),
+	)
+	return err
+}
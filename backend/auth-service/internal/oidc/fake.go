@@ -0,0 +1,268 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// FakeUser - пользователь, от имени которого FakeProvider подтверждает вход.
+type FakeUser struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Name              string
+}
+
+type fakeAuthorization struct {
+	user          FakeUser
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	expireAt      time.Time
+}
+
+// FakeProvider - минимальный OIDC провайдер для локальной разработки:
+// discovery, authorize (сразу подтверждает вход), token с проверкой PKCE и jwks.
+// Позволяет проверить вход через OIDC без доступа к сети.
+type FakeProvider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// User - пользователь по умолчанию. Параметр login_hint в /authorize
+	// подменяет его на пользователя с subject = login_hint.
+	User FakeUser
+
+	key   *rsa.PrivateKey
+	keyID string
+
+	mu    sync.Mutex
+	codes map[string]fakeAuthorization
+	mux   *http.ServeMux
+}
+
+func NewFakeProvider(issuer, clientID, clientSecret string, user FakeUser) (*FakeProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &FakeProvider{
+		Issuer:       strings.TrimRight(issuer, "/"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		User:         user,
+		key:          key,
+		keyID:        "fake-1",
+		codes:        make(map[string]fakeAuthorization),
+		mux:          http.NewServeMux(),
+	}
+
+	p.mux.HandleFunc("/.well-known/openid-configuration", p.handleDiscovery)
+	p.mux.HandleFunc("/authorize", p.handleAuthorize)
+	p.mux.HandleFunc("/token", p.handleToken)
+	p.mux.HandleFunc("/jwks", p.handleJWKS)
+
+	return p, nil
+}
+
+func (p *FakeProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mux.ServeHTTP(w, r)
+}
+
+// Authorize проходит страницу входа для адреса из Provider.AuthCodeURL
+// без браузера и возвращает code и state из редиректа в приложение.
+func (p *FakeProvider) Authorize(authURL string) (string, string, error) {
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	redirect, _, err := p.authorize(parsed.Query())
+	if err != nil {
+		return "", "", err
+	}
+	return redirect.Query().Get("code"), redirect.Query().Get("state"), nil
+}
+
+func (p *FakeProvider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.Issuer,
+		"authorization_endpoint":                p.Issuer + "/authorize",
+		"token_endpoint":                        p.Issuer + "/token",
+		"jwks_uri":                              p.Issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *FakeProvider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	redirect, status, err := p.authorize(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// authorize сразу подтверждает вход и возвращает адрес редиректа с code и state.
+func (p *FakeProvider) authorize(query url.Values) (*url.URL, int, error) {
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		return nil, http.StatusBadRequest, errors.New("invalid redirect_uri")
+	}
+
+	switch {
+	case query.Get("response_type") != "code":
+		return nil, http.StatusBadRequest, errors.New("unsupported response_type")
+	case query.Get("client_id") != p.ClientID:
+		return nil, http.StatusBadRequest, errors.New("unknown client_id")
+	case query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256":
+		return nil, http.StatusBadRequest, errors.New("PKCE with S256 is required")
+	}
+
+	user := p.User
+	if hint := query.Get("login_hint"); hint != "" {
+		user = FakeUser{
+			Subject:           hint,
+			Email:             hint + "@example.test",
+			EmailVerified:     true,
+			PreferredUsername: hint,
+			Name:              hint,
+		}
+	}
+
+	code, err := RandomString()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	p.mu.Lock()
+	p.codes[code] = fakeAuthorization{
+		user:          user,
+		clientID:      p.ClientID,
+		redirectURI:   redirectURI.String(),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		expireAt:      time.Now().Add(time.Minute),
+	}
+	p.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+
+	return redirectURI, http.StatusFound, nil
+}
+
+func (p *FakeProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	authorization, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	switch {
+	case r.PostForm.Get("grant_type") != "authorization_code":
+		tokenError(w, "unsupported_grant_type")
+		return
+	case !ok || time.Now().After(authorization.expireAt):
+		tokenError(w, "invalid_grant")
+		return
+	case r.PostForm.Get("client_id") != authorization.clientID:
+		tokenError(w, "invalid_client")
+		return
+	case p.ClientSecret != "" && r.PostForm.Get("client_secret") != p.ClientSecret:
+		tokenError(w, "invalid_client")
+		return
+	case r.PostForm.Get("redirect_uri") != authorization.redirectURI:
+		tokenError(w, "invalid_grant")
+		return
+	case CodeChallenge(r.PostForm.Get("code_verifier")) != authorization.codeChallenge:
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                p.Issuer,
+		"sub":                authorization.user.Subject,
+		"aud":                authorization.clientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"nonce":              authorization.nonce,
+		"email":              authorization.user.Email,
+		"email_verified":     authorization.user.EmailVerified,
+		"preferred_username": authorization.user.PreferredUsername,
+		"name":               authorization.user.Name,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.keyID
+
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	accessToken, err := RandomString()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (p *FakeProvider) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": p.keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.PublicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.PublicKey.E)).Bytes()),
+		}},
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,219 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go-forum-project/auth-service/internal/config"
+)
+
+const (
+	testClientID     = "forum"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://forum.test/oidc/callback"
+)
+
+var testUser = FakeUser{
+	Subject:           "user-1",
+	Email:             "alice@example.test",
+	EmailVerified:     true,
+	PreferredUsername: "alice",
+	Name:              "Alice",
+}
+
+// newTestProvider поднимает FakeProvider на httptest и настраивает на него Provider.
+func newTestProvider(t *testing.T) (*FakeProvider, *Provider) {
+	t.Helper()
+
+	var fake *FakeProvider
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	fake, err := NewFakeProvider(server.URL, testClientID, testClientSecret, testUser)
+	if err != nil {
+		t.Fatalf("NewFakeProvider: %v", err)
+	}
+
+	provider := NewProvider(config.OIDCProviderConfig{
+		Name:         "fake",
+		Issuer:       server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+	return fake, provider
+}
+
+// authorize проходит страницу входа провайдера и возвращает code и state.
+func authorize(t *testing.T, fake *FakeProvider, authURL string) (string, string) {
+	t.Helper()
+
+	code, state, err := fake.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	return code, state
+}
+
+func TestExchangeWithPKCE(t *testing.T) {
+	ctx := context.Background()
+	fake, provider := newTestProvider(t)
+
+	verifier, _ := RandomString()
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", CodeChallenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	code, state := authorize(t, fake, authURL)
+	if state != "state-1" {
+		t.Errorf("state = %q, want state-1", state)
+	}
+
+	identity, err := provider.Exchange(ctx, code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	want := Identity{
+		Subject:           testUser.Subject,
+		Email:             testUser.Email,
+		EmailVerified:     true,
+		PreferredUsername: testUser.PreferredUsername,
+		Name:              testUser.Name,
+	}
+	if *identity != want {
+		t.Errorf("identity = %+v, want %+v", *identity, want)
+	}
+
+	// code одноразовый
+	if _, err := provider.Exchange(ctx, code, verifier, "nonce-1"); err == nil {
+		t.Errorf("second Exchange with the same code succeeded")
+	}
+}
+
+func TestExchangeRejects(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		verifier func(verifier string) string
+		nonce    string
+		wantErr  error
+	}{
+		{
+			name:     "wrong code verifier",
+			verifier: func(string) string { return "another-verifier-another-verifier-another-verifier" },
+			nonce:    "nonce-1",
+		},
+		{
+			name:     "nonce mismatch",
+			verifier: func(verifier string) string { return verifier },
+			nonce:    "nonce-2",
+			wantErr:  ErrNonceMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, provider := newTestProvider(t)
+
+			verifier, _ := RandomString()
+			authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", CodeChallenge(verifier))
+			if err != nil {
+				t.Fatalf("AuthCodeURL: %v", err)
+			}
+			code, _ := authorize(t, fake, authURL)
+
+			_, err = provider.Exchange(ctx, code, tt.verifier(verifier), tt.nonce)
+			if err == nil {
+				t.Fatalf("Exchange succeeded, want error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Exchange error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	ctx := context.Background()
+	fake, provider := newTestProvider(t)
+	if _, err := provider.discover(ctx); err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	validClaims := func() jwt.MapClaims {
+		now := time.Now()
+		return jwt.MapClaims{
+			"iss":   fake.Issuer,
+			"sub":   "user-1",
+			"aud":   testClientID,
+			"iat":   now.Unix(),
+			"exp":   now.Add(5 * time.Minute).Unix(),
+			"nonce": "nonce-1",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		claims  func(claims jwt.MapClaims)
+		key     *rsa.PrivateKey
+		wantErr error
+	}{
+		{name: "valid"},
+		{name: "foreign signature", key: otherKey, wantErr: ErrInvalidIDToken},
+		{name: "wrong audience", claims: func(c jwt.MapClaims) { c["aud"] = "other-client" }, wantErr: ErrInvalidIDToken},
+		{name: "wrong issuer", claims: func(c jwt.MapClaims) { c["iss"] = "http://evil.test" }, wantErr: ErrInvalidIDToken},
+		{
+			name:    "expired",
+			claims:  func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-10 * time.Minute).Unix() },
+			wantErr: ErrInvalidIDToken,
+		},
+		{name: "no expiry", claims: func(c jwt.MapClaims) { delete(c, "exp") }, wantErr: ErrInvalidIDToken},
+		{name: "no subject", claims: func(c jwt.MapClaims) { delete(c, "sub") }, wantErr: ErrInvalidIDToken},
+		{name: "nonce mismatch", claims: func(c jwt.MapClaims) { c["nonce"] = "nonce-2" }, wantErr: ErrNonceMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+			key := fake.key
+			if tt.key != nil {
+				key = tt.key
+			}
+
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			token.Header["kid"] = fake.keyID
+			idToken, err := token.SignedString(key)
+			if err != nil {
+				t.Fatalf("SignedString: %v", err)
+			}
+
+			_, err = provider.verify(ctx, idToken, "nonce-1")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("verify error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package password
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+
+	"go-forum-project/auth-service/internal/config"
+	"go-forum-project/auth-service/internal/entity"
+)
+
+// bcrypt не принимает пароли длиннее 72 байт.
+const bcryptMaxLength = 72
+
+type Policy struct {
+	minLength      int
+	maxLength      int
+	requireUpper   bool
+	requireLower   bool
+	requireDigit   bool
+	requireSpecial bool
+	minEntropyBits float64
+	breached       map[string]struct{}
+}
+
+func NewPolicy(cfg config.PasswordPolicyConfig) (*Policy, error) {
+	policy := &Policy{
+		minLength:      cfg.MinLength,
+		maxLength:      cfg.MaxLength,
+		requireUpper:   cfg.RequireUpper,
+		requireLower:   cfg.RequireLower,
+		requireDigit:   cfg.RequireDigit,
+		requireSpecial: cfg.RequireSpecial,
+		minEntropyBits: cfg.MinEntropyBits,
+		breached:       make(map[string]struct{}),
+	}
+
+	if policy.minLength <= 0 {
+		policy.minLength = 1
+	}
+	if policy.maxLength <= 0 || policy.maxLength > bcryptMaxLength {
+		policy.maxLength = bcryptMaxLength
+	}
+
+	if cfg.BreachedListPath != "" {
+		if err := policy.loadBreached(cfg.BreachedListPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+func (p *Policy) loadBreached(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.breached[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	return nil
+}
+
+// Check проверяет пароль и возвращает нарушения для поля field.
+// Пустой результат означает, что пароль подходит.
+func (p *Policy) Check(field, password, username string) []entity.FieldViolation {
+	var violations []entity.FieldViolation
+	violate := func(description string) {
+		violations = append(violations, entity.FieldViolation{Field: field, Description: description})
+	}
+
+	length := len([]rune(password))
+	if length < p.minLength {
+		violate(fmt.Sprintf("must be at least %d characters long", p.minLength))
+	}
+	if len(password) > p.maxLength {
+		violate(fmt.Sprintf("must be at most %d bytes long", p.maxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsControl(r):
+		default:
+			hasSpecial = true
+		}
+	}
+
+	if p.requireUpper && !hasUpper {
+		violate("must contain an uppercase letter")
+	}
+	if p.requireLower && !hasLower {
+		violate("must contain a lowercase letter")
+	}
+	if p.requireDigit && !hasDigit {
+		violate("must contain a digit")
+	}
+	if p.requireSpecial && !hasSpecial {
+		violate("must contain a special character")
+	}
+
+	if length > 0 && p.minEntropyBits > 0 && Entropy(password) < p.minEntropyBits {
+		violate("is too weak")
+	}
+
+	lowered := strings.ToLower(password)
+	if username != "" && strings.Contains(lowered, strings.ToLower(username)) {
+		violate("must not contain the username")
+	}
+	if _, ok := p.breached[lowered]; ok {
+		violate("is too common, it appears in a list of breached passwords")
+	}
+
+	return violations
+}
+
+// Entropy грубо оценивает стойкость пароля в битах: размер алфавита по
+// встреченным классам символов, повторы подряд идущих символов не учитываются.
+func Entropy(password string) float64 {
+	var pool int
+	var hasUpper, hasLower, hasDigit, hasSpecial, hasOther bool
+	var effectiveLength int
+	var prev rune = -1
+
+	for _, r := range password {
+		if r != prev {
+			effectiveLength++
+		}
+		prev = r
+
+		switch {
+		case r < unicode.MaxASCII && unicode.IsUpper(r):
+			hasUpper = true
+		case r < unicode.MaxASCII && unicode.IsLower(r):
+			hasLower = true
+		case r < unicode.MaxASCII && unicode.IsDigit(r):
+			hasDigit = true
+		case r < unicode.MaxASCII:
+			hasSpecial = true
+		default:
+			hasOther = true
+		}
+	}
+
+	if hasUpper {
+		pool += 26
+	}
+	if hasLower {
+		pool += 26
+	}
+	if hasDigit {
+		pool += 10
+	}
+	if hasSpecial {
+		pool += 33
+	}
+	if hasOther {
+		pool += 100
+	}
+	if pool == 0 {
+		return 0
+	}
+
+	return float64(effectiveLength) * math.Log2(float64(pool))
+}
@@ -0,0 +1,203 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/events"
+)
+
+type AccountRepository interface {
+	// AnonymizeUser стирает личные данные и все способы входа, оставляя
+	// строку users, на которую ссылаются остальные таблицы. В той же
+	// транзакции запоминает сервисы, где данные ещё предстоит стереть.
+	AnonymizeUser(ctx context.Context, userID int, placeholder string, services []string) error
+
+	ListPendingErasures(ctx context.Context, limit int) ([]*entity.PendingErasure, error)
+	FinishErasure(ctx context.Context, userID int, service string) error
+	FailErasure(ctx context.Context, userID int, service, reason string) error
+
+	CreateExport(ctx context.Context, export *entity.DataExport) error
+	FinishExport(ctx context.Context, export *entity.DataExport) error
+	GetExport(ctx context.Context, userID, id int) (*entity.DataExport, error)
+	HasPendingExport(ctx context.Context, userID int) (bool, error)
+	// DeleteExpiredExports удаляет устаревшие выгрузки и возвращает имена их файлов.
+	DeleteExpiredExports(ctx context.Context) ([]string, error)
+	DeleteUserExports(ctx context.Context, userID int) ([]string, error)
+}
+
+type AccountRepo struct {
+	Db *sql.DB
+}
+
+func NewAccountRepo(db *sql.DB) *AccountRepo {
+	return &AccountRepo{Db: db}
+}
+
+func (r *AccountRepo) AnonymizeUser(ctx context.Context, userID int, placeholder string, services []string) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{
+		"refresh_tokens", "personal_access_tokens", "oidc_identities", "mfa_recovery_codes",
+		"password_reset_tokens", "email_verification_tokens", "oidc_login_states", "username_history",
+	} {
+		column := "user_id"
+		if table == "oidc_login_states" {
+			column = "link_user_id"
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s = $1", table, column), userID); err != nil {
+			return fmt.Errorf("failed to clean %s: %w", table, err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE users SET username = $2, username_skeleton = $2, email = NULL, email_verified = FALSE,
+			password = '', mfa_enabled = FALSE, mfa_secret = NULL, display_name = NULL, bio = NULL,
+			avatar_url = NULL, location = NULL, deleted_at = NOW(), updated_at = NOW()
+		 WHERE id = $1`,
+		userID, placeholder,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	for _, service := range services {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO pending_erasures (user_id, service) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			userID, service,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to schedule erasure: %w", err)
+		}
+	}
+
+	if err := events.Enqueue(ctx, tx, events.UserDeleted{UserID: userID}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *AccountRepo) ListPendingErasures(ctx context.Context, limit int) ([]*entity.PendingErasure, error) {
+	rows, err := r.Db.QueryContext(ctx,
+		`SELECT user_id, service, attempts, created_at FROM pending_erasures
+		 ORDER BY attempts, created_at LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var erasures []*entity.PendingErasure
+	for rows.Next() {
+		var erasure entity.PendingErasure
+		if err := rows.Scan(&erasure.UserID, &erasure.Service, &erasure.Attempts, &erasure.CreatedAt); err != nil {
+			return nil, err
+		}
+		erasures = append(erasures, &erasure)
+	}
+
+	return erasures, rows.Err()
+}
+
+func (r *AccountRepo) FinishErasure(ctx context.Context, userID int, service string) error {
+	_, err := r.Db.ExecContext(ctx,
+		"DELETE FROM pending_erasures WHERE user_id = $1 AND service = $2",
+		userID, service,
+	)
+	return err
+}
+
+func (r *AccountRepo) FailErasure(ctx context.Context, userID int, service, reason string) error {
+	_, err := r.Db.ExecContext(ctx,
+		"UPDATE pending_erasures SET attempts = attempts + 1, last_error = $3 WHERE user_id = $1 AND service = $2",
+		userID, service, reason,
+	)
+	return err
+}
+
+func (r *AccountRepo) CreateExport(ctx context.Context, export *entity.DataExport) error {
+	return r.Db.QueryRowContext(ctx,
+		"INSERT INTO data_exports (user_id, status) VALUES ($1, $2) RETURNING id, created_at",
+		export.UserID, export.Status,
+	).Scan(&export.ID, &export.CreatedAt)
+}
+
+func (r *AccountRepo) FinishExport(ctx context.Context, export *entity.DataExport) error {
+	_, err := r.Db.ExecContext(ctx,
+		`UPDATE data_exports SET status = $2, file_name = NULLIF($3, ''), error = NULLIF($4, ''),
+			completed_at = $5, expire_at = $6
+		 WHERE id = $1`,
+		export.ID, export.Status, export.FileName, export.Error, export.CompletedAt.UTC(), export.ExpiresAt.UTC(),
+	)
+	return err
+}
+
+func (r *AccountRepo) GetExport(ctx context.Context, userID, id int) (*entity.DataExport, error) {
+	var (
+		export      entity.DataExport
+		completedAt sql.NullTime
+		expireAt    sql.NullTime
+	)
+	err := r.Db.QueryRowContext(ctx,
+		`SELECT id, user_id, status, COALESCE(file_name, ''), COALESCE(error, ''), created_at, completed_at, expire_at
+		 FROM data_exports WHERE id = $1 AND user_id = $2`,
+		id, userID,
+	).Scan(&export.ID, &export.UserID, &export.Status, &export.FileName, &export.Error, &export.CreatedAt,
+		&completedAt, &expireAt)
+	if err != nil {
+		return nil, err
+	}
+
+	export.CompletedAt = completedAt.Time
+	export.ExpiresAt = expireAt.Time
+	return &export, nil
+}
+
+func (r *AccountRepo) HasPendingExport(ctx context.Context, userID int) (bool, error) {
+	var exists bool
+	err := r.Db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM data_exports WHERE user_id = $1 AND status = $2)",
+		userID, entity.ExportPending,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *AccountRepo) DeleteExpiredExports(ctx context.Context) ([]string, error) {
+	return r.deleteExports(ctx, "DELETE FROM data_exports WHERE expire_at < $1 RETURNING COALESCE(file_name, '')",
+		time.Now().UTC())
+}
+
+func (r *AccountRepo) DeleteUserExports(ctx context.Context, userID int) ([]string, error) {
+	return r.deleteExports(ctx, "DELETE FROM data_exports WHERE user_id = $1 RETURNING COALESCE(file_name, '')",
+		userID)
+}
+
+func (r *AccountRepo) deleteExports(ctx context.Context, query string, arg any) ([]string, error) {
+	rows, err := r.Db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+
+	return files, rows.Err()
+}
@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/events"
+)
+
+type AuditRepository interface {
+	// StoreSynced сохраняет записи, забранные из журнала service. Уже
+	// сохранённые записи пропускаются.
+	StoreSynced(ctx context.Context, service string, entries []events.AuditRecorded) error
+	// LastSyncedID возвращает ID последней забранной из service записи.
+	LastSyncedID(ctx context.Context, service string) (int64, error)
+	List(ctx context.Context, filter entity.AuditFilter, limit, offset int) ([]entity.AuditEntry, int, error)
+}
+
+type AuditRepo struct {
+	Db *sql.DB
+}
+
+func NewAuditRepo(db *sql.DB) *AuditRepo {
+	return &AuditRepo{Db: db}
+}
+
+// auditService - значение service у записей самого auth-service
+const auditService = "auth"
+
+// recordAudit пишет действие auth-service в журнал в транзакции самого действия.
+func recordAudit(ctx context.Context, tx *sql.Tx, entry events.AuditRecorded) error {
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = time.Now()
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (service, actor_id, actor_role, action, target_type, target_id, reason,
+			before_state, after_state, occurred_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, '')::jsonb, NULLIF($9, '')::jsonb, $10)`,
+		auditService, entry.ActorID, entry.ActorRole, entry.Action, entry.TargetType, entry.TargetID, entry.Reason,
+		string(entry.Before), string(entry.After), entry.OccurredAt.UTC(),
+	)
+	return err
+}
+
+func (r *AuditRepo) StoreSynced(ctx context.Context, service string, entries []events.AuditRecorded) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, entry := range entries {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO audit_log (service, source_id, actor_id, actor_role, action, target_type, target_id, reason,
+				before_state, after_state, occurred_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, '')::jsonb, NULLIF($10, '')::jsonb, $11)
+			 ON CONFLICT (service, source_id) DO NOTHING`,
+			service, entry.ID, entry.ActorID, entry.ActorRole, entry.Action, entry.TargetType, entry.TargetID,
+			entry.Reason, string(entry.Before), string(entry.After), entry.OccurredAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *AuditRepo) LastSyncedID(ctx context.Context, service string) (int64, error) {
+	var lastID int64
+	err := r.Db.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(source_id), 0) FROM audit_log WHERE service = $1", service,
+	).Scan(&lastID)
+	return lastID, err
+}
+
+// List возвращает записи от новых к старым вместе с общим числом найденных.
+func (r *AuditRepo) List(ctx context.Context, filter entity.AuditFilter, limit,
+	offset int) ([]entity.AuditEntry, int, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.ActorID != 0 {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.Service != "" {
+		add("service = $%d", filter.Service)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if !filter.Since.IsZero() {
+		add("occurred_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		add("occurred_at < $%d", filter.Until)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.Db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`SELECT id, service, COALESCE(source_id, 0), actor_id, actor_role, action, target_type, target_id, reason,
+			COALESCE(before_state::text, ''), COALESCE(after_state::text, ''), occurred_at, recorded_at
+		FROM audit_log%s ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		where, len(args)+1, len(args)+2)
+	rows, err := r.Db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []entity.AuditEntry{}
+	for rows.Next() {
+		var e entity.AuditEntry
+		err := rows.Scan(&e.ID, &e.Service, &e.SourceID, &e.ActorID, &e.ActorRole, &e.Action, &e.TargetType,
+			&e.TargetID, &e.Reason, &e.Before, &e.After, &e.OccurredAt, &e.RecordedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, total, rows.Err()
+}
@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/events"
+)
+
+type BanRepository interface {
+	// CreateBan и RevokeBans пишут audit в журнал в той же транзакции.
+	CreateBan(ctx context.Context, ban *entity.Ban, audit events.AuditRecorded) error
+	// GetActiveBan возвращает nil, если действующей блокировки нет.
+	GetActiveBan(ctx context.Context, userID int) (*entity.Ban, error)
+	RevokeBans(ctx context.Context, userID, revokedBy int, audit events.AuditRecorded) (bool, error)
+}
+
+type BanRepo struct {
+	Db *sql.DB
+}
+
+func NewBanRepo(db *sql.DB) *BanRepo {
+	return &BanRepo{Db: db}
+}
+
+const activeBanCondition = "revoked_at IS NULL AND (expire_at IS NULL OR expire_at > NOW())"
+
+func (r *BanRepo) CreateBan(ctx context.Context, ban *entity.Ban, audit events.AuditRecorded) error {
+	var expireAt sql.NullTime
+	if !ban.ExpiresAt.IsZero() {
+		expireAt = sql.NullTime{Time: ban.ExpiresAt.UTC(), Valid: true}
+	}
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO user_bans (user_id, reason, banned_by, expire_at) VALUES ($1, $2, NULLIF($3, 0), $4)
+		 RETURNING id, created_at`,
+		ban.UserID, ban.Reason, ban.BannedBy, expireAt,
+	).Scan(&ban.ID, &ban.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	err = events.Enqueue(ctx, tx, events.UserBanned{
+		UserID:    ban.UserID,
+		Reason:    ban.Reason,
+		BannedBy:  ban.BannedBy,
+		ExpiresAt: ban.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetActiveBan при нескольких действующих блокировках берёт самую долгую.
+func (r *BanRepo) GetActiveBan(ctx context.Context, userID int) (*entity.Ban, error) {
+	var (
+		ban      entity.Ban
+		expireAt sql.NullTime
+	)
+	err := r.Db.QueryRowContext(ctx,
+		`SELECT id, user_id, reason, COALESCE(banned_by, 0), expire_at, created_at FROM user_bans
+		 WHERE user_id = $1 AND `+activeBanCondition+`
+		 ORDER BY expire_at DESC NULLS FIRST LIMIT 1`,
+		userID,
+	).Scan(&ban.ID, &ban.UserID, &ban.Reason, &ban.BannedBy, &expireAt, &ban.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ban.ExpiresAt = expireAt.Time
+	return &ban, nil
+}
+
+func (r *BanRepo) RevokeBans(ctx context.Context, userID, revokedBy int, audit events.AuditRecorded) (bool, error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE user_bans SET revoked_at = NOW(), revoked_by = NULLIF($2, 0)
+		 WHERE user_id = $1 AND `+activeBanCondition,
+		userID, revokedBy,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return false, err
+	}
+
+	if err := events.Enqueue(ctx, tx, events.UserUnbanned{UserID: userID, RevokedBy: revokedBy}); err != nil {
+		return false, err
+	}
+	if err := recordAudit(ctx, tx, audit); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
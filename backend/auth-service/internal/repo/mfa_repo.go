@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type MFARepository interface {
+	SetMFASecret(ctx context.Context, userID int, secret string) error
+	EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error
+	DisableMFA(ctx context.Context, userID int) error
+	UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+}
+
+type MFARepo struct {
+	Db *sql.DB
+}
+
+func NewMFARepo(db *sql.DB) *MFARepo {
+	return &MFARepo{Db: db}
+}
+
+// SetMFASecret сохраняет секрет, который ещё не подтверждён кодом.
+func (r *MFARepo) SetMFASecret(ctx context.Context, userID int, secret string) error {
+	_, err := r.Db.ExecContext(ctx,
+		"UPDATE users SET mfa_secret = $1, updated_at = NOW() WHERE id = $2 AND NOT mfa_enabled",
+		secret, userID,
+	)
+	return err
+}
+
+// EnableMFA включает MFA и заменяет коды восстановления в одной транзакции.
+func (r *MFARepo) EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE users SET mfa_enabled = TRUE, mfa_last_step = $1, updated_at = NOW() WHERE id = $2",
+		step, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enable mfa: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM mfa_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)",
+			userID, hash,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save recovery code: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *MFARepo) DisableMFA(ctx context.Context, userID int) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE users SET mfa_enabled = FALSE, mfa_secret = NULL, mfa_last_step = 0, updated_at = NOW() WHERE id = $1",
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to disable mfa: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM mfa_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// UseTOTPStep запоминает использованный шаг TOTP. Возвращает false, если код
+// этого или более позднего шага уже применялся.
+func (r *MFARepo) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	result, err := r.Db.ExecContext(ctx,
+		"UPDATE users SET mfa_last_step = $1 WHERE id = $2 AND mfa_last_step < $1",
+		step, userID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+func (r *MFARepo) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	result, err := r.Db.ExecContext(ctx,
+		`UPDATE mfa_recovery_codes SET used_at = NOW()
+		 WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`,
+		userID, codeHash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
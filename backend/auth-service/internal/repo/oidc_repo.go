@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"go-forum-project/auth-service/internal/entity"
+)
+
+var ErrIdentityLinked = errors.New("identity already linked")
+
+type OIDCRepository interface {
+	CreateLoginState(ctx context.Context, stateHash string, state *entity.OIDCLoginState) error
+	ConsumeLoginState(ctx context.Context, stateHash string) (*entity.OIDCLoginState, error)
+	FindIdentity(ctx context.Context, provider, subject string) (*entity.OIDCIdentity, error)
+	LinkIdentity(ctx context.Context, identity *entity.OIDCIdentity) error
+	TouchIdentity(ctx context.Context, provider, subject string) error
+	ListIdentities(ctx context.Context, userID int) ([]*entity.OIDCIdentity, error)
+}
+
+type OIDCRepo struct {
+	Db *sql.DB
+}
+
+func NewOIDCRepo(db *sql.DB) *OIDCRepo {
+	return &OIDCRepo{Db: db}
+}
+
+func (r *OIDCRepo) CreateLoginState(ctx context.Context, stateHash string, state *entity.OIDCLoginState) error {
+	// Заодно чистим брошенные входы
+	if _, err := r.Db.ExecContext(ctx, "DELETE FROM oidc_login_states WHERE expire_at < NOW()"); err != nil {
+		return err
+	}
+
+	_, err := r.Db.ExecContext(ctx,
+		`INSERT INTO oidc_login_states (state_hash, provider, code_verifier, nonce, link_user_id, expire_at)
+		 VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6)`,
+		stateHash, state.Provider, state.CodeVerifier, state.Nonce, state.LinkUserID, state.ExpireAt.UTC(),
+	)
+	return err
+}
+
+// ConsumeLoginState удаляет state и возвращает его, поэтому каждый state
+// можно использовать только один раз.
+func (r *OIDCRepo) ConsumeLoginState(ctx context.Context, stateHash string) (*entity.OIDCLoginState, error) {
+	var state entity.OIDCLoginState
+	err := r.Db.QueryRowContext(ctx,
+		`DELETE FROM oidc_login_states WHERE state_hash = $1 AND expire_at > NOW()
+		 RETURNING provider, code_verifier, nonce, COALESCE(link_user_id, 0), expire_at`,
+		stateHash,
+	).Scan(&state.Provider, &state.CodeVerifier, &state.Nonce, &state.LinkUserID, &state.ExpireAt)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *OIDCRepo) FindIdentity(ctx context.Context, provider, subject string) (*entity.OIDCIdentity, error) {
+	var identity entity.OIDCIdentity
+	err := r.Db.QueryRowContext(ctx,
+		`SELECT user_id, provider, subject, COALESCE(email, '') FROM oidc_identities
+		 WHERE provider = $1 AND subject = $2`,
+		provider, subject,
+	).Scan(&identity.UserID, &identity.Provider, &identity.Subject, &identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *OIDCRepo) LinkIdentity(ctx context.Context, identity *entity.OIDCIdentity) error {
+	_, err := r.Db.ExecContext(ctx,
+		`INSERT INTO oidc_identities (user_id, provider, subject, email, last_login_at)
+		 VALUES ($1, $2, $3, NULLIF($4, ''), NOW())`,
+		identity.UserID, identity.Provider, identity.Subject, identity.Email,
+	)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrIdentityLinked
+	}
+	return err
+}
+
+func (r *OIDCRepo) TouchIdentity(ctx context.Context, provider, subject string) error {
+	_, err := r.Db.ExecContext(ctx,
+		"UPDATE oidc_identities SET last_login_at = NOW() WHERE provider = $1 AND subject = $2",
+		provider, subject,
+	)
+	return err
+}
+
+func (r *OIDCRepo) ListIdentities(ctx context.Context, userID int) ([]*entity.OIDCIdentity, error) {
+	rows, err := r.Db.QueryContext(ctx,
+		`SELECT user_id, provider, subject, COALESCE(email, '') FROM oidc_identities
+		 WHERE user_id = $1 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []*entity.OIDCIdentity
+	for rows.Next() {
+		var identity entity.OIDCIdentity
+		if err := rows.Scan(&identity.UserID, &identity.Provider, &identity.Subject, &identity.Email); err != nil {
+			return nil, err
+		}
+		identities = append(identities, &identity)
+	}
+
+	return identities, rows.Err()
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"go-forum-project/auth-service/internal/entity"
+)
+
+type PersonalTokenRepository interface {
+	CreatePersonalToken(ctx context.Context, token *entity.PersonalToken, tokenHash string) error
+	ListPersonalTokens(ctx context.Context, userID int) ([]*entity.PersonalToken, error)
+	CountPersonalTokens(ctx context.Context, userID int) (int, error)
+	FindPersonalToken(ctx context.Context, tokenHash string) (*entity.PersonalToken, error)
+	TouchPersonalToken(ctx context.Context, id int) error
+	RevokePersonalToken(ctx context.Context, userID, id int) (bool, error)
+}
+
+type PersonalTokenRepo struct {
+	Db *sql.DB
+}
+
+func NewPersonalTokenRepo(db *sql.DB) *PersonalTokenRepo {
+	return &PersonalTokenRepo{Db: db}
+}
+
+const personalTokenColumns = "id, user_id, name, token_prefix, scopes, expire_at, last_used_at, created_at"
+
+func scanPersonalToken(row rowScanner) (*entity.PersonalToken, error) {
+	var (
+		token      entity.PersonalToken
+		expireAt   sql.NullTime
+		lastUsedAt sql.NullTime
+	)
+	err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.Prefix, pq.Array(&token.Scopes),
+		&expireAt, &lastUsedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	token.ExpiresAt = expireAt.Time
+	token.LastUsedAt = lastUsedAt.Time
+	return &token, nil
+}
+
+func (r *PersonalTokenRepo) CreatePersonalToken(ctx context.Context, token *entity.PersonalToken,
+	tokenHash string) error {
+	var expireAt sql.NullTime
+	if !token.ExpiresAt.IsZero() {
+		expireAt = sql.NullTime{Time: token.ExpiresAt.UTC(), Valid: true}
+	}
+
+	return r.Db.QueryRowContext(ctx,
+		`INSERT INTO personal_access_tokens (user_id, name, token_prefix, token_hash, scopes, expire_at)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		token.UserID, token.Name, token.Prefix, tokenHash, pq.Array(token.Scopes), expireAt,
+	).Scan(&token.ID, &token.CreatedAt)
+}
+
+// ListPersonalTokens возвращает только действующие токены.
+func (r *PersonalTokenRepo) ListPersonalTokens(ctx context.Context, userID int) ([]*entity.PersonalToken, error) {
+	rows, err := r.Db.QueryContext(ctx,
+		`SELECT `+personalTokenColumns+` FROM personal_access_tokens
+		 WHERE user_id = $1 AND revoked_at IS NULL AND (expire_at IS NULL OR expire_at > NOW())
+		 ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*entity.PersonalToken
+	for rows.Next() {
+		token, err := scanPersonalToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *PersonalTokenRepo) CountPersonalTokens(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.Db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM personal_access_tokens
+		 WHERE user_id = $1 AND revoked_at IS NULL AND (expire_at IS NULL OR expire_at > NOW())`,
+		userID,
+	).Scan(&count)
+	return count, err
+}
+
+func (r *PersonalTokenRepo) FindPersonalToken(ctx context.Context, tokenHash string) (*entity.PersonalToken, error) {
+	return scanPersonalToken(r.Db.QueryRowContext(ctx,
+		`SELECT `+personalTokenColumns+` FROM personal_access_tokens
+		 WHERE token_hash = $1 AND revoked_at IS NULL AND (expire_at IS NULL OR expire_at > NOW())`,
+		tokenHash,
+	))
+}
+
+// TouchPersonalToken обновляет last_used_at не чаще раза в минуту,
+// чтобы активный бот не писал в БД на каждый запрос.
+func (r *PersonalTokenRepo) TouchPersonalToken(ctx context.Context, id int) error {
+	_, err := r.Db.ExecContext(ctx,
+		`UPDATE personal_access_tokens SET last_used_at = NOW()
+		 WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`,
+		id,
+	)
+	return err
+}
+
+func (r *PersonalTokenRepo) RevokePersonalToken(ctx context.Context, userID, id int) (bool, error) {
+	result, err := r.Db.ExecContext(ctx,
+		"UPDATE personal_access_tokens SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL",
+		id, userID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
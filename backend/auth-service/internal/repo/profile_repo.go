@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"go-forum-project/auth-service/internal/entity"
+)
+
+type ProfileRepository interface {
+	GetProfileByUsername(ctx context.Context, username string) (*entity.Profile, error)
+	GetProfileByID(ctx context.Context, userID int) (*entity.Profile, error)
+	UpdateProfile(ctx context.Context, userID int, update entity.ProfileUpdate) error
+}
+
+type ProfileRepo struct {
+	Db *sql.DB
+}
+
+func NewProfileRepo(db *sql.DB) *ProfileRepo {
+	return &ProfileRepo{Db: db}
+}
+
+const profileColumns = `id, username, COALESCE(display_name, ''), COALESCE(bio, ''), COALESCE(avatar_url, ''),
+	COALESCE(location, ''), created_at`
+
+func scanProfile(row *sql.Row) (*entity.Profile, error) {
+	var profile entity.Profile
+	err := row.Scan(&profile.UserID, &profile.Username, &profile.DisplayName, &profile.Bio, &profile.AvatarURL,
+		&profile.Location, &profile.JoinedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *ProfileRepo) GetProfileByUsername(ctx context.Context, username string) (*entity.Profile, error) {
+	return scanProfile(r.Db.QueryRowContext(ctx,
+		"SELECT "+profileColumns+" FROM users WHERE LOWER(username) = LOWER($1)", username))
+}
+
+func (r *ProfileRepo) GetProfileByID(ctx context.Context, userID int) (*entity.Profile, error) {
+	return scanProfile(r.Db.QueryRowContext(ctx, "SELECT "+profileColumns+" FROM users WHERE id = $1", userID))
+}
+
+// UpdateProfile меняет только переданные поля. Пустая строка очищает поле.
+func (r *ProfileRepo) UpdateProfile(ctx context.Context, userID int, update entity.ProfileUpdate) error {
+	_, err := r.Db.ExecContext(ctx,
+		`UPDATE users SET
+		     display_name = CASE WHEN $1 THEN NULLIF($2, '') ELSE display_name END,
+		     bio = CASE WHEN $3 THEN NULLIF($4, '') ELSE bio END,
+		     avatar_url = CASE WHEN $5 THEN NULLIF($6, '') ELSE avatar_url END,
+		     location = CASE WHEN $7 THEN NULLIF($8, '') ELSE location END,
+		     updated_at = NOW()
+		 WHERE id = $9`,
+		update.DisplayName != nil, deref(update.DisplayName),
+		update.Bio != nil, deref(update.Bio),
+		update.AvatarURL != nil, deref(update.AvatarURL),
+		update.Location != nil, deref(update.Location),
+		userID,
+	)
+	return err
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
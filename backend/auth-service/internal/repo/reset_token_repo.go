@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type ResetTokenRepository interface {
+	CreateResetToken(ctx context.Context, userID int, tokenHash string, expireAt time.Time) error
+	FindResetToken(ctx context.Context, tokenHash string) (int, error)
+	ConsumeResetToken(ctx context.Context, tokenHash string) (int, error)
+	DeleteUserResetTokens(ctx context.Context, userID int) error
+}
+
+type ResetTokenRepo struct {
+	Db *sql.DB
+}
+
+func NewResetTokenRepo(db *sql.DB) *ResetTokenRepo {
+	return &ResetTokenRepo{Db: db}
+}
+
+func (r *ResetTokenRepo) CreateResetToken(ctx context.Context, userID int, tokenHash string, expireAt time.Time) error {
+	_, err := r.Db.ExecContext(ctx,
+		"INSERT INTO password_reset_tokens (user_id, token_hash, expire_at) VALUES ($1, $2, $3)",
+		userID, tokenHash, expireAt.UTC(),
+	)
+	return err
+}
+
+// FindResetToken возвращает владельца действующего токена, не помечая его использованным.
+func (r *ResetTokenRepo) FindResetToken(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := r.Db.QueryRowContext(ctx,
+		"SELECT user_id FROM password_reset_tokens WHERE token_hash = $1 AND used_at IS NULL AND expire_at > NOW()",
+		tokenHash,
+	).Scan(&userID)
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// ConsumeResetToken одним запросом помечает токен использованным и возвращает
+// владельца, поэтому один и тот же токен нельзя применить дважды.
+func (r *ResetTokenRepo) ConsumeResetToken(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := r.Db.QueryRowContext(ctx,
+		`UPDATE password_reset_tokens SET used_at = NOW()
+		 WHERE token_hash = $1 AND used_at IS NULL AND expire_at > NOW()
+		 RETURNING user_id`,
+		tokenHash,
+	).Scan(&userID)
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (r *ResetTokenRepo) DeleteUserResetTokens(ctx context.Context, userID int) error {
+	_, err := r.Db.ExecContext(ctx,
+		"DELETE FROM password_reset_tokens WHERE user_id = $1 AND used_at IS NULL",
+		userID,
+	)
+	return err
+}
@@ -14,6 +14,7 @@ type TokenRepository interface {
 	CreateRefreshToken(ctx context.Context, userID int, tokenHash string, expireAt time.Time) error
 	FindRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
 	DeleteRefreshToken(ctx context.Context, tokenHash string) error
+	DeleteUserRefreshTokens(ctx context.Context, userID int, exceptTokenHash string) error
 }
 
 type TokenRepo struct {
@@ -27,7 +28,7 @@ func NewTokenRepo(db *sql.DB) *TokenRepo {
 func (r *TokenRepo) CreateRefreshToken(ctx context.Context, userID int, tokenHash string, expireAt time.Time) error {
 	_, err := r.Db.ExecContext(ctx,
 		"INSERT INTO refresh_tokens (user_id, token_hash, expire_at) VALUES ($1, $2, $3)",
-		userID, tokenHash, expireAt,
+		userID, tokenHash, expireAt.UTC(),
 	)
 	return err
 }
@@ -61,3 +62,12 @@ func (r *TokenRepo) DeleteRefreshToken(ctx context.Context, tokenHash string) er
 	}
 	return nil
 }
+
+// DeleteUserRefreshTokens удаляет все сессии пользователя, кроме exceptTokenHash.
+func (r *TokenRepo) DeleteUserRefreshTokens(ctx context.Context, userID int, exceptTokenHash string) error {
+	_, err := r.Db.ExecContext(ctx,
+		"DELETE FROM refresh_tokens WHERE user_id = $1 AND token_hash <> $2",
+		userID, exceptTokenHash,
+	)
+	return err
+}
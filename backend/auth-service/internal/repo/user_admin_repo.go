@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/events"
+)
+
+type UserAdminRepository interface {
+	ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.UserListItem, int, error)
+	// SetRole меняет роль и пишет audit в журнал в той же транзакции.
+	SetRole(ctx context.Context, userID int, role string, audit events.AuditRecorded) (bool, error)
+}
+
+type UserAdminRepo struct {
+	Db *sql.DB
+}
+
+func NewUserAdminRepo(db *sql.DB) *UserAdminRepo {
+	return &UserAdminRepo{Db: db}
+}
+
+// ListUsers ищет по подстроке в имени или email. Вместе со страницей
+// возвращает общее число найденных пользователей.
+func (r *UserAdminRepo) ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.UserListItem, int,
+	error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.Query != "" {
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
+		conditions = append(conditions, fmt.Sprintf("(username ILIKE $%d OR email ILIKE $%d)", len(args), len(args)))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.Db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PageSize, (filter.Page-1)*filter.PageSize)
+	query := `SELECT ` + userColumns + `, b.id, b.reason, b.banned_by, b.expire_at, b.created_at
+		FROM users
+		LEFT JOIN LATERAL (
+			SELECT id, reason, COALESCE(banned_by, 0) AS banned_by, expire_at, created_at FROM user_bans
+			WHERE user_bans.user_id = users.id AND ` + activeBanCondition + `
+			ORDER BY expire_at DESC NULLS FIRST LIMIT 1
+		) b ON TRUE` + where + fmt.Sprintf(" ORDER BY users.id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []*entity.UserListItem
+	for rows.Next() {
+		var (
+			user        entity.User
+			banID       sql.NullInt64
+			banReason   sql.NullString
+			bannedBy    sql.NullInt64
+			banExpireAt sql.NullTime
+			banCreated  sql.NullTime
+		)
+		err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.Role,
+			&user.MFAEnabled, &user.MFASecret, &user.MFALastStep, &user.CreatedAt,
+			&banID, &banReason, &bannedBy, &banExpireAt, &banCreated)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		item := &entity.UserListItem{User: &user}
+		if banID.Valid {
+			item.Ban = &entity.Ban{
+				ID:        int(banID.Int64),
+				UserID:    user.ID,
+				Reason:    banReason.String,
+				BannedBy:  int(bannedBy.Int64),
+				ExpiresAt: banExpireAt.Time,
+				CreatedAt: banCreated.Time,
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, total, rows.Err()
+}
+
+func (r *UserAdminRepo) SetRole(ctx context.Context, userID int, role string,
+	audit events.AuditRecorded) (bool, error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2", role, userID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return false, err
+	}
+
+	if err := recordAudit(ctx, tx, audit); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// escapeLike экранирует спецсимволы LIKE в пользовательском вводе.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
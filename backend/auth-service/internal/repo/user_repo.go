@@ -2,15 +2,28 @@ package repo
 
 import (
 	"database/sql"
+	"errors"
 
+	"github.com/lib/pq"
 	"go-forum-project/auth-service/internal/entity"
 )
 
+var (
+	ErrDuplicateUser  = errors.New("user already exists")
+	ErrDuplicateEmail = errors.New("email already in use")
+)
+
 type AuthRepository interface {
 	CreateUser(user *entity.User) error
 	GetUserByUsername(username string) (*entity.User, error)
 	GetUserByID(id int) (*entity.User, error)
-	UserExists(username string) (bool, error)
+	// GetUsersByIDs пропускает несуществующих и удалённых пользователей
+	GetUsersByIDs(ids []int) ([]*entity.User, error)
+	GetUserByEmail(email string) (*entity.User, error)
+	UserExists(username, skeleton string) (bool, error)
+	EmailExists(email string) (bool, error)
+	UpdatePassword(id int, password string) error
+	MarkEmailVerified(id int) error
 }
 
 type UserRepo struct {
@@ -21,39 +34,108 @@ func NewUserRepo(db *sql.DB) *UserRepo {
 	return &UserRepo{DB: db}
 }
 
-func (r *UserRepo) CreateUser(user *entity.User) error {
-	_, err := r.DB.Exec(
-		"INSERT INTO users (username, password, role) VALUES ($1, $2, $3)",
-		user.Username, user.Password, user.Role,
-	)
-	return err
+const userColumns = `id, username, COALESCE(email, ''), email_verified, password, role,
+	mfa_enabled, COALESCE(mfa_secret, ''), mfa_last_step, created_at`
+
+// rowScanner - общий интерфейс *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-func (r *UserRepo) GetUserByUsername(username string) (*entity.User, error) {
+func scanUser(row rowScanner) (*entity.User, error) {
 	var user entity.User
-	err := r.DB.QueryRow("SELECT id, username, password, role FROM users WHERE username = $1", username).
-		Scan(&user.ID, &user.Username, &user.Password, &user.Role)
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.EmailVerified, &user.Password, &user.Role,
+		&user.MFAEnabled, &user.MFASecret, &user.MFALastStep, &user.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
+func (r *UserRepo) CreateUser(user *entity.User) error {
+	err := r.DB.QueryRow(
+		`INSERT INTO users (username, username_skeleton, email, email_verified, password, role)
+		 VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6) RETURNING id`,
+		user.Username, user.UsernameSkeleton, user.Email, user.EmailVerified, user.Password, user.Role,
+	).Scan(&user.ID)
+	return mapUniqueViolation(err)
+}
+
+func (r *UserRepo) GetUserByUsername(username string) (*entity.User, error) {
+	return scanUser(r.DB.QueryRow("SELECT "+userColumns+" FROM users WHERE LOWER(username) = LOWER($1)", username))
+}
+
 func (r *UserRepo) GetUserByID(id int) (*entity.User, error) {
-	var user entity.User
-	err := r.DB.QueryRow("SELECT id, username, password, role FROM users WHERE id = $1", id).
-		Scan(&user.ID, &user.Username, &user.Password, &user.Role)
+	return scanUser(r.DB.QueryRow("SELECT "+userColumns+" FROM users WHERE id = $1", id))
+}
+
+func (r *UserRepo) GetUsersByIDs(ids []int) ([]*entity.User, error) {
+	rows, err := r.DB.Query("SELECT "+userColumns+" FROM users WHERE id = ANY($1) AND deleted_at IS NULL",
+		pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	defer rows.Close()
+
+	var users []*entity.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *UserRepo) GetUserByEmail(email string) (*entity.User, error) {
+	return scanUser(r.DB.QueryRow("SELECT "+userColumns+" FROM users WHERE LOWER(email) = LOWER($1)", email))
 }
 
-func (r *UserRepo) UserExists(username string) (bool, error) {
+// UserExists ищет пользователя с тем же именем без учёта регистра
+// или с визуально похожим именем (тем же скелетом). Недавно освобождённые
+// имена тоже считаются занятыми.
+func (r *UserRepo) UserExists(username, skeleton string) (bool, error) {
 	var exists bool
 	err := r.DB.QueryRow(
-		"SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)",
-		username,
+		`SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(username) = LOWER($1) OR username_skeleton = $2)
+		     OR EXISTS(SELECT 1 FROM username_history WHERE reserved_until > NOW()
+		                   AND (LOWER(username) = LOWER($1) OR username_skeleton = $2))`,
+		username, skeleton,
 	).Scan(&exists)
 	return exists, err
 }
+
+func (r *UserRepo) EmailExists(email string) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = LOWER($1))",
+		email,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *UserRepo) UpdatePassword(id int, password string) error {
+	_, err := r.DB.Exec(
+		"UPDATE users SET password = $1, updated_at = NOW() WHERE id = $2",
+		password, id,
+	)
+	return err
+}
+
+func (r *UserRepo) MarkEmailVerified(id int) error {
+	_, err := r.DB.Exec("UPDATE users SET email_verified = TRUE, updated_at = NOW() WHERE id = $1", id)
+	return err
+}
+
+func mapUniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		if pqErr.Constraint == "idx_users_email_lower" {
+			return ErrDuplicateEmail
+		}
+		return ErrDuplicateUser
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-forum-project/events"
+)
+
+type UsernameHistoryRepository interface {
+	// ChangeUsername сохраняет текущее имя в историю и меняет его на новое.
+	ChangeUsername(ctx context.Context, userID int, username, skeleton string, reservedUntil time.Time) error
+	// LastChangedAt возвращает нулевое время, если пользователь не менял имя.
+	LastChangedAt(ctx context.Context, userID int) (time.Time, error)
+	// UsernameAvailable проверяет имя для userID: его не должен носить другой
+	// пользователь и оно не должно быть зарезервировано за другим пользователем.
+	UsernameAvailable(ctx context.Context, userID int, username, skeleton string) (bool, error)
+	// FindUserByPreviousUsername ищет последнего владельца прежнего имени.
+	FindUserByPreviousUsername(ctx context.Context, username string) (int, error)
+}
+
+type UsernameHistoryRepo struct {
+	Db *sql.DB
+}
+
+func NewUsernameHistoryRepo(db *sql.DB) *UsernameHistoryRepo {
+	return &UsernameHistoryRepo{Db: db}
+}
+
+func (r *UsernameHistoryRepo) ChangeUsername(ctx context.Context, userID int, username, skeleton string,
+	reservedUntil time.Time) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previous string
+	if err := tx.QueryRowContext(ctx,
+		`INSERT INTO username_history (user_id, username, username_skeleton, reserved_until)
+		 SELECT id, username, username_skeleton, $2 FROM users WHERE id = $1
+		 RETURNING username`,
+		userID, reservedUntil.UTC(),
+	).Scan(&previous); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE users SET username = $1, username_skeleton = $2, updated_at = NOW() WHERE id = $3",
+		username, skeleton, userID,
+	); err != nil {
+		return mapUniqueViolation(err)
+	}
+
+	err = events.Enqueue(ctx, tx, events.UserRenamed{UserID: userID, OldUsername: previous, NewUsername: username})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *UsernameHistoryRepo) LastChangedAt(ctx context.Context, userID int) (time.Time, error) {
+	var changedAt sql.NullTime
+	err := r.Db.QueryRowContext(ctx,
+		"SELECT MAX(changed_at) FROM username_history WHERE user_id = $1", userID,
+	).Scan(&changedAt)
+	return changedAt.Time, err
+}
+
+func (r *UsernameHistoryRepo) UsernameAvailable(ctx context.Context, userID int, username, skeleton string) (bool, error) {
+	var taken bool
+	err := r.Db.QueryRowContext(ctx,
+		`SELECT EXISTS(
+		     SELECT 1 FROM users
+		     WHERE id <> $3 AND (LOWER(username) = LOWER($1) OR username_skeleton = $2)
+		 ) OR EXISTS(
+		     SELECT 1 FROM username_history
+		     WHERE user_id <> $3 AND reserved_until > NOW()
+		       AND (LOWER(username) = LOWER($1) OR username_skeleton = $2)
+		 )`,
+		username, skeleton, userID,
+	).Scan(&taken)
+	return !taken, err
+}
+
+func (r *UsernameHistoryRepo) FindUserByPreviousUsername(ctx context.Context, username string) (int, error) {
+	var userID int
+	err := r.Db.QueryRowContext(ctx,
+		`SELECT user_id FROM username_history WHERE LOWER(username) = LOWER($1)
+		 ORDER BY changed_at DESC LIMIT 1`,
+		username,
+	).Scan(&userID)
+	return userID, err
+}
@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type VerificationTokenRepository interface {
+	CreateVerificationToken(ctx context.Context, userID int, tokenHash string, expireAt time.Time) error
+	ConsumeVerificationToken(ctx context.Context, tokenHash string) (int, error)
+	DeleteUserVerificationTokens(ctx context.Context, userID int) error
+}
+
+type VerificationTokenRepo struct {
+	Db *sql.DB
+}
+
+func NewVerificationTokenRepo(db *sql.DB) *VerificationTokenRepo {
+	return &VerificationTokenRepo{Db: db}
+}
+
+func (r *VerificationTokenRepo) CreateVerificationToken(ctx context.Context, userID int, tokenHash string,
+	expireAt time.Time) error {
+	_, err := r.Db.ExecContext(ctx,
+		"INSERT INTO email_verification_tokens (user_id, token_hash, expire_at) VALUES ($1, $2, $3)",
+		userID, tokenHash, expireAt.UTC(),
+	)
+	return err
+}
+
+// ConsumeVerificationToken удаляет действующий токен и возвращает его владельца.
+func (r *VerificationTokenRepo) ConsumeVerificationToken(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := r.Db.QueryRowContext(ctx,
+		"DELETE FROM email_verification_tokens WHERE token_hash = $1 AND expire_at > NOW() RETURNING user_id",
+		tokenHash,
+	).Scan(&userID)
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (r *VerificationTokenRepo) DeleteUserVerificationTokens(ctx context.Context, userID int) error {
+	_, err := r.Db.ExecContext(ctx,
+		"DELETE FROM email_verification_tokens WHERE user_id = $1",
+		userID,
+	)
+	return err
+}
@@ -0,0 +1,90 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Секрет "12345678901234567890" из приложения B RFC 6238
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateRFCVectors(t *testing.T) {
+	// Коды из RFC - восьмизначные, берём последние шесть цифр
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			now := time.Unix(tt.unix, 0)
+			step, ok := Validate(rfcSecret, tt.code, now)
+			if !ok {
+				t.Fatalf("Validate(%q) at %d = false, want true", tt.code, tt.unix)
+			}
+			if want := tt.unix / int64(Period.Seconds()); step != want {
+				t.Errorf("step = %d, want %d", step, want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	const code = "050471"
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		at     time.Time
+		valid  bool
+	}{
+		{name: "current step", secret: rfcSecret, code: code, at: now, valid: true},
+		{name: "previous step", secret: rfcSecret, code: code, at: now.Add(Period), valid: true},
+		{name: "next step", secret: rfcSecret, code: code, at: now.Add(-Period), valid: true},
+		{name: "two steps later", secret: rfcSecret, code: code, at: now.Add(2 * Period), valid: false},
+		{name: "two steps earlier", secret: rfcSecret, code: code, at: now.Add(-2 * Period), valid: false},
+		{name: "surrounding spaces", secret: rfcSecret, code: " " + code + " ", at: now, valid: true},
+		{name: "lowercase secret", secret: strings.ToLower(rfcSecret), code: code, at: now, valid: true},
+		{name: "wrong code", secret: rfcSecret, code: "123456", at: now, valid: false},
+		{name: "too short", secret: rfcSecret, code: code[:5], at: now, valid: false},
+		{name: "eight digits", secret: rfcSecret, code: "14050471", at: now, valid: false},
+		{name: "empty", secret: rfcSecret, code: "", at: now, valid: false},
+		{name: "invalid secret", secret: "not base32!", code: code, at: now, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Validate(tt.secret, tt.code, tt.at); ok != tt.valid {
+				t.Errorf("Validate(%q) = %v, want %v", tt.code, ok, tt.valid)
+			}
+		})
+	}
+}
+
+func TestGenerateSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret is not base32: %v", err)
+	}
+
+	now := time.Now()
+	code := generate(key, now.Unix()/int64(Period.Seconds()))
+	if _, ok := Validate(secret, code, now); !ok {
+		t.Errorf("Validate rejected a freshly generated code")
+	}
+}
@@ -0,0 +1,397 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-forum-project/auth-service/internal/avatar"
+	"go-forum-project/auth-service/internal/config"
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/auth-service/internal/userdata"
+)
+
+const (
+	defaultExportTTL = 7 * 24 * time.Hour
+	exportTimeout    = 5 * time.Minute
+)
+
+var (
+	ErrExportInProgress = errors.New("data export is already in progress")
+	ErrExportNotFound   = errors.New("data export not found")
+	ErrExportNotReady   = errors.New("data export is not ready")
+
+	ErrInvalidInternalToken = errors.New("invalid internal token")
+	ErrTooManyContacts      = errors.New("too many users requested")
+)
+
+// maxContactsPerRequest ограничивает GetContacts, сервисы запрашивают адреса пачками.
+const maxContactsPerRequest = 500
+
+// erasureBatch - сколько отложенных стираний RetryErasures берёт за раз.
+const erasureBatch = 100
+
+type AccountUseCase interface {
+	// DeleteAccount обезличивает пользователя во всех сервисах. Аккаунт в
+	// auth-service удаляется сразу, а сервисы, которые не ответили, дочищает
+	// RetryErasures.
+	DeleteAccount(ctx context.Context, userID int, password, mfaCode string) error
+	// RetryErasures повторяет стирание данных в сервисах, где оно не удалось.
+	RetryErasures(ctx context.Context) error
+
+	// RequestExport ставит задачу на выгрузку, архив собирается в фоне.
+	RequestExport(ctx context.Context, userID int) (*entity.DataExport, error)
+	GetExport(ctx context.Context, userID, id int) (*entity.DataExport, error)
+	ReadExport(ctx context.Context, userID, id int) (*entity.DataExport, []byte, error)
+	CleanupExports(ctx context.Context) error
+
+	// GetContacts отдаёт адреса пользователей другим сервисам для рассылок.
+	// internalToken должен совпадать с accounts.internal_token.
+	GetContacts(ctx context.Context, internalToken string, userIDs []int) ([]*entity.User, error)
+}
+
+type accountUseCase struct {
+	authUC      AuthUseCase
+	userRepo    repo.AuthRepository
+	accountRepo repo.AccountRepository
+	profileRepo repo.ProfileRepository
+	tokenRepo   repo.PersonalTokenRepository
+	oidcRepo    repo.OIDCRepository
+	avatars     *avatar.Store
+	services    []*userdata.Client
+	cfg         config.AccountsConfig
+}
+
+func NewAccountUseCase(authUC AuthUseCase, ur repo.AuthRepository, ar repo.AccountRepository,
+	pr repo.ProfileRepository, tr repo.PersonalTokenRepository, or repo.OIDCRepository, avatars *avatar.Store,
+	services []*userdata.Client, cfg config.AccountsConfig) AccountUseCase {
+	if cfg.ExportTTL <= 0 {
+		cfg.ExportTTL = defaultExportTTL
+	}
+
+	return &accountUseCase{
+		authUC:      authUC,
+		userRepo:    ur,
+		accountRepo: ar,
+		profileRepo: pr,
+		tokenRepo:   tr,
+		oidcRepo:    or,
+		avatars:     avatars,
+		services:    services,
+		cfg:         cfg,
+	}
+}
+
+func (uc *accountUseCase) DeleteAccount(ctx context.Context, userID int, password, mfaCode string) error {
+	if err := uc.authUC.ConfirmIdentity(ctx, userID, password, mfaCode); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	profile, err := uc.profileRepo.GetProfileByID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	names := make([]string, 0, len(uc.services))
+	for _, service := range uc.services {
+		names = append(names, service.Name())
+	}
+
+	// Сначала обезличиваем аккаунт: остальные сервисы ищут данные по ID, а
+	// задачи на стирание сохраняются в той же транзакции. Имя с # не проходит
+	// политику имён, поэтому его нельзя занять заново
+	if err := uc.accountRepo.AnonymizeUser(ctx, user.ID, fmt.Sprintf("deleted#%d", user.ID), names); err != nil {
+		return err
+	}
+
+	for _, service := range uc.services {
+		uc.erase(ctx, service, user.ID)
+	}
+
+	if err := uc.avatars.Delete(profile.AvatarURL); err != nil {
+		log.Printf("Failed to delete avatar of user %d: %v", user.ID, err)
+	}
+
+	files, err := uc.accountRepo.DeleteUserExports(ctx, user.ID)
+	if err != nil {
+		log.Printf("Failed to delete exports of user %d: %v", user.ID, err)
+	}
+	uc.removeExportFiles(files)
+
+	return nil
+}
+
+func (uc *accountUseCase) RetryErasures(ctx context.Context) error {
+	erasures, err := uc.accountRepo.ListPendingErasures(ctx, erasureBatch)
+	if err != nil {
+		return err
+	}
+
+	services := make(map[string]*userdata.Client, len(uc.services))
+	for _, service := range uc.services {
+		services[service.Name()] = service
+	}
+
+	for _, erasure := range erasures {
+		service, ok := services[erasure.Service]
+		if !ok {
+			log.Printf("Pending erasure of user %d in unknown service %s", erasure.UserID, erasure.Service)
+			continue
+		}
+		uc.erase(ctx, service, erasure.UserID)
+	}
+	return nil
+}
+
+// erase стирает данные в одном сервисе. Ошибка не возвращается: задача
+// остаётся в pending_erasures и её повторит RetryErasures.
+func (uc *accountUseCase) erase(ctx context.Context, service *userdata.Client, userID int) {
+	if err := service.Erase(ctx, userID); err != nil {
+		log.Printf("Failed to erase data of user %d: %v", userID, err)
+		if err := uc.accountRepo.FailErasure(ctx, userID, service.Name(), err.Error()); err != nil {
+			log.Printf("Failed to record erasure failure of user %d: %v", userID, err)
+		}
+		return
+	}
+
+	if err := uc.accountRepo.FinishErasure(ctx, userID, service.Name()); err != nil {
+		log.Printf("Failed to finish erasure of user %d in %s: %v", userID, service.Name(), err)
+	}
+}
+
+func (uc *accountUseCase) RequestExport(ctx context.Context, userID int) (*entity.DataExport, error) {
+	pending, err := uc.accountRepo.HasPendingExport(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if pending {
+		return nil, ErrExportInProgress
+	}
+
+	export := &entity.DataExport{UserID: userID, Status: entity.ExportPending}
+	if err := uc.accountRepo.CreateExport(ctx, export); err != nil {
+		return nil, fmt.Errorf("failed to create export: %w", err)
+	}
+
+	go uc.buildExport(*export)
+
+	return export, nil
+}
+
+func (uc *accountUseCase) GetExport(ctx context.Context, userID, id int) (*entity.DataExport, error) {
+	export, err := uc.accountRepo.GetExport(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExportNotFound
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	if !export.ExpiresAt.IsZero() && time.Now().After(export.ExpiresAt) {
+		return nil, ErrExportNotFound
+	}
+	return export, nil
+}
+
+func (uc *accountUseCase) ReadExport(ctx context.Context, userID, id int) (*entity.DataExport, []byte, error) {
+	export, err := uc.GetExport(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export.Status != entity.ExportReady {
+		return nil, nil, ErrExportNotReady
+	}
+
+	data, err := os.ReadFile(filepath.Join(uc.cfg.ExportDir, filepath.Base(export.FileName)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, ErrExportNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to read export: %w", err)
+	}
+
+	return export, data, nil
+}
+
+func (uc *accountUseCase) CleanupExports(ctx context.Context) error {
+	files, err := uc.accountRepo.DeleteExpiredExports(ctx)
+	if err != nil {
+		return err
+	}
+	uc.removeExportFiles(files)
+	return nil
+}
+
+func (uc *accountUseCase) GetContacts(ctx context.Context, internalToken string,
+	userIDs []int) ([]*entity.User, error) {
+	expected := uc.cfg.InternalToken
+	if expected == "" || subtle.ConstantTimeCompare([]byte(internalToken), []byte(expected)) != 1 {
+		return nil, ErrInvalidInternalToken
+	}
+
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	if len(userIDs) > maxContactsPerRequest {
+		return nil, ErrTooManyContacts
+	}
+
+	return uc.userRepo.GetUsersByIDs(userIDs)
+}
+
+// buildExport собирает архив и записывает результат в задачу. Неудачные
+// задачи тоже получают срок, чтобы их подчистил CleanupExports.
+func (uc *accountUseCase) buildExport(export entity.DataExport) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	fileName, err := uc.writeArchive(ctx, export.UserID)
+
+	export.CompletedAt = time.Now()
+	export.ExpiresAt = export.CompletedAt.Add(uc.cfg.ExportTTL)
+	if err != nil {
+		log.Printf("Failed to build export %d: %v", export.ID, err)
+		export.Status = entity.ExportFailed
+		export.Error = "failed to collect user data, please try again later"
+	} else {
+		export.Status = entity.ExportReady
+		export.FileName = fileName
+	}
+
+	if err := uc.accountRepo.FinishExport(ctx, &export); err != nil {
+		log.Printf("Failed to save export %d: %v", export.ID, err)
+	}
+}
+
+// writeArchive пишет account.json и по файлу <сервис>.json на каждый
+// сервис из конфига. Возвращает имя файла в ExportDir.
+func (uc *accountUseCase) writeArchive(ctx context.Context, userID int) (string, error) {
+	account, err := uc.accountData(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(uc.cfg.ExportDir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create export dir: %w", err)
+	}
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate export name: %w", err)
+	}
+	fileName := fmt.Sprintf("%d-%s.zip", userID, hex.EncodeToString(random))
+	path := filepath.Join(uc.cfg.ExportDir, fileName)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	err = writeZip(file, func(zw *zip.Writer) error {
+		accountJSON, err := json.MarshalIndent(account, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeZipEntry(zw, "account.json", accountJSON); err != nil {
+			return err
+		}
+
+		for _, service := range uc.services {
+			data, err := service.Export(ctx, account.ID)
+			if err != nil {
+				return err
+			}
+			if err := writeZipEntry(zw, service.Name()+".json", data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return fileName, nil
+}
+
+func (uc *accountUseCase) accountData(ctx context.Context, userID int) (*entity.AccountData, error) {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	profile, err := uc.profileRepo.GetProfileByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	tokens, err := uc.tokenRepo.ListPersonalTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list personal tokens: %w", err)
+	}
+
+	identities, err := uc.oidcRepo.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+
+	return &entity.AccountData{
+		ID:             user.ID,
+		Username:       user.Username,
+		Email:          user.Email,
+		EmailVerified:  user.EmailVerified,
+		Role:           user.Role,
+		MFAEnabled:     user.MFAEnabled,
+		CreatedAt:      user.CreatedAt,
+		Profile:        profile,
+		PersonalTokens: tokens,
+		Identities:     identities,
+	}, nil
+}
+
+func (uc *accountUseCase) removeExportFiles(files []string) {
+	for _, name := range files {
+		err := os.Remove(filepath.Join(uc.cfg.ExportDir, filepath.Base(name)))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Failed to remove export file %s: %v", name, err)
+		}
+	}
+}
+
+func writeZip(file *os.File, write func(zw *zip.Writer) error) error {
+	zw := zip.NewWriter(file)
+	if err := write(zw); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+func writeZipEntry(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/events"
+)
+
+const (
+	defaultUsersPageSize = 20
+	maxUsersPageSize     = 100
+
+	auditTargetUser = "user"
+)
+
+var (
+	ErrUserBanned       = errors.New("user is banned")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrUserNotBanned    = errors.New("user is not banned")
+	ErrInvalidRole      = errors.New("invalid role")
+	ErrCannotTargetSelf = errors.New("admins cannot change their own role or ban themselves")
+	ErrCannotBanAdmin   = errors.New("admins cannot be banned")
+)
+
+var userRoles = []string{entity.RoleUser, entity.RoleModerator, entity.RoleAdmin}
+
+// BannedError возвращается при входе и проверке токена заблокированного
+// пользователя. errors.Is(err, ErrUserBanned) для него истинно.
+type BannedError struct {
+	Reason    string
+	ExpiresAt time.Time
+}
+
+func (e *BannedError) Error() string {
+	if e.ExpiresAt.IsZero() {
+		return fmt.Sprintf("user is banned permanently: %s", e.Reason)
+	}
+	return fmt.Sprintf("user is banned until %s: %s", e.ExpiresAt.UTC().Format(time.RFC3339), e.Reason)
+}
+
+func (e *BannedError) Is(target error) bool {
+	return target == ErrUserBanned
+}
+
+// checkBan возвращает *BannedError, если у пользователя есть действующая блокировка.
+func checkBan(ctx context.Context, bans repo.BanRepository, userID int) error {
+	ban, err := bans.GetActiveBan(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check ban: %w", err)
+	}
+	if ban != nil {
+		return &BannedError{Reason: ban.Reason, ExpiresAt: ban.ExpiresAt}
+	}
+	return nil
+}
+
+type AdminUseCase interface {
+	ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.UserListItem, int, error)
+	GetUser(ctx context.Context, userID int) (*entity.UserListItem, error)
+	SetRole(ctx context.Context, actorID, userID int, role string) error
+	BanUser(ctx context.Context, actorID, userID int, reason string, duration time.Duration) (*entity.Ban, error)
+	UnbanUser(ctx context.Context, actorID, userID int) error
+}
+
+type adminUseCase struct {
+	userRepo  repo.AuthRepository
+	adminRepo repo.UserAdminRepository
+	banRepo   repo.BanRepository
+	tokenRepo repo.TokenRepository
+}
+
+func NewAdminUseCase(ur repo.AuthRepository, ar repo.UserAdminRepository, br repo.BanRepository,
+	tr repo.TokenRepository) AdminUseCase {
+	return &adminUseCase{userRepo: ur, adminRepo: ar, banRepo: br, tokenRepo: tr}
+}
+
+func (uc *adminUseCase) ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.UserListItem, int,
+	error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Role = strings.ToLower(strings.TrimSpace(filter.Role))
+
+	if filter.Role != "" && !slices.Contains(userRoles, filter.Role) {
+		return nil, 0, &entity.ValidationError{Violations: []entity.FieldViolation{
+			{Field: "role", Description: "must be one of " + strings.Join(userRoles, ", ")},
+		}}
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultUsersPageSize
+	}
+	if filter.PageSize > maxUsersPageSize {
+		filter.PageSize = maxUsersPageSize
+	}
+
+	return uc.adminRepo.ListUsers(ctx, filter)
+}
+
+func (uc *adminUseCase) GetUser(ctx context.Context, userID int) (*entity.UserListItem, error) {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	ban, err := uc.banRepo.GetActiveBan(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ban: %w", err)
+	}
+
+	return &entity.UserListItem{User: user, Ban: ban}, nil
+}
+
+func (uc *adminUseCase) SetRole(ctx context.Context, actorID, userID int, role string) error {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if !slices.Contains(userRoles, role) {
+		return ErrInvalidRole
+	}
+
+	// Иначе последний администратор может случайно лишить себя прав
+	if actorID == userID {
+		return ErrCannotTargetSelf
+	}
+
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	updated, err := uc.adminRepo.SetRole(ctx, userID, role, adminAudit(events.AuditRecorded{
+		ActorID:    actorID,
+		Action:     events.AuditUserRoleChange,
+		TargetType: auditTargetUser,
+		TargetID:   userID,
+		Before:     events.Snapshot(map[string]string{"role": user.Role}),
+		After:      events.Snapshot(map[string]string{"role": role}),
+	}))
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if !updated {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// BanUser блокирует пользователя и завершает все его сессии. Нулевая
+// длительность означает бессрочную блокировку.
+func (uc *adminUseCase) BanUser(ctx context.Context, actorID, userID int, reason string,
+	duration time.Duration) (*entity.Ban, error) {
+	reason = strings.TrimSpace(reason)
+
+	var violations []entity.FieldViolation
+	if reason == "" || len([]rune(reason)) > 500 {
+		violations = append(violations, entity.FieldViolation{Field: "reason", Description: "must be 1-500 characters"})
+	}
+	if duration < 0 {
+		violations = append(violations, entity.FieldViolation{Field: "duration_seconds", Description: "must not be negative"})
+	}
+	if len(violations) > 0 {
+		return nil, &entity.ValidationError{Violations: violations}
+	}
+
+	if actorID == userID {
+		return nil, ErrCannotTargetSelf
+	}
+
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Role == entity.RoleAdmin {
+		return nil, ErrCannotBanAdmin
+	}
+
+	previous, err := uc.banRepo.GetActiveBan(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ban: %w", err)
+	}
+
+	ban := &entity.Ban{UserID: user.ID, Reason: reason, BannedBy: actorID}
+	if duration > 0 {
+		ban.ExpiresAt = time.Now().Add(duration)
+	}
+
+	err = uc.banRepo.CreateBan(ctx, ban, adminAudit(events.AuditRecorded{
+		ActorID:    actorID,
+		Action:     events.AuditUserBan,
+		TargetType: auditTargetUser,
+		TargetID:   user.ID,
+		Reason:     reason,
+		Before:     banSnapshot(previous),
+		After:      banSnapshot(ban),
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ban: %w", err)
+	}
+
+	// Access токены живут недолго и дополнительно отсекаются в ValidateToken
+	if err := uc.tokenRepo.DeleteUserRefreshTokens(ctx, user.ID, ""); err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return ban, nil
+}
+
+func (uc *adminUseCase) UnbanUser(ctx context.Context, actorID, userID int) error {
+	previous, err := uc.banRepo.GetActiveBan(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check ban: %w", err)
+	}
+
+	revoked, err := uc.banRepo.RevokeBans(ctx, userID, actorID, adminAudit(events.AuditRecorded{
+		ActorID:    actorID,
+		Action:     events.AuditUserUnban,
+		TargetType: auditTargetUser,
+		TargetID:   userID,
+		Before:     banSnapshot(previous),
+	}))
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if !revoked {
+		return ErrUserNotBanned
+	}
+	return nil
+}
+
+// adminAudit дополняет запись журнала ролью. Вызовы AdminUseCase доступны
+// только роли admin, поэтому роль не передаётся.
+func adminAudit(event events.AuditRecorded) events.AuditRecorded {
+	event.ActorRole = entity.RoleAdmin
+	return event
+}
+
+func banSnapshot(ban *entity.Ban) json.RawMessage {
+	if ban == nil {
+		return nil
+	}
+	snapshot := map[string]any{"reason": ban.Reason, "banned_by": ban.BannedBy, "expires_at": nil}
+	if !ban.ExpiresAt.IsZero() {
+		snapshot["expires_at"] = ban.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return events.Snapshot(snapshot)
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/auth-service/internal/userdata"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+	// Больше записей за раз не выгружается, фильтр нужно сузить
+	maxAuditExportRows = 10000
+	// auditSyncBatch - сколько записей забирается из сервиса за один запрос
+	auditSyncBatch = 500
+)
+
+var ErrAuditExportTooLarge = errors.New("too many audit entries to export, narrow the filter")
+
+var auditCSVHeader = []string{"id", "occurred_at", "service", "actor_id", "actor_role", "action", "target_type",
+	"target_id", "reason", "before", "after"}
+
+// AuditUseCase - журнал действий модераторов и администраторов всех сервисов.
+type AuditUseCase interface {
+	List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, int, error)
+	ExportCSV(ctx context.Context, filter entity.AuditFilter) ([]byte, error)
+	// Sync забирает новые записи из журналов остальных сервисов. Сервисы
+	// хранят записи у себя, пока их не заберут, поэтому пропущенный запуск
+	// ничего не теряет.
+	Sync(ctx context.Context) error
+}
+
+type auditUseCase struct {
+	auditRepo repo.AuditRepository
+	services  []*userdata.Client
+}
+
+func NewAuditUseCase(ar repo.AuditRepository, services []*userdata.Client) AuditUseCase {
+	return &auditUseCase{auditRepo: ar, services: services}
+}
+
+func (uc *auditUseCase) List(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, int, error) {
+	if err := validateAuditFilter(&filter); err != nil {
+		return nil, 0, err
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultAuditPageSize
+	}
+	if filter.PageSize > maxAuditPageSize {
+		filter.PageSize = maxAuditPageSize
+	}
+
+	return uc.auditRepo.List(ctx, filter, filter.PageSize, (filter.Page-1)*filter.PageSize)
+}
+
+func (uc *auditUseCase) ExportCSV(ctx context.Context, filter entity.AuditFilter) ([]byte, error) {
+	if err := validateAuditFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	entries, total, err := uc.auditRepo.List(ctx, filter, maxAuditExportRows, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	if total > maxAuditExportRows {
+		return nil, ErrAuditExportTooLarge
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(auditCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		record := []string{
+			strconv.Itoa(e.ID),
+			e.OccurredAt.UTC().Format(time.RFC3339),
+			e.Service,
+			strconv.Itoa(e.ActorID),
+			e.ActorRole,
+			e.Action,
+			e.TargetType,
+			strconv.Itoa(e.TargetID),
+			csvSafe(e.Reason),
+			csvSafe(e.Before),
+			csvSafe(e.After),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+func (uc *auditUseCase) Sync(ctx context.Context) error {
+	var errs []error
+	for _, service := range uc.services {
+		if err := uc.syncService(ctx, service); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", service.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (uc *auditUseCase) syncService(ctx context.Context, service *userdata.Client) error {
+	lastID, err := uc.auditRepo.LastSyncedID(ctx, service.Name())
+	if err != nil {
+		return err
+	}
+
+	for {
+		entries, err := service.AuditAfter(ctx, lastID, auditSyncBatch)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		if err := uc.auditRepo.StoreSynced(ctx, service.Name(), entries); err != nil {
+			return err
+		}
+		lastID = entries[len(entries)-1].ID
+		if len(entries) < auditSyncBatch {
+			return nil
+		}
+	}
+}
+
+func validateAuditFilter(filter *entity.AuditFilter) error {
+	filter.Action = strings.TrimSpace(filter.Action)
+	filter.Service = strings.TrimSpace(filter.Service)
+	filter.TargetType = strings.TrimSpace(filter.TargetType)
+
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return &entity.ValidationError{Violations: []entity.FieldViolation{
+			{Field: "since", Description: "must be before until"},
+		}}
+	}
+	return nil
+}
+
+// csvSafe не даёт табличным редакторам выполнить значение как формулу.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -7,54 +7,98 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"go-forum-project/auth-service/internal/config"
 	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/keys"
+	"go-forum-project/auth-service/internal/lockout"
+	"go-forum-project/auth-service/internal/password"
 	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/auth-service/internal/totp"
+	"go-forum-project/auth-service/internal/username"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrWrongPassword      = errors.New("current password is incorrect")
+	ErrUsernameTaken      = errors.New("username already exists")
+	ErrEmailTaken         = errors.New("email already in use")
+)
+
 type AuthUseCase interface {
-	Login(username, password string) (*entity.TokenPair, error)
+	Login(ctx context.Context, username, password, ip string) (*entity.TokenPair, error)
 	Logout(ctx context.Context) error
-	Register(username, password string) error
+	Register(username, password, email string) (*entity.User, error)
 	RefreshTokens(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
-	ValidateToken(ctx context.Context, accessToken string) (string, bool, error)
+	ValidateToken(ctx context.Context, accessToken string) (*entity.TokenClaims, error)
+	PublicKeys(ctx context.Context) ([]entity.PublicKey, error)
+	ChangePassword(ctx context.Context, userID int, currentPassword, newPassword, refreshToken string) error
+
+	EnrollMFA(ctx context.Context, userID int) (*entity.MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, userID int, code string, login bool) ([]string, *entity.TokenPair, error)
+	VerifyMFA(ctx context.Context, mfaToken, code, ip string) (*entity.TokenPair, error)
+	DisableMFA(ctx context.Context, userID int, password, code string) error
+	ParseMFAToken(mfaToken, purpose string) (int, error)
+
+	// CompleteLogin завершает вход уже опознанного пользователя (например,
+	// через OIDC): выдаёт токены или MFA токен, как Login.
+	CompleteLogin(ctx context.Context, userID int) (*entity.TokenPair, error)
+
+	// ConfirmIdentity перепроверяет пароль и, если включена MFA, код перед
+	// необратимыми действиями с аккаунтом.
+	ConfirmIdentity(ctx context.Context, userID int, password, mfaCode string) error
 }
 
 type authUseCase struct {
-	userRepo  repo.AuthRepository
-	tokenRepo repo.TokenRepository
-	secretKey string
+	userRepo   repo.AuthRepository
+	tokenRepo  repo.TokenRepository
+	mfaRepo    repo.MFARepository
+	banRepo    repo.BanRepository
+	signingKey *keys.KeySet
+	passwords  *password.Policy
+	usernames  *username.Policy
+	mfaCipher  *totp.Cipher
+	guard      *lockout.Guard
+	cfg        config.SecurityConfig
 }
 
-func NewAuthUseCase(ur repo.AuthRepository, tr repo.TokenRepository, secretKey string) AuthUseCase {
+func NewAuthUseCase(ur repo.AuthRepository, tr repo.TokenRepository, mr repo.MFARepository, br repo.BanRepository,
+	signingKey *keys.KeySet, passwords *password.Policy, usernames *username.Policy, mfaCipher *totp.Cipher,
+	guard *lockout.Guard, cfg config.SecurityConfig) AuthUseCase {
 	return &authUseCase{
-		userRepo:  ur,
-		tokenRepo: tr,
-		secretKey: secretKey,
+		userRepo:   ur,
+		tokenRepo:  tr,
+		mfaRepo:    mr,
+		banRepo:    br,
+		signingKey: signingKey,
+		passwords:  passwords,
+		usernames:  usernames,
+		mfaCipher:  mfaCipher,
+		guard:      guard,
+		cfg:        cfg,
 	}
 }
 
 func (uc *authUseCase) generateAccessToken(user *entity.User) (string, time.Time, error) {
-	cfg, err := config.LoadConfig("auth-service/internal/config/config.yaml")
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
-	}
-
-	expiresAt := time.Now().Add(cfg.Security.AccessTokenTTL)
+	expiresAt := time.Now().Add(uc.cfg.AccessTokenTTL)
 
 	claims := jwt.MapClaims{
 		"user_id":  user.ID,
 		"role":     user.Role,
 		"username": user.Username,
 		"exp":      expiresAt.Unix(),
+
+		"email_verified": user.EmailVerified,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(uc.secretKey))
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = uc.signingKey.KeyID()
+	signedToken, err := token.SignedString(uc.signingKey.PrivateKey())
 	return signedToken, expiresAt, err
 }
 
@@ -71,14 +115,9 @@ func (uc *authUseCase) generateRefreshToken(user *entity.User) (string, time.Tim
 		return "", time.Time{}, errors.New("username cannot be empty")
 	}
 
-	cfg, err := config.LoadConfig("auth-service/internal/config/config.yaml")
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
-	}
-
-	expiresAt := time.Now().Add(cfg.Security.RefreshTokenTTL)
+	expiresAt := time.Now().Add(uc.cfg.RefreshTokenTTL)
 
-	rawToken := sha256.Sum256([]byte(user.Username + time.Now().String() + uc.secretKey))
+	rawToken := sha256.Sum256([]byte(user.Username + time.Now().String() + uc.cfg.SecretKey))
 	token := hex.EncodeToString(rawToken[:])
 
 	if user.ID == 0 {
@@ -92,16 +131,85 @@ func (uc *authUseCase) generateRefreshToken(user *entity.User) (string, time.Tim
 	return token, expiresAt, nil
 }
 
-func (uc *authUseCase) Login(username, password string) (*entity.TokenPair, error) {
-	user, err := uc.userRepo.GetUserByUsername(username)
+func (uc *authUseCase) Login(ctx context.Context, name, password, ip string) (*entity.TokenPair, error) {
+	name = username.Normalize(name)
+
+	// Проверяем до обращения к БД, чтобы при блокировке ответ не зависел от пароля
+	if err := uc.guard.Check(ctx, name, ip); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetUserByUsername(name)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		uc.loginFailed(ctx, name, ip)
+		return nil, ErrInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, errors.New("invalid credentials")
+		uc.loginFailed(ctx, name, ip)
+		return nil, ErrInvalidCredentials
+	}
+
+	tokens, err := uc.completeLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// С MFA счётчик сбрасывается только после второго фактора, иначе знающий
+	// пароль перебирал бы коды, каждый раз начиная с нуля
+	if tokens.MFAToken == "" {
+		uc.loginSucceeded(ctx, name)
+	}
+	return tokens, nil
+}
+
+func (uc *authUseCase) CompleteLogin(ctx context.Context, userID int) (*entity.TokenPair, error) {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	return uc.completeLogin(ctx, user)
+}
+
+func (uc *authUseCase) completeLogin(ctx context.Context, user *entity.User) (*entity.TokenPair, error) {
+	if err := checkBan(ctx, uc.banRepo, user.ID); err != nil {
+		return nil, err
+	}
+
+	// Второй фактор: вместо токенов выдаём короткоживущий MFA токен
+	if user.MFAEnabled {
+		mfaToken, err := uc.generateMFAToken(user, MFAPurposeVerify)
+		if err != nil {
+			return nil, err
+		}
+		return &entity.TokenPair{MFAToken: mfaToken}, nil
+	}
+
+	if uc.mfaRequired(user) {
+		mfaToken, err := uc.generateMFAToken(user, MFAPurposeEnroll)
+		if err != nil {
+			return nil, err
+		}
+		return &entity.TokenPair{MFAToken: mfaToken, MFAEnrollmentRequired: true}, nil
+	}
+
+	return uc.issueTokens(user)
+}
+
+func (uc *authUseCase) loginFailed(ctx context.Context, name, ip string) {
+	if err := uc.guard.Failure(ctx, name, ip); err != nil {
+		log.Printf("Failed to record login failure: %v", err)
 	}
+}
 
+func (uc *authUseCase) loginSucceeded(ctx context.Context, name string) {
+	if err := uc.guard.Success(ctx, name); err != nil {
+		log.Printf("Failed to reset login attempts: %v", err)
+	}
+}
+
+func (uc *authUseCase) issueTokens(user *entity.User) (*entity.TokenPair, error) {
 	accessToken, accessExp, err := uc.generateAccessToken(user)
 	if err != nil {
 		return nil, err
@@ -138,27 +246,67 @@ func (uc *authUseCase) Logout(ctx context.Context) error {
 	return nil
 }
 
-func (uc *authUseCase) Register(username, password string) error {
-	exists, err := uc.userRepo.UserExists(username)
+func (uc *authUseCase) Register(name, password, email string) (*entity.User, error) {
+	name = username.Normalize(name)
+	email = strings.TrimSpace(email)
+
+	violations := uc.usernames.Check(name)
+	violations = append(violations, uc.passwords.Check("password", password, name)...)
+	switch {
+	case email == "" && uc.cfg.EmailVerification.Enabled:
+		violations = append(violations, entity.FieldViolation{Field: "email", Description: "is required"})
+	case email != "" && !validEmail(email):
+		violations = append(violations, entity.FieldViolation{Field: "email", Description: "must be a valid email address"})
+	}
+	if len(violations) > 0 {
+		return nil, &entity.ValidationError{Violations: violations}
+	}
+
+	skeleton := username.Skeleton(name)
+
+	exists, err := uc.userRepo.UserExists(name, skeleton)
 	if err != nil {
-		return fmt.Errorf("database error: %w", err)
+		return nil, fmt.Errorf("database error: %w", err)
 	}
 	if exists {
-		return errors.New("username already exists")
+		return nil, ErrUsernameTaken
+	}
+
+	if email != "" {
+		exists, err := uc.userRepo.EmailExists(email)
+		if err != nil {
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+		if exists {
+			return nil, ErrEmailTaken
+		}
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return fmt.Errorf("failed to hash password: %w", err)
+		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	user := &entity.User{
-		Username: username,
-		Password: string(hashedPassword),
-		Role:     "user",
+		Username:         name,
+		UsernameSkeleton: skeleton,
+		Email:            email,
+		EmailVerified:    !uc.cfg.EmailVerification.Enabled,
+		Password:         string(hashedPassword),
+		Role:             "user",
 	}
 
-	return uc.userRepo.CreateUser(user)
+	if err := uc.userRepo.CreateUser(user); err != nil {
+		if errors.Is(err, repo.ErrDuplicateUser) {
+			return nil, ErrUsernameTaken
+		}
+		if errors.Is(err, repo.ErrDuplicateEmail) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+
+	return user, nil
 }
 
 func (uc *authUseCase) RefreshTokens(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
@@ -180,13 +328,13 @@ func (uc *authUseCase) RefreshTokens(ctx context.Context, refreshToken string) (
 		return nil, errors.New("user not found")
 	}
 
-	// 4. Генерируем новые токены
-	newAccessToken, accessExp, err := uc.generateAccessToken(user)
-	if err != nil {
+	if err := checkBan(ctx, uc.banRepo, user.ID); err != nil {
+		_ = uc.tokenRepo.DeleteRefreshToken(ctx, refreshToken)
 		return nil, err
 	}
 
-	newRefreshToken, refreshExp, err := uc.generateRefreshToken(user)
+	// 4. Генерируем новые токены
+	tokens, err := uc.issueTokens(user)
 	if err != nil {
 		return nil, err
 	}
@@ -196,32 +344,131 @@ func (uc *authUseCase) RefreshTokens(ctx context.Context, refreshToken string) (
 		log.Printf("Failed to delete old refresh token: %v", err)
 	}
 
-	return &entity.TokenPair{
-		AccessToken:      newAccessToken,
-		RefreshToken:     newRefreshToken,
-		AccessExpiresAt:  accessExp,
-		RefreshExpiresAt: refreshExp,
-	}, nil
+	return tokens, nil
 }
 
-func (uc *authUseCase) ValidateToken(ctx context.Context, accessToken string) (string, bool, error) {
-	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
-		return []byte(uc.secretKey), nil
-	})
+func (uc *authUseCase) parseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		publicKey, ok := uc.signingKey.PublicKey(keyID)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %q", keyID)
+		}
+		return publicKey, nil
+	}, jwt.WithValidMethods([]string{keys.Algorithm}))
 	if err != nil {
-		return "", false, err
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", false, errors.New("invalid token claims")
+		return nil, errors.New("invalid token claims")
+	}
+
+	return claims, nil
+}
+
+func (uc *authUseCase) ValidateToken(ctx context.Context, accessToken string) (*entity.TokenClaims, error) {
+	claims, err := uc.parseToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// MFA токены подписаны тем же ключом, но доступа не дают
+	if _, ok := claims["purpose"]; ok {
+		return nil, errors.New("not an access token")
 	}
 
 	// Проверяем username в claims
 	username, ok := claims["username"].(string)
 	if !ok || username == "" {
-		return "", false, errors.New("username not found in token")
+		return nil, errors.New("username not found in token")
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil, errors.New("expiration not found in token")
+	}
+
+	userID, _ := claims["user_id"].(float64)
+	role, _ := claims["role"].(string)
+	emailVerified, _ := claims["email_verified"].(bool)
+
+	// Блокировка действует сразу, не дожидаясь истечения access токена
+	if err := checkBan(ctx, uc.banRepo, int(userID)); err != nil {
+		return nil, err
+	}
+
+	return &entity.TokenClaims{
+		UserID:        int(userID),
+		Username:      username,
+		Role:          role,
+		EmailVerified: emailVerified,
+		ExpiresAt:     expiresAt.Time,
+	}, nil
+}
+
+func (uc *authUseCase) PublicKeys(ctx context.Context) ([]entity.PublicKey, error) {
+	return uc.signingKey.PublicKeys()
+}
+
+// ChangePassword меняет пароль и завершает все сессии пользователя,
+// кроме текущей, переданной через refreshToken.
+func (uc *authUseCase) ChangePassword(ctx context.Context, userID int, currentPassword, newPassword,
+	refreshToken string) error {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
 	}
 
-	return username, true, nil
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return ErrWrongPassword
+	}
+
+	violations := uc.passwords.Check("new_password", newPassword, user.Username)
+	if newPassword == currentPassword {
+		violations = append(violations, entity.FieldViolation{
+			Field:       "new_password",
+			Description: "must differ from the current password",
+		})
+	}
+	if len(violations) > 0 {
+		return &entity.ValidationError{Violations: violations}
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := uc.userRepo.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := uc.tokenRepo.DeleteUserRefreshTokens(ctx, user.ID, refreshToken); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *authUseCase) ConfirmIdentity(ctx context.Context, userID int, password, mfaCode string) error {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return ErrWrongPassword
+	}
+
+	if user.MFAEnabled {
+		return uc.verifyMFACode(ctx, user, mfaCode, "")
+	}
+	return nil
+}
+
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
 }
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/mailer"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("verification token is invalid or expired")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+	ErrNoEmail                  = errors.New("user has no email")
+)
+
+type EmailVerificationUseCase interface {
+	SendVerification(ctx context.Context, user *entity.User) error
+	ResendVerification(ctx context.Context, userID int) error
+	VerifyEmail(ctx context.Context, token string) error
+}
+
+type emailVerificationUseCase struct {
+	userRepo  repo.AuthRepository
+	tokenRepo repo.VerificationTokenRepository
+	mailer    mailer.Mailer
+	tokenTTL  time.Duration
+	verifyURL string
+}
+
+func NewEmailVerificationUseCase(ur repo.AuthRepository, vr repo.VerificationTokenRepository, m mailer.Mailer,
+	tokenTTL time.Duration, frontendURL string) EmailVerificationUseCase {
+	return &emailVerificationUseCase{
+		userRepo:  ur,
+		tokenRepo: vr,
+		mailer:    m,
+		tokenTTL:  tokenTTL,
+		verifyURL: strings.TrimRight(frontendURL, "/") + "/verify-email",
+	}
+}
+
+func (uc *emailVerificationUseCase) SendVerification(ctx context.Context, user *entity.User) error {
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+	if user.Email == "" {
+		return ErrNoEmail
+	}
+
+	if err := uc.tokenRepo.DeleteUserVerificationTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete old verification tokens: %w", err)
+	}
+
+	token, err := newOneTimeToken()
+	if err != nil {
+		return err
+	}
+
+	err = uc.tokenRepo.CreateVerificationToken(ctx, user.ID, hashToken(token), time.Now().Add(uc.tokenTTL))
+	if err != nil {
+		return fmt.Errorf("failed to create verification token: %w", err)
+	}
+
+	link := uc.verifyURL + "?token=" + url.QueryEscape(token)
+	err = uc.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by following the link below, "+
+			"it is valid for %s:\n\n%s\n", user.Username, uc.tokenTTL, link),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send verification mail: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *emailVerificationUseCase) ResendVerification(ctx context.Context, userID int) error {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	return uc.SendVerification(ctx, user)
+}
+
+func (uc *emailVerificationUseCase) VerifyEmail(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrInvalidVerificationToken
+	}
+
+	userID, err := uc.tokenRepo.ConsumeVerificationToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidVerificationToken
+		}
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	if err := uc.userRepo.MarkEmailVerified(userID); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	MFAPurposeVerify = "mfa_verify"
+	MFAPurposeEnroll = "mfa_enroll"
+)
+
+var (
+	ErrInvalidMFAToken    = errors.New("mfa token is invalid or expired")
+	ErrInvalidMFACode     = errors.New("invalid mfa code")
+	ErrMFAAlreadyEnabled  = errors.New("mfa already enabled")
+	ErrMFANotEnrolled     = errors.New("mfa enrollment not started")
+	ErrMFANotEnabled      = errors.New("mfa not enabled")
+	ErrMFARequiredForRole = errors.New("mfa is required for this role")
+	// ErrMFAAttemptsExceeded - по MFA токену исчерпаны попытки, вход нужно начать заново
+	ErrMFAAttemptsExceeded = errors.New("too many invalid mfa codes, log in again")
+)
+
+const defaultMFAMaxAttempts = 5
+
+func (uc *authUseCase) mfaRequired(user *entity.User) bool {
+	return slices.Contains(uc.cfg.MFA.RequiredForRoles, user.Role)
+}
+
+func (uc *authUseCase) generateMFAToken(user *entity.User, purpose string) (string, error) {
+	// jti отличает токены одного пользователя для лимита попыток
+	challengeID, err := newOneTimeToken()
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"purpose": purpose,
+		"jti":     challengeID,
+		"exp":     time.Now().Add(uc.cfg.MFA.ChallengeTTL).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = uc.signingKey.KeyID()
+	return token.SignedString(uc.signingKey.PrivateKey())
+}
+
+// ParseMFAToken проверяет MFA токен, выданный Login, и возвращает ID пользователя.
+func (uc *authUseCase) ParseMFAToken(mfaToken, purpose string) (int, error) {
+	userID, _, err := uc.parseMFAToken(mfaToken, purpose)
+	return userID, err
+}
+
+func (uc *authUseCase) parseMFAToken(mfaToken, purpose string) (int, string, error) {
+	claims, err := uc.parseToken(mfaToken)
+	if err != nil {
+		return 0, "", ErrInvalidMFAToken
+	}
+
+	if tokenPurpose, _ := claims["purpose"].(string); tokenPurpose != purpose {
+		return 0, "", ErrInvalidMFAToken
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok || userID == 0 {
+		return 0, "", ErrInvalidMFAToken
+	}
+
+	challengeID, _ := claims["jti"].(string)
+	if challengeID == "" {
+		return 0, "", ErrInvalidMFAToken
+	}
+
+	return int(userID), challengeID, nil
+}
+
+func (uc *authUseCase) EnrollMFA(ctx context.Context, userID int) (*entity.MFAEnrollment, error) {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := uc.mfaCipher.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.mfaRepo.SetMFASecret(ctx, user.ID, encrypted); err != nil {
+		return nil, fmt.Errorf("failed to save mfa secret: %w", err)
+	}
+
+	return &entity.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(uc.cfg.MFA.Issuer, user.Username, secret),
+	}, nil
+}
+
+// ConfirmMFA включает MFA после проверки первого кода и возвращает коды
+// восстановления. При login == true сразу выдаёт пару токенов - так завершается
+// вход пользователя, которому MFA навязана ролью.
+func (uc *authUseCase) ConfirmMFA(ctx context.Context, userID int, code string, login bool) ([]string,
+	*entity.TokenPair, error) {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if user.MFAEnabled {
+		return nil, nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return nil, nil, ErrMFANotEnrolled
+	}
+
+	secret, err := uc.mfaCipher.Decrypt(user.MFASecret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return nil, nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := uc.generateRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.mfaRepo.EnableMFA(ctx, user.ID, step, hashes); err != nil {
+		return nil, nil, fmt.Errorf("failed to enable mfa: %w", err)
+	}
+
+	if !login {
+		return codes, nil, nil
+	}
+	uc.loginSucceeded(ctx, user.Username)
+
+	tokens, err := uc.issueTokens(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return codes, tokens, nil
+}
+
+// VerifyMFA завершает двухшаговый вход. Принимает код из приложения
+// или один из кодов восстановления. Неверные коды ограничены и на токен,
+// и общим счётчиком неудачных входов пользователя и IP.
+func (uc *authUseCase) VerifyMFA(ctx context.Context, mfaToken, code, ip string) (*entity.TokenPair, error) {
+	userID, challengeID, err := uc.parseMFAToken(mfaToken, MFAPurposeVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAttempts := uc.cfg.MFA.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMFAMaxAttempts
+	}
+	exhausted, err := uc.guard.ChallengeExhausted(ctx, challengeID, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if exhausted {
+		return nil, ErrMFAAttemptsExceeded
+	}
+
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if !user.MFAEnabled {
+		return nil, ErrMFANotEnabled
+	}
+
+	if err := uc.verifyMFACode(ctx, user, code, ip); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if err := uc.guard.ChallengeFailure(ctx, challengeID, uc.cfg.MFA.ChallengeTTL); err != nil {
+				log.Printf("Failed to record mfa failure: %v", err)
+			}
+		}
+		return nil, err
+	}
+
+	return uc.issueTokens(user)
+}
+
+func (uc *authUseCase) DisableMFA(ctx context.Context, userID int, password, code string) error {
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+	if uc.mfaRequired(user) {
+		return ErrMFARequiredForRole
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return ErrWrongPassword
+	}
+
+	if err := uc.verifyMFACode(ctx, user, code, ""); err != nil {
+		return err
+	}
+
+	return uc.mfaRepo.DisableMFA(ctx, user.ID)
+}
+
+// verifyMFACode проверяет код второго фактора через тот же lockout, что и
+// пароль: неверный код - неудачная попытка входа пользователя, счётчик
+// сбрасывается только после верного кода.
+func (uc *authUseCase) verifyMFACode(ctx context.Context, user *entity.User, code, ip string) error {
+	if err := uc.guard.Check(ctx, user.Username, ip); err != nil {
+		return err
+	}
+
+	if err := uc.checkMFACode(ctx, user, code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			uc.loginFailed(ctx, user.Username, ip)
+		}
+		return err
+	}
+
+	uc.loginSucceeded(ctx, user.Username)
+	return nil
+}
+
+func (uc *authUseCase) checkMFACode(ctx context.Context, user *entity.User, code string) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits {
+		secret, err := uc.mfaCipher.Decrypt(user.MFASecret)
+		if err != nil {
+			return err
+		}
+
+		step, ok := totp.Validate(secret, code, time.Now())
+		if !ok {
+			return ErrInvalidMFACode
+		}
+
+		fresh, err := uc.mfaRepo.UseTOTPStep(ctx, user.ID, step)
+		if err != nil {
+			return fmt.Errorf("failed to save mfa step: %w", err)
+		}
+		if !fresh {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := uc.mfaRepo.UseRecoveryCode(ctx, user.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (uc *authUseCase) generateRecoveryCodes() ([]string, []string, error) {
+	count := uc.cfg.MFA.RecoveryCodes
+	if count <= 0 {
+		count = 10
+	}
+
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		encoded := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw))[:10]
+		code := encoded[:5] + "-" + encoded[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code)))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
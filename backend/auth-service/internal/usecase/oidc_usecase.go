@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/oidc"
+	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/auth-service/internal/username"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUnknownProvider       = errors.New("unknown identity provider")
+	ErrInvalidOIDCState      = errors.New("login state is invalid or expired")
+	ErrOIDCAccountNotFound   = errors.New("no account is linked to this identity")
+	ErrIdentityLinkedToUser  = errors.New("identity is already linked to another account")
+	ErrProviderAlreadyLinked = errors.New("provider is already linked to this account")
+)
+
+type OIDCUseCase interface {
+	Providers() []entity.OIDCProvider
+	// StartLogin возвращает адрес страницы входа провайдера. При linkUserID != 0
+	// вход завершится привязкой провайдера к этому пользователю.
+	StartLogin(ctx context.Context, provider string, linkUserID int) (string, error)
+	// FinishLogin обрабатывает code и state из редиректа провайдера. Для привязки
+	// возвращает linked = true и пустые токены.
+	FinishLogin(ctx context.Context, state, code string) (tokens *entity.TokenPair, linked bool, err error)
+}
+
+type oidcUseCase struct {
+	userRepo  repo.AuthRepository
+	oidcRepo  repo.OIDCRepository
+	authUC    AuthUseCase
+	usernames *username.Policy
+	providers map[string]*oidc.Provider
+	order     []string
+	stateTTL  time.Duration
+}
+
+func NewOIDCUseCase(ur repo.AuthRepository, or repo.OIDCRepository, authUC AuthUseCase, usernames *username.Policy,
+	providers []*oidc.Provider, stateTTL time.Duration) OIDCUseCase {
+	uc := &oidcUseCase{
+		userRepo:  ur,
+		oidcRepo:  or,
+		authUC:    authUC,
+		usernames: usernames,
+		providers: make(map[string]*oidc.Provider, len(providers)),
+		stateTTL:  stateTTL,
+	}
+	for _, provider := range providers {
+		uc.providers[provider.Name()] = provider
+		uc.order = append(uc.order, provider.Name())
+	}
+	return uc
+}
+
+func (uc *oidcUseCase) Providers() []entity.OIDCProvider {
+	providers := make([]entity.OIDCProvider, 0, len(uc.order))
+	for _, name := range uc.order {
+		providers = append(providers, entity.OIDCProvider{
+			Name:        name,
+			DisplayName: uc.providers[name].DisplayName(),
+		})
+	}
+	return providers
+}
+
+func (uc *oidcUseCase) StartLogin(ctx context.Context, providerName string, linkUserID int) (string, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	// state уходит в браузер, поэтому в БД храним только хеш
+	err = uc.oidcRepo.CreateLoginState(ctx, hashToken(state), &entity.OIDCLoginState{
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		LinkUserID:   linkUserID,
+		ExpireAt:     time.Now().Add(uc.stateTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save login state: %w", err)
+	}
+
+	return provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+}
+
+func (uc *oidcUseCase) FinishLogin(ctx context.Context, state, code string) (*entity.TokenPair, bool, error) {
+	loginState, err := uc.oidcRepo.ConsumeLoginState(ctx, hashToken(state))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, ErrInvalidOIDCState
+		}
+		return nil, false, fmt.Errorf("database error: %w", err)
+	}
+
+	provider, ok := uc.providers[loginState.Provider]
+	if !ok {
+		return nil, false, ErrUnknownProvider
+	}
+
+	identity, err := provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, false, fmt.Errorf("oidc exchange failed: %w", err)
+	}
+
+	if loginState.LinkUserID != 0 {
+		return nil, true, uc.link(ctx, loginState.LinkUserID, provider, identity)
+	}
+
+	userID, err := uc.resolveUser(ctx, provider, identity)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tokens, err := uc.authUC.CompleteLogin(ctx, userID)
+	return tokens, false, err
+}
+
+func (uc *oidcUseCase) link(ctx context.Context, userID int, provider *oidc.Provider, identity *oidc.Identity) error {
+	existing, err := uc.oidcRepo.FindIdentity(ctx, provider.Name(), identity.Subject)
+	switch {
+	case err == nil && existing.UserID == userID:
+		return nil
+	case err == nil:
+		return ErrIdentityLinkedToUser
+	case !errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	err = uc.oidcRepo.LinkIdentity(ctx, &entity.OIDCIdentity{
+		UserID:   userID,
+		Provider: provider.Name(),
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if errors.Is(err, repo.ErrIdentityLinked) {
+		return ErrProviderAlreadyLinked
+	}
+	return err
+}
+
+// resolveUser находит пользователя по привязке, затем по подтверждённому email
+// (если разрешено), и в последнюю очередь регистрирует нового.
+func (uc *oidcUseCase) resolveUser(ctx context.Context, provider *oidc.Provider, identity *oidc.Identity) (int, error) {
+	existing, err := uc.oidcRepo.FindIdentity(ctx, provider.Name(), identity.Subject)
+	if err == nil {
+		if err := uc.oidcRepo.TouchIdentity(ctx, provider.Name(), identity.Subject); err != nil {
+			log.Printf("Failed to update identity last login: %v", err)
+		}
+		return existing.UserID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("database error: %w", err)
+	}
+
+	cfg := provider.Config()
+
+	// Локальный адрес тоже должен быть подтверждён: иначе можно заранее
+	// зарегистрироваться с чужим email и получить доступ после его входа через SSO
+	if cfg.LinkByEmail && identity.EmailVerified && identity.Email != "" {
+		user, err := uc.userRepo.GetUserByEmail(identity.Email)
+		if err == nil && user.EmailVerified {
+			if err := uc.link(ctx, user.ID, provider, identity); err != nil {
+				return 0, err
+			}
+			return user.ID, nil
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("database error: %w", err)
+		}
+	}
+
+	if !cfg.AutoRegister {
+		return 0, ErrOIDCAccountNotFound
+	}
+
+	user, err := uc.register(identity)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := uc.link(ctx, user.ID, provider, identity); err != nil {
+		return 0, err
+	}
+
+	return user.ID, nil
+}
+
+func (uc *oidcUseCase) register(identity *oidc.Identity) (*entity.User, error) {
+	// Пароль неизвестен никому: войти можно только через провайдера
+	// или после сброса пароля
+	randomPassword, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(randomPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	email := ""
+	if identity.EmailVerified {
+		exists, err := uc.userRepo.EmailExists(identity.Email)
+		if err != nil {
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+		if !exists {
+			email = identity.Email
+		}
+	}
+
+	base := usernameCandidate(identity)
+	for attempt := 0; attempt < 10; attempt++ {
+		name := base
+		if attempt > 0 {
+			suffix, err := rand.Int(rand.Reader, big.NewInt(10000))
+			if err != nil {
+				return nil, err
+			}
+			name = fmt.Sprintf("%s%d", base, suffix.Int64())
+		}
+		name = username.Normalize(name)
+
+		if len(uc.usernames.Check(name)) > 0 {
+			continue
+		}
+
+		skeleton := username.Skeleton(name)
+		exists, err := uc.userRepo.UserExists(name, skeleton)
+		if err != nil {
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		user := &entity.User{
+			Username:         name,
+			UsernameSkeleton: skeleton,
+			Email:            email,
+			EmailVerified:    email != "",
+			Password:         string(hashedPassword),
+			Role:             "user",
+		}
+		if err := uc.userRepo.CreateUser(user); err != nil {
+			if errors.Is(err, repo.ErrDuplicateUser) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+		return user, nil
+	}
+
+	return nil, errors.New("failed to pick a free username")
+}
+
+// usernameCandidate берёт preferred_username или локальную часть email
+// и оставляет только безопасные символы.
+func usernameCandidate(identity *oidc.Identity) string {
+	source := identity.PreferredUsername
+	if source == "" {
+		source, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	var b strings.Builder
+	for _, r := range source {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+
+	candidate := b.String()
+	if len([]rune(candidate)) > 24 {
+		candidate = string([]rune(candidate)[:24])
+	}
+	if len([]rune(candidate)) < 3 {
+		candidate = "user" + candidate
+	}
+	return candidate
+}
@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-forum-project/auth-service/internal/config"
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/oidc"
+	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/auth-service/internal/username"
+)
+
+// memoryUsers реализует только то, что нужно OIDC входу.
+type memoryUsers struct {
+	repo.AuthRepository
+	users []*entity.User
+}
+
+func (r *memoryUsers) GetUserByEmail(email string) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryUsers) EmailExists(email string) (bool, error) {
+	_, err := r.GetUserByEmail(email)
+	return err == nil, nil
+}
+
+func (r *memoryUsers) UserExists(name, skeleton string) (bool, error) {
+	for _, user := range r.users {
+		if user.Username == name || username.Skeleton(user.Username) == skeleton {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryUsers) CreateUser(user *entity.User) error {
+	user.ID = 100 + len(r.users)
+	r.users = append(r.users, user)
+	return nil
+}
+
+type memoryOIDC struct {
+	repo.OIDCRepository
+	states     map[string]*entity.OIDCLoginState
+	identities []*entity.OIDCIdentity
+}
+
+func (r *memoryOIDC) CreateLoginState(ctx context.Context, stateHash string, state *entity.OIDCLoginState) error {
+	r.states[stateHash] = state
+	return nil
+}
+
+func (r *memoryOIDC) ConsumeLoginState(ctx context.Context, stateHash string) (*entity.OIDCLoginState, error) {
+	state, ok := r.states[stateHash]
+	delete(r.states, stateHash)
+	if !ok || state.ExpireAt.Before(time.Now()) {
+		return nil, sql.ErrNoRows
+	}
+	return state, nil
+}
+
+func (r *memoryOIDC) FindIdentity(ctx context.Context, provider, subject string) (*entity.OIDCIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryOIDC) LinkIdentity(ctx context.Context, identity *entity.OIDCIdentity) error {
+	for _, existing := range r.identities {
+		if existing.UserID == identity.UserID && existing.Provider == identity.Provider {
+			return repo.ErrIdentityLinked
+		}
+	}
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *memoryOIDC) TouchIdentity(ctx context.Context, provider, subject string) error {
+	return nil
+}
+
+// loginRecorder запоминает, для кого завершён вход.
+type loginRecorder struct {
+	AuthUseCase
+	userIDs []int
+}
+
+func (a *loginRecorder) CompleteLogin(ctx context.Context, userID int) (*entity.TokenPair, error) {
+	a.userIDs = append(a.userIDs, userID)
+	return &entity.TokenPair{AccessToken: "access-" + strconv.Itoa(userID)}, nil
+}
+
+type oidcTest struct {
+	uc     OIDCUseCase
+	fake   *oidc.FakeProvider
+	users  *memoryUsers
+	oidc   *memoryOIDC
+	logins *loginRecorder
+}
+
+func newOIDCTest(t *testing.T, user oidc.FakeUser, autoRegister bool, users ...*entity.User) *oidcTest {
+	t.Helper()
+
+	var fake *oidc.FakeProvider
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	fake, err := oidc.NewFakeProvider(server.URL, "forum", "secret", user)
+	if err != nil {
+		t.Fatalf("NewFakeProvider: %v", err)
+	}
+
+	provider := oidc.NewProvider(config.OIDCProviderConfig{
+		Name:         "fake",
+		Issuer:       server.URL,
+		ClientID:     "forum",
+		ClientSecret: "secret",
+		RedirectURL:  "http://forum.test/oidc/callback",
+		LinkByEmail:  true,
+		AutoRegister: autoRegister,
+	})
+
+	policy, err := username.NewPolicy(config.UsernamePolicyConfig{MinLength: 3, MaxLength: 32})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+
+	test := &oidcTest{
+		fake:   fake,
+		users:  &memoryUsers{users: users},
+		oidc:   &memoryOIDC{states: make(map[string]*entity.OIDCLoginState)},
+		logins: &loginRecorder{},
+	}
+	test.uc = NewOIDCUseCase(test.users, test.oidc, test.logins, policy, []*oidc.Provider{provider}, time.Minute)
+	return test
+}
+
+// start начинает вход и проходит страницу провайдера.
+func (tt *oidcTest) start(t *testing.T, linkUserID int) (string, string) {
+	t.Helper()
+
+	authURL, err := tt.uc.StartLogin(context.Background(), "fake", linkUserID)
+	if err != nil {
+		t.Fatalf("StartLogin: %v", err)
+	}
+
+	code, state, err := tt.fake.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	return code, state
+}
+
+var oidcAlice = oidc.FakeUser{
+	Subject:           "sub-alice",
+	Email:             "alice@example.test",
+	EmailVerified:     true,
+	PreferredUsername: "alice",
+}
+
+func TestOIDCLinksExistingUserByVerifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	existing := &entity.User{ID: 7, Username: "alice", Email: "alice@example.test", EmailVerified: true}
+	tt := newOIDCTest(t, oidcAlice, false, existing)
+
+	code, state := tt.start(t, 0)
+	tokens, linked, err := tt.uc.FinishLogin(ctx, state, code)
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if linked || tokens.AccessToken != "access-7" {
+		t.Errorf("FinishLogin = %+v, linked %v, want tokens of user 7", tokens, linked)
+	}
+	if len(tt.oidc.identities) != 1 || tt.oidc.identities[0].UserID != 7 {
+		t.Fatalf("identities = %+v, want one linked to user 7", tt.oidc.identities)
+	}
+
+	// Следующий вход находит пользователя по привязке, даже если email сменился
+	existing.Email = "alice@other.test"
+	code, state = tt.start(t, 0)
+	if _, _, err := tt.uc.FinishLogin(ctx, state, code); err != nil {
+		t.Fatalf("second FinishLogin: %v", err)
+	}
+	if got := tt.logins.userIDs; len(got) != 2 || got[1] != 7 {
+		t.Errorf("logins = %v, want user 7 twice", got)
+	}
+}
+
+func TestOIDCDoesNotLinkUnverifiedEmail(t *testing.T) {
+	unverifiedProvider := oidcAlice
+	unverifiedProvider.EmailVerified = false
+
+	tests := []struct {
+		name     string
+		provider oidc.FakeUser
+		local    *entity.User
+	}{
+		{
+			name:     "local email not verified",
+			provider: oidcAlice,
+			local:    &entity.User{ID: 7, Username: "alice", Email: "alice@example.test"},
+		},
+		{
+			name:     "provider email not verified",
+			provider: unverifiedProvider,
+			local:    &entity.User{ID: 7, Username: "alice", Email: "alice@example.test", EmailVerified: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tt := newOIDCTest(t, tc.provider, false, tc.local)
+
+			code, state := tt.start(t, 0)
+			_, _, err := tt.uc.FinishLogin(context.Background(), state, code)
+			if !errors.Is(err, ErrOIDCAccountNotFound) {
+				t.Errorf("FinishLogin error = %v, want ErrOIDCAccountNotFound", err)
+			}
+			if len(tt.oidc.identities) != 0 || len(tt.logins.userIDs) != 0 {
+				t.Errorf("identity linked or login completed: %+v, %v", tt.oidc.identities, tt.logins.userIDs)
+			}
+		})
+	}
+}
+
+func TestOIDCAutoRegister(t *testing.T) {
+	taken := &entity.User{ID: 1, Username: "alice", Email: "someone@example.test", EmailVerified: true}
+	tt := newOIDCTest(t, oidcAlice, true, taken)
+
+	code, state := tt.start(t, 0)
+	if _, _, err := tt.uc.FinishLogin(context.Background(), state, code); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+
+	if len(tt.users.users) != 2 {
+		t.Fatalf("users = %d, want a new user", len(tt.users.users))
+	}
+	created := tt.users.users[1]
+	if created.Username == "alice" || created.Email != "alice@example.test" || !created.EmailVerified {
+		t.Errorf("created user = %+v, want a free name and the verified email", created)
+	}
+	if got := tt.logins.userIDs; len(got) != 1 || got[0] != created.ID {
+		t.Errorf("logins = %v, want user %d", got, created.ID)
+	}
+}
+
+func TestOIDCLinkToCurrentUser(t *testing.T) {
+	tt := newOIDCTest(t, oidcAlice, false)
+
+	code, state := tt.start(t, 3)
+	tokens, linked, err := tt.uc.FinishLogin(context.Background(), state, code)
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if !linked || tokens != nil {
+		t.Errorf("FinishLogin = %+v, linked %v, want linked without tokens", tokens, linked)
+	}
+	if len(tt.oidc.identities) != 1 || tt.oidc.identities[0].UserID != 3 {
+		t.Errorf("identities = %+v, want one linked to user 3", tt.oidc.identities)
+	}
+}
+
+func TestOIDCRejectsStateAndNonceMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown state", func(t *testing.T) {
+		tt := newOIDCTest(t, oidcAlice, true)
+		code, _ := tt.start(t, 0)
+
+		if _, _, err := tt.uc.FinishLogin(ctx, "forged-state", code); !errors.Is(err, ErrInvalidOIDCState) {
+			t.Errorf("FinishLogin error = %v, want ErrInvalidOIDCState", err)
+		}
+	})
+
+	t.Run("state reused", func(t *testing.T) {
+		tt := newOIDCTest(t, oidcAlice, true)
+		code, state := tt.start(t, 0)
+
+		if _, _, err := tt.uc.FinishLogin(ctx, state, code); err != nil {
+			t.Fatalf("FinishLogin: %v", err)
+		}
+		if _, _, err := tt.uc.FinishLogin(ctx, state, code); !errors.Is(err, ErrInvalidOIDCState) {
+			t.Errorf("second FinishLogin error = %v, want ErrInvalidOIDCState", err)
+		}
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		tt := newOIDCTest(t, oidcAlice, true)
+		code, state := tt.start(t, 0)
+
+		// ID токен выпущен для nonce, который сохранён при начале входа
+		for _, loginState := range tt.oidc.states {
+			loginState.Nonce = "another-nonce"
+		}
+
+		if _, _, err := tt.uc.FinishLogin(ctx, state, code); !errors.Is(err, oidc.ErrNonceMismatch) {
+			t.Errorf("FinishLogin error = %v, want ErrNonceMismatch", err)
+		}
+		if len(tt.users.users) != 0 || len(tt.logins.userIDs) != 0 {
+			t.Errorf("user created or logged in after nonce mismatch")
+		}
+	})
+}
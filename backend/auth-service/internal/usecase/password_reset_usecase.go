@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/password"
+	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrInvalidResetToken = errors.New("reset token is invalid or expired")
+
+type PasswordResetUseCase interface {
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type passwordResetUseCase struct {
+	userRepo  repo.AuthRepository
+	tokenRepo repo.TokenRepository
+	resetRepo repo.ResetTokenRepository
+	passwords *password.Policy
+	mailer    mailer.Mailer
+	tokenTTL  time.Duration
+	resetURL  string
+}
+
+func NewPasswordResetUseCase(ur repo.AuthRepository, tr repo.TokenRepository, rr repo.ResetTokenRepository,
+	passwords *password.Policy, m mailer.Mailer, tokenTTL time.Duration, frontendURL string) PasswordResetUseCase {
+	return &passwordResetUseCase{
+		userRepo:  ur,
+		tokenRepo: tr,
+		resetRepo: rr,
+		passwords: passwords,
+		mailer:    m,
+		tokenTTL:  tokenTTL,
+		resetURL:  strings.TrimRight(frontendURL, "/") + "/reset-password",
+	}
+}
+
+// RequestPasswordReset не сообщает, существует ли пользователь с таким email,
+// чтобы через этот метод нельзя было перебирать адреса.
+func (uc *passwordResetUseCase) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := uc.userRepo.GetUserByEmail(strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	// Действует только последняя выданная ссылка
+	if err := uc.resetRepo.DeleteUserResetTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to delete old reset tokens: %w", err)
+	}
+
+	token, err := newOneTimeToken()
+	if err != nil {
+		return err
+	}
+
+	if err := uc.resetRepo.CreateResetToken(ctx, user.ID, hashToken(token), time.Now().Add(uc.tokenTTL)); err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	link := uc.resetURL + "?token=" + url.QueryEscape(token)
+	err = uc.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Password reset",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone requested a password reset for your account.\n"+
+			"Follow the link below to choose a new password, it is valid for %s:\n\n%s\n\n"+
+			"If it wasn't you, just ignore this email.\n", user.Username, uc.tokenTTL, link),
+	})
+	// Ответ не должен отличаться от случая с неизвестным email, иначе по нему
+	// можно проверить, есть ли аккаунт
+	if err != nil {
+		log.Printf("Failed to send password reset mail to user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+func (uc *passwordResetUseCase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return ErrInvalidResetToken
+	}
+
+	tokenHash := hashToken(token)
+
+	userID, err := uc.resetRepo.FindResetToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	user, err := uc.userRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	// Проверяем пароль до того, как сжечь токен, чтобы при ошибке валидации
+	// пользователю не пришлось запрашивать новую ссылку.
+	if violations := uc.passwords.Check("new_password", newPassword, user.Username); len(violations) > 0 {
+		return &entity.ValidationError{Violations: violations}
+	}
+
+	if _, err := uc.resetRepo.ConsumeResetToken(ctx, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := uc.userRepo.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// После сброса завершаем все сессии, включая ту, что могла быть у злоумышленника
+	if err := uc.tokenRepo.DeleteUserRefreshTokens(ctx, user.ID, ""); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func newOneTimeToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
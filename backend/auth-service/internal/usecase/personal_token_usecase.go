@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"go-forum-project/auth-service/internal/config"
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/repo"
+)
+
+// PersonalTokenPrefix отличает персональные токены от JWT, в том числе
+// для сканеров секретов в репозиториях.
+const PersonalTokenPrefix = "gfp_"
+
+// Видимая часть токена, которая хранится открыто и показывается в списке
+const personalTokenVisibleLength = len(PersonalTokenPrefix) + 8
+
+var (
+	ErrInvalidPersonalToken  = errors.New("personal token is invalid, expired or revoked")
+	ErrPersonalTokenNotFound = errors.New("personal token not found")
+	ErrTooManyPersonalTokens = errors.New("personal token limit reached")
+)
+
+var personalTokenScopes = []string{entity.ScopeRead, entity.ScopePost, entity.ScopeChat}
+
+type PersonalTokenUseCase interface {
+	Create(ctx context.Context, userID int, name string, scopes []string, ttl time.Duration) (string,
+		*entity.PersonalToken, error)
+	List(ctx context.Context, userID int) ([]*entity.PersonalToken, error)
+	Revoke(ctx context.Context, userID, id int) error
+	Validate(ctx context.Context, token string) (*entity.TokenClaims, error)
+}
+
+type personalTokenUseCase struct {
+	userRepo  repo.AuthRepository
+	tokenRepo repo.PersonalTokenRepository
+	banRepo   repo.BanRepository
+	cfg       config.PersonalTokensConfig
+}
+
+func NewPersonalTokenUseCase(ur repo.AuthRepository, tr repo.PersonalTokenRepository, br repo.BanRepository,
+	cfg config.PersonalTokensConfig) PersonalTokenUseCase {
+	return &personalTokenUseCase{userRepo: ur, tokenRepo: tr, banRepo: br, cfg: cfg}
+}
+
+func IsPersonalToken(token string) bool {
+	return strings.HasPrefix(token, PersonalTokenPrefix)
+}
+
+func (uc *personalTokenUseCase) Create(ctx context.Context, userID int, name string, scopes []string,
+	ttl time.Duration) (string, *entity.PersonalToken, error) {
+	name = strings.TrimSpace(name)
+	scopes = normalizeScopes(scopes)
+
+	var violations []entity.FieldViolation
+	if name == "" || len([]rune(name)) > 100 {
+		violations = append(violations, entity.FieldViolation{Field: "name", Description: "must be 1-100 characters"})
+	}
+	if len(scopes) == 0 {
+		violations = append(violations, entity.FieldViolation{Field: "scopes", Description: "at least one scope is required"})
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(personalTokenScopes, scope) {
+			violations = append(violations, entity.FieldViolation{
+				Field:       "scopes",
+				Description: fmt.Sprintf("unknown scope %q, allowed: %s", scope, strings.Join(personalTokenScopes, ", ")),
+			})
+		}
+	}
+	if ttl < 0 || (uc.cfg.MaxTTL > 0 && ttl > uc.cfg.MaxTTL) {
+		violations = append(violations, entity.FieldViolation{Field: "expires_in_days", Description: "is out of range"})
+	}
+	if len(violations) > 0 {
+		return "", nil, &entity.ValidationError{Violations: violations}
+	}
+
+	// Без явного срока действует максимальный, если он ограничен
+	if ttl == 0 {
+		ttl = uc.cfg.MaxTTL
+	}
+
+	if uc.cfg.MaxPerUser > 0 {
+		count, err := uc.tokenRepo.CountPersonalTokens(ctx, userID)
+		if err != nil {
+			return "", nil, fmt.Errorf("database error: %w", err)
+		}
+		if count >= uc.cfg.MaxPerUser {
+			return "", nil, ErrTooManyPersonalTokens
+		}
+	}
+
+	raw := make([]byte, 30)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := PersonalTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	personalToken := &entity.PersonalToken{
+		UserID: userID,
+		Name:   name,
+		Prefix: token[:personalTokenVisibleLength],
+		Scopes: scopes,
+	}
+	if ttl > 0 {
+		personalToken.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	if err := uc.tokenRepo.CreatePersonalToken(ctx, personalToken, hashToken(token)); err != nil {
+		return "", nil, fmt.Errorf("failed to create personal token: %w", err)
+	}
+
+	return token, personalToken, nil
+}
+
+func (uc *personalTokenUseCase) List(ctx context.Context, userID int) ([]*entity.PersonalToken, error) {
+	return uc.tokenRepo.ListPersonalTokens(ctx, userID)
+}
+
+func (uc *personalTokenUseCase) Revoke(ctx context.Context, userID, id int) error {
+	revoked, err := uc.tokenRepo.RevokePersonalToken(ctx, userID, id)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if !revoked {
+		return ErrPersonalTokenNotFound
+	}
+	return nil
+}
+
+func (uc *personalTokenUseCase) Validate(ctx context.Context, token string) (*entity.TokenClaims, error) {
+	if !IsPersonalToken(token) {
+		return nil, ErrInvalidPersonalToken
+	}
+
+	personalToken, err := uc.tokenRepo.FindPersonalToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidPersonalToken
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	user, err := uc.userRepo.GetUserByID(personalToken.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := checkBan(ctx, uc.banRepo, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.tokenRepo.TouchPersonalToken(ctx, personalToken.ID); err != nil {
+		log.Printf("Failed to update personal token last use: %v", err)
+	}
+
+	return &entity.TokenClaims{
+		UserID:        user.ID,
+		Username:      user.Username,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
+		ExpiresAt:     personalToken.ExpiresAt,
+		Scopes:        personalToken.Scopes,
+	}, nil
+}
+
+func normalizeScopes(scopes []string) []string {
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope != "" && !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized
+}
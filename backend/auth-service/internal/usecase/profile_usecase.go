@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go-forum-project/auth-service/internal/avatar"
+	"go-forum-project/auth-service/internal/config"
+	"go-forum-project/auth-service/internal/entity"
+	"go-forum-project/auth-service/internal/repo"
+	"go-forum-project/auth-service/internal/username"
+)
+
+var (
+	ErrProfileNotFound   = errors.New("profile not found")
+	ErrUsernameUnchanged = errors.New("new username is the same as the current one")
+	ErrUsernameCooldown  = errors.New("username was changed recently")
+)
+
+// UsernameCooldownError возвращается, если имя меняли слишком недавно.
+// errors.Is(err, ErrUsernameCooldown) для него истинно.
+type UsernameCooldownError struct {
+	RetryAt time.Time
+}
+
+func (e *UsernameCooldownError) Error() string {
+	return fmt.Sprintf("username can be changed again after %s", e.RetryAt.UTC().Format(time.RFC3339))
+}
+
+func (e *UsernameCooldownError) Is(target error) bool {
+	return target == ErrUsernameCooldown
+}
+
+const (
+	maxDisplayNameLength = 64
+	maxBioLength         = 500
+	maxLocationLength    = 100
+	maxAvatarURLLength   = 512
+)
+
+type ProfileUseCase interface {
+	GetProfile(ctx context.Context, username string) (*entity.Profile, error)
+	UpdateProfile(ctx context.Context, userID int, update entity.ProfileUpdate) (*entity.Profile, error)
+	UploadAvatar(ctx context.Context, userID int, data []byte) (*entity.Profile, error)
+	ChangeUsername(ctx context.Context, userID int, name string) (*entity.Profile, error)
+}
+
+type profileUseCase struct {
+	profileRepo repo.ProfileRepository
+	historyRepo repo.UsernameHistoryRepository
+	usernames   *username.Policy
+	avatars     *avatar.Store
+	cfg         config.UsernameChangeConfig
+}
+
+func NewProfileUseCase(pr repo.ProfileRepository, hr repo.UsernameHistoryRepository, usernames *username.Policy,
+	avatars *avatar.Store, cfg config.UsernameChangeConfig) ProfileUseCase {
+	return &profileUseCase{
+		profileRepo: pr,
+		historyRepo: hr,
+		usernames:   usernames,
+		avatars:     avatars,
+		cfg:         cfg,
+	}
+}
+
+// GetProfile ищет по текущему имени, а затем по прежним. Во втором случае
+// в профиле будет уже новое имя, по нему клиент понимает, что нужен редирект.
+func (uc *profileUseCase) GetProfile(ctx context.Context, username string) (*entity.Profile, error) {
+	profile, err := uc.profileRepo.GetProfileByUsername(ctx, username)
+	if err == nil {
+		return profile, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	userID, err := uc.historyRepo.FindUserByPreviousUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	profile, err = uc.profileRepo.GetProfileByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return profile, nil
+}
+
+func (uc *profileUseCase) ChangeUsername(ctx context.Context, userID int, name string) (*entity.Profile, error) {
+	name = username.Normalize(strings.TrimSpace(name))
+	if violations := uc.usernames.Check(name); len(violations) > 0 {
+		return nil, &entity.ValidationError{Violations: violations}
+	}
+
+	current, err := uc.profileRepo.GetProfileByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if current.Username == name {
+		return nil, ErrUsernameUnchanged
+	}
+
+	lastChange, err := uc.historyRepo.LastChangedAt(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if !lastChange.IsZero() {
+		if retryAt := lastChange.Add(uc.cfg.Cooldown); time.Now().Before(retryAt) {
+			return nil, &UsernameCooldownError{RetryAt: retryAt}
+		}
+	}
+
+	skeleton := username.Skeleton(name)
+
+	available, err := uc.historyRepo.UsernameAvailable(ctx, userID, name, skeleton)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if !available {
+		return nil, ErrUsernameTaken
+	}
+
+	err = uc.historyRepo.ChangeUsername(ctx, userID, name, skeleton, time.Now().Add(uc.cfg.ReserveFor))
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicateUser) {
+			return nil, ErrUsernameTaken
+		}
+		return nil, fmt.Errorf("failed to change username: %w", err)
+	}
+
+	return uc.profileRepo.GetProfileByID(ctx, userID)
+}
+
+func (uc *profileUseCase) UpdateProfile(ctx context.Context, userID int,
+	update entity.ProfileUpdate) (*entity.Profile, error) {
+	var violations []entity.FieldViolation
+
+	trim := func(field string, value *string, maxLength int, multiline bool) {
+		if value == nil {
+			return
+		}
+		*value = strings.TrimSpace(*value)
+		if utf8.RuneCountInString(*value) > maxLength {
+			violations = append(violations, entity.FieldViolation{
+				Field:       field,
+				Description: fmt.Sprintf("must be at most %d characters", maxLength),
+			})
+		}
+		if hasControlChars(*value, multiline) {
+			violations = append(violations, entity.FieldViolation{Field: field, Description: "contains invalid characters"})
+		}
+	}
+
+	trim("display_name", update.DisplayName, maxDisplayNameLength, false)
+	trim("bio", update.Bio, maxBioLength, true)
+	trim("location", update.Location, maxLocationLength, false)
+	trim("avatar_url", update.AvatarURL, maxAvatarURLLength, false)
+
+	if update.AvatarURL != nil && *update.AvatarURL != "" && !validAvatarURL(*update.AvatarURL) {
+		violations = append(violations, entity.FieldViolation{Field: "avatar_url", Description: "must be an http(s) URL"})
+	}
+
+	if len(violations) > 0 {
+		return nil, &entity.ValidationError{Violations: violations}
+	}
+
+	var previous *entity.Profile
+	if update.AvatarURL != nil {
+		var err error
+		previous, err = uc.profileRepo.GetProfileByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+	}
+
+	if err := uc.profileRepo.UpdateProfile(ctx, userID, update); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	if previous != nil && previous.AvatarURL != *update.AvatarURL {
+		uc.deleteAvatar(previous.AvatarURL)
+	}
+
+	return uc.profileRepo.GetProfileByID(ctx, userID)
+}
+
+func (uc *profileUseCase) UploadAvatar(ctx context.Context, userID int, data []byte) (*entity.Profile, error) {
+	previous, err := uc.profileRepo.GetProfileByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	avatarURL, err := uc.avatars.Save(userID, data)
+	if err != nil {
+		if errors.Is(err, avatar.ErrTooLarge) || errors.Is(err, avatar.ErrUnsupportedType) {
+			return nil, &entity.ValidationError{Violations: []entity.FieldViolation{
+				{Field: "data", Description: err.Error()},
+			}}
+		}
+		return nil, err
+	}
+
+	if err := uc.profileRepo.UpdateProfile(ctx, userID, entity.ProfileUpdate{AvatarURL: &avatarURL}); err != nil {
+		uc.deleteAvatar(avatarURL)
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	uc.deleteAvatar(previous.AvatarURL)
+
+	return uc.profileRepo.GetProfileByID(ctx, userID)
+}
+
+func (uc *profileUseCase) deleteAvatar(avatarURL string) {
+	if avatarURL == "" {
+		return
+	}
+	if err := uc.avatars.Delete(avatarURL); err != nil {
+		log.Printf("Failed to delete avatar %s: %v", avatarURL, err)
+	}
+}
+
+func validAvatarURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// hasControlChars разрешает переводы строк только в многострочных полях.
+func hasControlChars(value string, multiline bool) bool {
+	for _, r := range value {
+		if multiline && (r == '\n' || r == '\r' || r == '\t') {
+			continue
+		}
+		if unicode.IsControl(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package userdata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-forum-project/auth-service/internal/config"
+	"go-forum-project/events"
+)
+
+// Client ходит в служебный API сервиса, который хранит данные пользователя:
+// GET и DELETE /internal/users/{userId}/data, GET /internal/audit.
+type Client struct {
+	name    string
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(cfg config.DataServiceConfig, token string) *Client {
+	return &Client{
+		name:    cfg.Name,
+		baseURL: strings.TrimRight(cfg.URL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewClients создаёт клиентов для всех сервисов из конфига.
+func NewClients(cfg config.AccountsConfig) []*Client {
+	clients := make([]*Client, 0, len(cfg.DataServices))
+	for _, service := range cfg.DataServices {
+		clients = append(clients, NewClient(service, cfg.InternalToken))
+	}
+	return clients
+}
+
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Export возвращает данные пользователя в том виде, в каком их отдал сервис.
+func (c *Client) Export(ctx context.Context, userID int) (json.RawMessage, error) {
+	resp, err := c.do(ctx, http.MethodGet, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read response: %w", c.name, err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s: invalid json in response", c.name)
+	}
+	return data, nil
+}
+
+// Erase удаляет или обезличивает данные пользователя. Повторный вызов безопасен.
+func (c *Client) Erase(ctx context.Context, userID int) error {
+	resp, err := c.do(ctx, http.MethodDelete, userID)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// AuditAfter возвращает записи журнала сервиса с ID больше afterID по возрастанию.
+func (c *Client) AuditAfter(ctx context.Context, afterID int64, limit int) ([]events.AuditRecorded, error) {
+	endpoint := fmt.Sprintf("%s/internal/audit?after=%d&limit=%d", c.baseURL, afterID, limit)
+	resp, err := c.request(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Entries []events.AuditRecorded `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%s: invalid audit response: %w", c.name, err)
+	}
+	return body.Entries, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, userID int) (*http.Response, error) {
+	return c.request(ctx, method, fmt.Sprintf("%s/internal/users/%d/data", c.baseURL, userID))
+}
+
+func (c *Client) request(ctx context.Context, method, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Internal-Token", c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.name, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: unexpected status %s", c.name, resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,89 @@
+package username
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-forum-project/auth-service/internal/config"
+	"go-forum-project/auth-service/internal/entity"
+	"golang.org/x/text/unicode/norm"
+)
+
+const defaultAllowedPattern = `^[\p{L}\p{N}_.-]+$`
+
+type Policy struct {
+	minLength int
+	maxLength int
+	allowed   *regexp.Regexp
+	reserved  map[string]struct{}
+}
+
+func NewPolicy(cfg config.UsernamePolicyConfig) (*Policy, error) {
+	pattern := cfg.AllowedPattern
+	if pattern == "" {
+		pattern = defaultAllowedPattern
+	}
+
+	allowed, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid username pattern: %w", err)
+	}
+
+	policy := &Policy{
+		minLength: cfg.MinLength,
+		maxLength: cfg.MaxLength,
+		allowed:   allowed,
+		reserved:  make(map[string]struct{}, len(cfg.Reserved)),
+	}
+
+	if policy.minLength <= 0 {
+		policy.minLength = 1
+	}
+	if policy.maxLength <= 0 {
+		policy.maxLength = 255
+	}
+
+	// Зарезервированные имена сравниваются по скелету, чтобы "аdmin"
+	// с кириллической "а" тоже считался занятым.
+	for _, name := range cfg.Reserved {
+		policy.reserved[Skeleton(name)] = struct{}{}
+	}
+
+	return policy, nil
+}
+
+// Normalize приводит имя к NFKC, чтобы полноширинные и составные символы
+// хранились в одном виде. Регистр сохраняется для отображения.
+func Normalize(username string) string {
+	return norm.NFKC.String(username)
+}
+
+// Check проверяет уже нормализованное имя пользователя.
+func (p *Policy) Check(username string) []entity.FieldViolation {
+	var violations []entity.FieldViolation
+	violate := func(description string) {
+		violations = append(violations, entity.FieldViolation{Field: "username", Description: description})
+	}
+
+	length := utf8.RuneCountInString(username)
+	if length < p.minLength || length > p.maxLength {
+		violate(fmt.Sprintf("must be between %d and %d characters long", p.minLength, p.maxLength))
+	}
+
+	if strings.IndexFunc(username, func(r rune) bool {
+		return unicode.IsControl(r) || unicode.IsSpace(r) || unicode.Is(unicode.Cf, r)
+	}) >= 0 {
+		violate("must not contain whitespace or control characters")
+	} else if username != "" && !p.allowed.MatchString(username) {
+		violate("contains characters that are not allowed")
+	}
+
+	if _, ok := p.reserved[Skeleton(username)]; ok {
+		violate("is reserved")
+	}
+
+	return violations
+}
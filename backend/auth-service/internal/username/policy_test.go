@@ -0,0 +1,47 @@
+package username
+
+import (
+	"testing"
+
+	"go-forum-project/auth-service/internal/config"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	policy, err := NewPolicy(config.UsernamePolicyConfig{
+		MinLength: 3,
+		MaxLength: 10,
+		Reserved:  []string{"admin"},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		valid    bool
+	}{
+		{name: "latin", username: "alice", valid: true},
+		{name: "unicode letters", username: "Алиса", valid: true},
+		{name: "punctuation", username: "a.b-c_d", valid: true},
+		{name: "empty", username: "", valid: false},
+		{name: "too short", username: "al", valid: false},
+		{name: "too long", username: "abcdefghijk", valid: false},
+		{name: "space", username: "al ice", valid: false},
+		{name: "control char", username: "ali\x00ce", valid: false},
+		{name: "zero width", username: "ali\u200bce", valid: false},
+		{name: "not allowed char", username: "alice!", valid: false},
+		{name: "reserved", username: "admin", valid: false},
+		{name: "reserved in other case", username: "Admin", valid: false},
+		{name: "reserved lookalike", username: "аdmin", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := policy.Check(Normalize(tt.username))
+			if valid := len(violations) == 0; valid != tt.valid {
+				t.Errorf("Check(%q) = %v, want valid = %v", tt.username, violations, tt.valid)
+			}
+		})
+	}
+}
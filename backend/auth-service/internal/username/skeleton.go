@@ -0,0 +1,49 @@
+package username
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables - сокращённая таблица из Unicode TR39 (confusables.txt):
+// символы других письменностей, которые выглядят как латиница.
+var confusables = map[rune]rune{
+	// Кириллица
+	'а': 'a', 'в': 'b', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x',
+	'ѕ': 's', 'і': 'i', 'ј': 'j', 'һ': 'h', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'ӏ': 'l',
+	'ь': 'b', 'п': 'n', 'г': 'r', 'к': 'k', 'т': 't', 'м': 'm', 'н': 'h', 'ё': 'e',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P',
+	'С': 'C', 'Т': 'T', 'Х': 'X', 'Ѕ': 'S', 'І': 'l', 'Ј': 'J', 'У': 'Y', 'Ԁ': 'D',
+	'Ӏ': 'l', 'Ь': 'b', 'З': '3',
+	// Греческий
+	'α': 'a', 'ο': 'o', 'ν': 'v', 'ρ': 'p', 'ι': 'i', 'κ': 'k', 'υ': 'u', 'χ': 'x',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'l', 'Κ': 'K', 'Μ': 'M',
+	'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+	// Латиница и цифры
+	'0': 'O', '1': 'l', 'I': 'l', '|': 'l', 'ı': 'i', 'ȷ': 'j', 'ɡ': 'g', 'ɑ': 'a',
+	'ʏ': 'y', 'ᴠ': 'v', 'ᴡ': 'w', 'ᴢ': 'z',
+}
+
+// Последовательности, которые в большинстве шрифтов читаются как одна буква.
+var sequenceReplacer = strings.NewReplacer("rn", "m", "vv", "w")
+
+// Skeleton строит "скелет" имени по мотивам UTS #39: у визуально похожих
+// имён скелеты совпадают. Скелет хранится в БД под уникальным индексом.
+func Skeleton(username string) string {
+	decomposed := norm.NFKD.String(username)
+
+	var b strings.Builder
+	for _, r := range decomposed {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if mapped, ok := confusables[r]; ok {
+			r = mapped
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return sequenceReplacer.Replace(b.String())
+}
@@ -0,0 +1,43 @@
+package username
+
+import "testing"
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{name: "case", a: "Alice", b: "alice", same: true},
+		{name: "cyrillic a", a: "аdmin", b: "admin", same: true},
+		{name: "greek omicron", a: "gοοgle", b: "google", same: true},
+		{name: "digit one and l", a: "pay1", b: "payl", same: true},
+		{name: "capital I and l", a: "PaypaI", b: "paypal", same: true},
+		{name: "zero and O", a: "r00t", b: "root", same: true},
+		{name: "rn and m", a: "rnoderator", b: "moderator", same: true},
+		{name: "vv and w", a: "vvalter", b: "walter", same: true},
+		{name: "combining accent", a: "josé", b: "jose", same: true},
+		{name: "precomposed accent", a: "josé", b: "jose", same: true},
+		{name: "fullwidth", a: "ｂｏｂ", b: "bob", same: true},
+		{name: "different names", a: "alice", b: "alicia", same: false},
+		{name: "different letters", a: "bob", b: "rob", same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := Skeleton(tt.a), Skeleton(tt.b)
+			if (a == b) != tt.same {
+				t.Errorf("Skeleton(%q) = %q, Skeleton(%q) = %q, want same = %v", tt.a, a, tt.b, b, tt.same)
+			}
+		})
+	}
+}
+
+func TestSkeletonIsIdempotent(t *testing.T) {
+	for _, name := range []string{"Alice", "аdmin", "rnoderator", "ｂｏｂ", "I1l|"} {
+		once := Skeleton(name)
+		if twice := Skeleton(once); twice != once {
+			t.Errorf("Skeleton(Skeleton(%q)) = %q, want %q", name, twice, once)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+-- Имена, которые отличаются только регистром, автоматически не разводятся:
+-- переименование сломало бы вход их владельцам. Переименуйте лишние аккаунты
+-- вручную, затем сбросьте версию (-action force -steps 2) и повторите миграцию.
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(names, '; ') INTO duplicates
+    FROM (
+        SELECT string_agg(username || ' (id ' || id || ')', ', ' ORDER BY id) AS names
+        FROM users
+        GROUP BY LOWER(username)
+        HAVING COUNT(*) > 1
+    ) groups;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'usernames differ only in case, rename all but one in each group: %', duplicates;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX idx_users_username_lower ON users (LOWER(username));
+
+-- Скелеты существующих имён заполняет мигратор через username.Skeleton,
+-- уникальный индекс по ним создаёт миграция 000016.
+ALTER TABLE users ADD COLUMN username_skeleton VARCHAR(255);
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE login_attempts (
+    key VARCHAR(300) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP
+);
+
+CREATE INDEX idx_login_attempts_last_failure ON login_attempts(last_failure);
+
+CREATE TABLE login_lockouts (
+    id SERIAL PRIMARY KEY,
+    scope VARCHAR(16) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    ip VARCHAR(64) NOT NULL,
+    failures INTEGER NOT NULL,
+    locked_until TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_lockouts_created_at ON login_lockouts(created_at);
\ No newline at end of file
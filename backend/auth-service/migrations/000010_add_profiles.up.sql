@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(64),
+    ADD COLUMN bio TEXT,
+    ADD COLUMN avatar_url VARCHAR(512),
+    ADD COLUMN location VARCHAR(100);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_role;
+
+DROP TABLE IF EXISTS user_bans;
\ No newline at end of file
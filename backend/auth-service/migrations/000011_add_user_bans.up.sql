@@ -0,0 +1,14 @@
+CREATE TABLE user_bans (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    banned_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expire_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    revoked_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_bans_user_id ON user_bans(user_id);
+
+CREATE INDEX idx_users_role ON users(role);
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE username_history (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    username VARCHAR(255) NOT NULL,
+    username_skeleton VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- До этого момента имя не может занять другой пользователь
+    reserved_until TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_username_history_user_id ON username_history(user_id);
+CREATE INDEX idx_username_history_username_lower ON username_history(LOWER(username));
+CREATE INDEX idx_username_history_skeleton ON username_history(username_skeleton);
\ No newline at end of file
@@ -0,0 +1,35 @@
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    service VARCHAR(20) NOT NULL,
+    -- ID записи в audit_log сервиса, откуда она забрана. NULL у записей
+    -- самого auth-service
+    source_id BIGINT,
+    actor_id INTEGER NOT NULL,
+    actor_role VARCHAR(20) NOT NULL DEFAULT '',
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    before_state JSONB,
+    after_state JSONB,
+    occurred_at TIMESTAMP NOT NULL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Повторно забранная запись не создаёт дубликат
+CREATE UNIQUE INDEX idx_audit_log_source ON audit_log(service, source_id);
+CREATE INDEX idx_audit_log_occurred_at ON audit_log(occurred_at);
+CREATE INDEX idx_audit_log_actor_id ON audit_log(actor_id);
+CREATE INDEX idx_audit_log_action ON audit_log(action);
+CREATE INDEX idx_audit_log_target ON audit_log(target_type, target_id);
+
+-- Журнал только дополняется: изменить или удалить запись нельзя
+CREATE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_username_skeleton;
+
+ALTER TABLE users ALTER COLUMN username_skeleton DROP NOT NULL;
\ No newline at end of file
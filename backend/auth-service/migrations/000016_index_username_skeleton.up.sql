@@ -0,0 +1,3 @@
+ALTER TABLE users ALTER COLUMN username_skeleton SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_users_username_skeleton ON users (username_skeleton);
\ No newline at end of file
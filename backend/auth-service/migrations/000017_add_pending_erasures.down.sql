@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pending_erasures;
\ No newline at end of file
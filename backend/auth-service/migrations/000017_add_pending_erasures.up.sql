@@ -0,0 +1,9 @@
+-- Сервисы, в которых ещё не стёрты данные удалённого пользователя
+CREATE TABLE pending_erasures (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    service VARCHAR(50) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, service)
+);
\ No newline at end of file
@@ -3,14 +3,22 @@ package app
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"go-forum-project/chat-service/internal/client"
 	"go-forum-project/chat-service/internal/config"
 	"go-forum-project/chat-service/internal/delivery/handler"
+	"go-forum-project/chat-service/internal/delivery/subscriber"
 	"go-forum-project/chat-service/internal/repo"
 	"go-forum-project/chat-service/internal/usecase"
+	"go-forum-project/contentfilter"
+	"go-forum-project/events"
+	"go-forum-project/ratelimit"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
@@ -26,18 +34,51 @@ func RunChat() {
 	}
 	defer db.Close()
 
-	messageRepo := repo.NewMessageRepo(db)
-	messageUC := usecase.NewMessageUseCase(messageRepo)
-
 	authClient, err := client.NewAuthClient(context.Background(), cfg)
 	if err != nil {
 		log.Fatalf("Failed to create auth client: %v", err)
 	}
 	defer authClient.Close()
 
-	hub := handler.NewHub(messageUC)
+	messageRepo := repo.NewMessageRepo(db)
+	auditRepo := repo.NewAuditRepo(db)
+	relationRepo := repo.NewRelationRepo(db)
+	contentFilter, err := contentfilter.NewReloader(cfg.ContentFilter, authClient)
+	if err != nil {
+		log.Fatalf("Failed to load content filter: %v", err)
+	}
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go contentFilter.Watch(relayCtx)
+
+	messageUC := usecase.NewMessageUseCase(messageRepo, authClient, contentFilter, relationRepo)
+	userDataUC := usecase.NewUserDataUseCase(messageRepo, relationRepo, cfg.Accounts)
+	relationUC := usecase.NewRelationUseCase(relationRepo)
+	auditUC := usecase.NewAuditUseCase(auditRepo)
+
+	bus, err := events.NewBus(cfg.Events)
+	if err != nil {
+		log.Fatalf("Failed to create event bus: %v", err)
+	}
+	defer bus.Close()
+
+	rateLimitStore, err := ratelimit.NewStore(cfg.RateLimit, db)
+	if err != nil {
+		log.Fatalf("Failed to create rate limit store: %v", err)
+	}
+	limiter, err := ratelimit.NewLimiter(cfg.RateLimit, rateLimitStore)
+	if err != nil {
+		log.Fatalf("Failed to create rate limiter: %v", err)
+	}
+	go limiter.Run(relayCtx)
+
+	hub := handler.NewHub(messageUC, cfg.Notifications, limiter, relationUC)
 	go hub.Run()
 
+	subscriber.Register(bus, userDataUC, relationUC, hub, authClient)
+	go events.NewRelay(db, bus, "chat", cfg.Events).Run(relayCtx)
+
 	go func() {
 		ticker := time.NewTicker(1 * time.Hour)
 		for range ticker.C {
@@ -49,9 +90,41 @@ func RunChat() {
 
 	http.Handle("/ws", enableCORS(handler.ServeWs(hub, authClient)))
 	http.Handle("/api/messages", enableCORS(handler.GetMessageHandler(messageUC)))
+	http.Handle("GET /internal/users/{userId}/data", handler.ExportUserDataHandler(userDataUC, cfg.Internal.Token))
+	http.Handle("DELETE /internal/users/{userId}/data", handler.EraseUserDataHandler(userDataUC, cfg.Internal.Token))
+	http.Handle("GET /internal/messages/{messageId}", handler.GetMessageInternalHandler(messageUC, cfg.Internal.Token))
+	http.Handle("DELETE /internal/messages/{messageId}", handler.RemoveMessageInternalHandler(hub, cfg.Internal.Token))
+	http.Handle("POST /internal/messages/{messageId}/release",
+		handler.ReleaseMessageInternalHandler(hub, cfg.Internal.Token))
+	http.Handle("GET /internal/audit", handler.AuditLogHandler(auditUC, cfg.Internal.Token))
+	http.Handle("GET /internal/debug/auth-client", handler.AuthClientMetricsHandler(authClient, cfg.Internal.Token))
+
+	server := &http.Server{Addr: fmt.Sprintf(":%d", cfg.Server.Port)}
 
-	log.Printf("Server started on : %d", cfg.Server.Port)
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", cfg.Server.Port), nil))
+	done := make(chan error, 1)
+	go func() {
+		log.Printf("Server started on : %d", cfg.Server.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			done <- fmt.Errorf("failed to listen on port %d: %v", cfg.Server.Port, err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case <-quit:
+		log.Println("Shutting down chat server...")
+	case err := <-done:
+		log.Fatalf("Failed to serve chat: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to shutdown chat server: %v", err)
+	}
 }
 
 func enableCORS(next http.Handler) http.Handler {
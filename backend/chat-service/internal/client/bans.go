@@ -0,0 +1,72 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// banList - заблокированные пользователи по событиям auth-service. Нужен,
+// чтобы блокировка действовала сразу, а не после истечения access токена.
+type banList struct {
+	mu sync.RWMutex
+	// нулевое время - бессрочная блокировка
+	until map[int]time.Time
+	// удалённые аккаунты, снятие блокировки их не касается
+	deleted map[int]bool
+}
+
+func newBanList() *banList {
+	return &banList{until: make(map[int]time.Time), deleted: make(map[int]bool)}
+}
+
+func (b *banList) Ban(userID int, until time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.until[userID] = until
+}
+
+func (b *banList) Unban(userID int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.until, userID)
+}
+
+func (b *banList) Delete(userID int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deleted[userID] = true
+}
+
+func (b *banList) IsBanned(userID int) bool {
+	b.mu.RLock()
+	until, ok := b.until[userID]
+	deleted := b.deleted[userID]
+	b.mu.RUnlock()
+
+	if deleted {
+		return true
+	}
+	if !ok {
+		return false
+	}
+	if !until.IsZero() && time.Now().After(until) {
+		b.Unban(userID)
+		return false
+	}
+	return true
+}
+
+// BanUser отклоняет токены пользователя до until (нулевое - бессрочно).
+func (c *AuthClient) BanUser(userID int, until time.Time) {
+	c.bans.Ban(userID, until)
+}
+
+func (c *AuthClient) UnbanUser(userID int) {
+	c.bans.Unban(userID)
+}
+
+// DeleteUser навсегда отклоняет токены удалённого пользователя, выданные
+// до удаления и ещё лежащие в кеше.
+func (c *AuthClient) DeleteUser(userID int) {
+	c.bans.Delete(userID)
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	pb "go-forum-project/proto/gRPC"
+)
+
+const keyAlgorithm = "EdDSA"
+
+var errUnknownKey = errors.New("unknown signing key")
+
+// keyStore хранит опубликованные auth-service публичные ключи и проверяет
+// access токены локально.
+type keyStore struct {
+	mu              sync.RWMutex
+	keys            map[string]ed25519.PublicKey
+	lastRefresh     time.Time
+	refreshInterval time.Duration
+	client          pb.AuthServiceClient
+}
+
+func newKeyStore(client pb.AuthServiceClient, refreshInterval time.Duration) *keyStore {
+	return &keyStore{
+		keys:            make(map[string]ed25519.PublicKey),
+		refreshInterval: refreshInterval,
+		client:          client,
+	}
+}
+
+func (s *keyStore) Refresh(ctx context.Context) error {
+	resp, err := s.client.GetPublicKeys(ctx, &pb.GetPublicKeysRequest{})
+	if err != nil {
+		return fmt.Errorf("get public keys error: %w", err)
+	}
+
+	keys := make(map[string]ed25519.PublicKey, len(resp.Keys))
+	for _, key := range resp.Keys {
+		if key.Algorithm != keyAlgorithm {
+			continue
+		}
+
+		parsed, err := x509.ParsePKIXPublicKey(key.PublicKey)
+		if err != nil {
+			log.Printf("Failed to parse public key %s: %v", key.KeyId, err)
+			continue
+		}
+
+		publicKey, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			continue
+		}
+		keys[key.KeyId] = publicKey
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.lastRefresh = time.Now()
+	s.mu.Unlock()
+
+	return nil
+}
+
+// RunRefresh периодически обновляет ключи, пока не отменён контекст.
+func (s *keyStore) RunRefresh(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("Failed to load auth public keys: %v", err)
+	}
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Printf("Failed to refresh auth public keys: %v", err)
+			}
+		}
+	}
+}
+
+func (s *keyStore) key(ctx context.Context, keyID string) (ed25519.PublicKey, bool) {
+	s.mu.RLock()
+	publicKey, ok := s.keys[keyID]
+	lastRefresh := s.lastRefresh
+	s.mu.RUnlock()
+	if ok {
+		return publicKey, true
+	}
+
+	// Неизвестный kid может означать ротацию ключа, но чаще всего это мусор,
+	// поэтому ходим за ключами не чаще раза в несколько секунд.
+	if time.Since(lastRefresh) < 5*time.Second {
+		return nil, false
+	}
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("Failed to refresh auth public keys: %v", err)
+		return nil, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	publicKey, ok = s.keys[keyID]
+	return publicKey, ok
+}
+
+// Verify проверяет подпись и срок действия токена. Возвращает errUnknownKey,
+// если токен подписан ключом, которого нет в хранилище.
+func (s *keyStore) Verify(ctx context.Context, accessToken string) (*TokenInfo, time.Time, error) {
+	var unknownKey bool
+	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		publicKey, ok := s.key(ctx, keyID)
+		if !ok {
+			unknownKey = true
+			return nil, errUnknownKey
+		}
+		return publicKey, nil
+	}, jwt.WithValidMethods([]string{keyAlgorithm}))
+	if unknownKey {
+		return nil, time.Time{}, errUnknownKey
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, time.Time{}, errors.New("invalid token claims")
+	}
+
+	// MFA токены подписаны тем же ключом, но доступа не дают
+	if _, ok := claims["purpose"]; ok {
+		return nil, time.Time{}, errors.New("not an access token")
+	}
+
+	username, ok := claims["username"].(string)
+	if !ok || username == "" {
+		return nil, time.Time{}, errors.New("username not found in token")
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil, time.Time{}, errors.New("expiration not found in token")
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok || userID <= 0 {
+		return nil, time.Time{}, errors.New("user id not found in token")
+	}
+
+	emailVerified, _ := claims["email_verified"].(bool)
+	role, _ := claims["role"].(string)
+
+	return &TokenInfo{
+		UserID:        int(userID),
+		Username:      username,
+		EmailVerified: emailVerified,
+		Role:          role,
+	}, expiresAt.Time, nil
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// tokenCache - LRU кэш результатов ValidateToken. Ключом служит хэш токена,
+// чтобы сами токены не лежали в памяти дольше необходимого.
+type tokenCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	items   map[[sha256.Size]byte]*list.Element
+	order   *list.List
+	metrics *Metrics
+}
+
+type cacheEntry struct {
+	key       [sha256.Size]byte
+	info      *TokenInfo
+	expiresAt time.Time
+}
+
+func newTokenCache(size int, ttl time.Duration, metrics *Metrics) *tokenCache {
+	return &tokenCache{
+		size:    size,
+		ttl:     ttl,
+		items:   make(map[[sha256.Size]byte]*list.Element),
+		order:   list.New(),
+		metrics: metrics,
+	}
+}
+
+func (c *tokenCache) Get(token string) (*TokenInfo, bool) {
+	key := sha256.Sum256([]byte(token))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		c.metrics.cacheMisses.Add(1)
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		c.metrics.cacheMisses.Add(1)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.metrics.cacheHits.Add(1)
+	return entry.info, true
+}
+
+// Set сохраняет результат не дольше ttl и не дольше срока жизни самого токена.
+func (c *tokenCache) Set(token string, info *TokenInfo, tokenExpiresAt time.Time) {
+	expiresAt := time.Now().Add(c.ttl)
+	if !tokenExpiresAt.IsZero() && tokenExpiresAt.Before(expiresAt) {
+		expiresAt = tokenExpiresAt
+	}
+	if !expiresAt.After(time.Now()) {
+		return
+	}
+
+	key := sha256.Sum256([]byte(token))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.info = info
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&cacheEntry{
+		key:       key,
+		info:      info,
+		expiresAt: expiresAt,
+	})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+		c.metrics.cacheEvictions.Add(1)
+	}
+}
+
+type Metrics struct {
+	cacheHits         atomic.Int64
+	cacheMisses       atomic.Int64
+	cacheEvictions    atomic.Int64
+	localValidations  atomic.Int64
+	remoteValidations atomic.Int64
+	unknownKeys       atomic.Int64
+}
+
+type MetricsSnapshot struct {
+	CacheHits         int64   `json:"cache_hits"`
+	CacheMisses       int64   `json:"cache_misses"`
+	CacheEvictions    int64   `json:"cache_evictions"`
+	CacheHitRate      float64 `json:"cache_hit_rate"`
+	LocalValidations  int64   `json:"local_validations"`
+	RemoteValidations int64   `json:"remote_validations"`
+	UnknownKeys       int64   `json:"unknown_keys"`
+}
+
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	snapshot := MetricsSnapshot{
+		CacheHits:         m.cacheHits.Load(),
+		CacheMisses:       m.cacheMisses.Load(),
+		CacheEvictions:    m.cacheEvictions.Load(),
+		LocalValidations:  m.localValidations.Load(),
+		RemoteValidations: m.remoteValidations.Load(),
+		UnknownKeys:       m.unknownKeys.Load(),
+	}
+
+	if total := snapshot.CacheHits + snapshot.CacheMisses; total > 0 {
+		snapshot.CacheHitRate = float64(snapshot.CacheHits) / float64(total)
+	}
+
+	return snapshot
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "go-forum-project/proto/gRPC"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+// LookupUserID возвращает id пользователя по его текущему имени.
+func (c *AuthClient) LookupUserID(ctx context.Context, username string) (int, error) {
+	userID, _, err := c.LookupUser(ctx, username)
+	return userID, err
+}
+
+// LookupUser возвращает id и текущее имя пользователя. Прежние имена
+// после переименования тоже находятся.
+func (c *AuthClient) LookupUser(ctx context.Context, username string) (int, string, error) {
+	resp, err := c.client.GetProfile(ctx, &pb.GetProfileRequest{Username: username})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, "", ErrUserNotFound
+		}
+		return 0, "", fmt.Errorf("get profile error: %w", err)
+	}
+
+	return int(resp.UserId), resp.Username, nil
+}
+
+// JoinedAt - дата регистрации для фильтра контента.
+func (c *AuthClient) JoinedAt(ctx context.Context, username string) (time.Time, error) {
+	resp, err := c.client.GetProfile(ctx, &pb.GetProfileRequest{Username: username})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return time.Time{}, ErrUserNotFound
+		}
+		return time.Time{}, fmt.Errorf("get profile error: %w", err)
+	}
+
+	return time.Unix(resp.JoinedAt, 0), nil
+}
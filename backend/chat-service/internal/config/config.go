@@ -2,18 +2,61 @@ package config
 
 import (
 	"fmt"
+	"go-forum-project/contentfilter"
+	"go-forum-project/events"
+	"go-forum-project/ratelimit"
 	"gopkg.in/yaml.v3"
 	"os"
+	"time"
 )
 
 type Config struct {
-	AuthService AuthServiceConfig `yaml:"auth_service"`
-	Server      ServerConfig      `yaml:"server"`
-	Database    DatabaseConfig    `yaml:"database"`
+	AuthService   AuthServiceConfig   `yaml:"auth_service"`
+	Server        ServerConfig        `yaml:"server"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Internal      InternalConfig      `yaml:"internal"`
+	Accounts      AccountsConfig      `yaml:"accounts"`
+	Events        events.Config       `yaml:"events"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	// ContentFilter - проверка сообщений перед публикацией
+	ContentFilter contentfilter.Config `yaml:"content_filter"`
+	// RateLimit - ограничение частоты сообщений
+	RateLimit ratelimit.Config `yaml:"rate_limit"`
+}
+
+// NotificationsConfig - доставка уведомлений форума через WebSocket.
+type NotificationsConfig struct {
+	// BufferSize - сколько уведомлений хранить для пользователя без соединений
+	BufferSize int `yaml:"buffer_size"`
+	// BufferTTL - через сколько неотправленное уведомление выбрасывается
+	BufferTTL time.Duration `yaml:"buffer_ttl"`
+}
+
+// InternalConfig - служебный API для других сервисов (/internal).
+// Пустой Token отключает его.
+type InternalConfig struct {
+	Token string `yaml:"token"`
+}
+
+const (
+	DeletedContentAnonymize = "anonymize"
+	DeletedContentRemove    = "remove"
+)
+
+type AccountsConfig struct {
+	// DeletedContent - что делать с сообщениями удалённого пользователя:
+	// anonymize или remove
+	DeletedContent string `yaml:"deleted_content"`
+	// DeletedAuthor - автор, на которого переписываются сообщения при anonymize
+	DeletedAuthor string `yaml:"deleted_author"`
 }
 
 type AuthServiceConfig struct {
-	Address string `yaml:"address"`
+	Address             string        `yaml:"address"`
+	LocalVerification   bool          `yaml:"local_verification"`
+	KeysRefreshInterval time.Duration `yaml:"keys_refresh_interval"`
+	CacheSize           int           `yaml:"cache_size"`
+	CacheTTL            time.Duration `yaml:"cache_ttl"`
 }
 
 type ServerConfig struct {
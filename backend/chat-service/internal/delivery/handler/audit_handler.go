@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"go-forum-project/chat-service/internal/usecase"
+)
+
+// AuditLogHandler отдаёт записи журнала с id больше after. Журнал забирает
+// auth-service.
+func AuditLogHandler(uc usecase.AuditUseCase, token string) http.Handler {
+	return requireInternalToken(token, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var (
+			afterID int64
+			limit   int
+			err     error
+		)
+		if raw := query.Get("after"); raw != "" {
+			if afterID, err = strconv.ParseInt(raw, 10, 64); err != nil || afterID < 0 {
+				http.Error(w, "invalid after", http.StatusBadRequest)
+				return
+			}
+		}
+		if raw := query.Get("limit"); raw != "" {
+			if limit, err = strconv.Atoi(raw); err != nil {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+		}
+
+		entries, err := uc.ListAfter(r.Context(), afterID, limit)
+		if err != nil {
+			log.Printf("Failed to list audit entries: %v", err)
+			http.Error(w, "failed to list audit entries", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"entries": entries})
+	})
+}
@@ -0,0 +1,27 @@
+package handler
+
+import "github.com/gorilla/websocket"
+
+// closeUnauthorized - код закрытия WebSocket, после которого клиент должен
+// заново войти
+const closeUnauthorized = 4001
+
+var unauthorizedFrame = websocket.FormatCloseMessage(closeUnauthorized, "Unauthorized")
+
+// DisconnectUser закрывает все соединения пользователя, например после
+// блокировки. Переподключиться он не сможет: токен уже отклоняется.
+func (h *Hub) DisconnectUser(userID int) {
+	h.disconnect <- userID
+}
+
+func (h *Hub) disconnectUser(userID int) {
+	for client := range h.clients {
+		if client.userID != userID {
+			continue
+		}
+		// Прочитает writePump после закрытия send
+		client.closeFrame = unauthorizedFrame
+		delete(h.clients, client)
+		close(client.send)
+	}
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go-forum-project/chat-service/internal/client"
+)
+
+// AuthClientMetricsHandler отдаёт метрики клиента auth-service: кэш токенов
+// и локальную проверку подписей. Закрыт служебным токеном.
+func AuthClientMetricsHandler(authClient *client.AuthClient, token string) http.Handler {
+	return requireInternalToken(token, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(authClient.Metrics())
+	})
+}
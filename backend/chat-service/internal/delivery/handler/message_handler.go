@@ -7,9 +7,16 @@ import (
 	"fmt"
 	"github.com/gorilla/websocket"
 	"go-forum-project/chat-service/internal/client"
+	"go-forum-project/chat-service/internal/config"
+	"go-forum-project/chat-service/internal/entity"
 	"go-forum-project/chat-service/internal/usecase"
+	"go-forum-project/events"
+	"go-forum-project/ratelimit"
 	"log"
+	"math"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -24,25 +31,60 @@ type Client struct {
 	hub      *Hub
 	conn     *websocket.Conn
 	send     chan []byte
+	userID   int
 	username string
+	role     string
+	ip       string
+	// closeFrame - кадр закрытия вместо пустого, задаёт Hub.Run
+	closeFrame []byte
+
+	// hidden - авторы, которых пользователь заблокировал или заглушил.
+	// Меняется в Hub.Run, читается и при ответе на get_all
+	mu     sync.RWMutex
+	hidden map[int]bool
 }
 
+// Действия chat-service, для которых в конфиге задаются лимиты
+const (
+	ActionMessageCreate = "message_create"
+	ActionMessageDelete = "message_delete"
+)
+
 type Hub struct {
 	clients    map[*Client]bool
-	broadcast  chan []byte
+	broadcast  chan []*entity.Message
 	register   chan *Client
 	unregister chan *Client
+	notify     chan userFrame
+	relations  chan events.RelationChanged
+	disconnect chan int
 	useCase    usecase.MessageUseCase
+	relationUC usecase.RelationUseCase
+	limiter    *ratelimit.Limiter
 	upgrader   *websocket.Upgrader
+
+	// Уведомления для пользователей без соединений, только внутри Run
+	pending    map[int][]pendingFrame
+	bufferSize int
+	bufferTTL  time.Duration
 }
 
-func NewHub(uc usecase.MessageUseCase) *Hub {
+func NewHub(uc usecase.MessageUseCase, cfg config.NotificationsConfig, limiter *ratelimit.Limiter,
+	relationUC usecase.RelationUseCase) *Hub {
 	return &Hub{
-		broadcast:  make(chan []byte),
+		broadcast:  make(chan []*entity.Message),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		notify:     make(chan userFrame),
+		relations:  make(chan events.RelationChanged),
+		disconnect: make(chan int),
 		clients:    make(map[*Client]bool),
+		pending:    make(map[int][]pendingFrame),
+		bufferSize: cfg.BufferSize,
+		bufferTTL:  cfg.BufferTTL,
 		useCase:    uc,
+		relationUC: relationUC,
+		limiter:    limiter,
 		upgrader: &websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -56,24 +98,29 @@ func NewHub(uc usecase.MessageUseCase) *Hub {
 func (h *Hub) Run() {
 	go h.cleanupOldMessages()
 
+	pendingTicker := time.NewTicker(10 * time.Minute)
+	defer pendingTicker.Stop()
+
 	for {
 		select {
 		case client := <-h.register:
 			h.clients[client] = true
+			h.replayPending(client)
 		case client := <-h.unregister:
 			if _, ok := h.clients[client]; ok {
 				delete(h.clients, client)
 				close(client.send)
 			}
-		case message := <-h.broadcast:
-			for client := range h.clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
-					delete(h.clients, client)
-				}
-			}
+		case messages := <-h.broadcast:
+			h.fanOut(messages)
+		case event := <-h.relations:
+			h.applyRelation(event)
+		case userID := <-h.disconnect:
+			h.disconnectUser(userID)
+		case frame := <-h.notify:
+			h.deliver(frame)
+		case <-pendingTicker.C:
+			h.cleanupPending()
 		}
 	}
 }
@@ -85,8 +132,7 @@ type MessageRequest struct {
 
 // CreateMessagePayload структура для создания сообщения
 type CreateMessagePayload struct {
-	Author string `json:"author"`
-	Text   string `json:"text"`
+	Text string `json:"text"`
 }
 
 // DeleteMessagePayload структура для удаления сообщения
@@ -138,8 +184,14 @@ func (c *Client) handleMessage(rawMsg []byte) error {
 
 	switch baseMsg.Action {
 	case "create":
+		if !c.allow(ActionMessageCreate) {
+			return nil
+		}
 		return c.handleCreateMessage(baseMsg.Payload)
 	case "delete":
+		if !c.allow(ActionMessageDelete) {
+			return nil
+		}
 		return c.handleDeleteMessage(baseMsg.Payload)
 	case "get_all":
 		c.sendMessages()
@@ -150,9 +202,9 @@ func (c *Client) handleMessage(rawMsg []byte) error {
 }
 
 func (c *Client) handleCreateMessage(payload json.RawMessage) error {
+	// Автор берётся из токена соединения, поле author в payload игнорируется
 	var createMsg struct {
-		Author string `json:"author"`
-		Text   string `json:"text"`
+		Text string `json:"text"`
 	}
 
 	if err := json.Unmarshal(payload, &createMsg); err != nil {
@@ -163,7 +215,16 @@ func (c *Client) handleCreateMessage(payload json.RawMessage) error {
 		return errors.New("empty message text")
 	}
 
-	if err := c.hub.useCase.CreateMessage(context.Background(), createMsg.Author, createMsg.Text); err != nil {
+	err := c.hub.useCase.CreateMessage(context.Background(), c.username, c.userID, createMsg.Text)
+	switch {
+	case errors.Is(err, usecase.ErrContentHeld):
+		// Остальные клиенты увидят сообщение после проверки модератором
+		c.sendFrame("held", map[string]string{"message": "message will appear after moderator review"})
+		return nil
+	case errors.Is(err, usecase.ErrContentRejected):
+		c.sendFrame("error", map[string]string{"error": err.Error()})
+		return nil
+	case err != nil:
 		return fmt.Errorf("error creating message: %v", err)
 	}
 
@@ -171,6 +232,41 @@ func (c *Client) handleCreateMessage(payload json.RawMessage) error {
 	return nil
 }
 
+// allow проверяет лимит действия и при превышении отправляет клиенту
+// кадр error с retry_after в секундах.
+func (c *Client) allow(action string) bool {
+	allowed, retryAfter := c.hub.limiter.Allow(context.Background(), action, ratelimit.Subject{
+		UserID: c.userID,
+		Role:   c.role,
+		IP:     c.ip,
+	})
+	if !allowed {
+		c.sendFrame("error", map[string]any{
+			"error":       "too many messages, try again later",
+			"retry_after": int(math.Ceil(retryAfter.Seconds())),
+		})
+	}
+	return allowed
+}
+
+// sendFrame отправляет кадр только этому клиенту.
+func (c *Client) sendFrame(action string, payload any) {
+	msgBytes, err := json.Marshal(map[string]any{
+		"action":  action,
+		"payload": payload,
+	})
+	if err != nil {
+		log.Printf("error marshaling %s frame: %v", action, err)
+		return
+	}
+
+	select {
+	case c.send <- msgBytes:
+	default:
+		log.Printf("send buffer of %s is full, dropping %s frame", c.username, action)
+	}
+}
+
 func (c *Client) handleDeleteMessage(payload json.RawMessage) error {
 	var deleteMsg struct {
 		ID int `json:"id"`
@@ -180,7 +276,7 @@ func (c *Client) handleDeleteMessage(payload json.RawMessage) error {
 		return fmt.Errorf("invalid delete message payload: %v", err)
 	}
 
-	if err := c.hub.useCase.DeleteMessage(context.Background(), deleteMsg.ID); err != nil {
+	if err := c.hub.useCase.DeleteMessage(context.Background(), deleteMsg.ID, c.userID); err != nil {
 		return fmt.Errorf("error deleting message: %v", err)
 	}
 
@@ -195,18 +291,7 @@ func (h *Hub) broadcastMessages() {
 		return
 	}
 
-	msgToSend := map[string]interface{}{
-		"action":  "broadcast",
-		"payload": messages,
-	}
-
-	msgBytes, err := json.Marshal(msgToSend)
-	if err != nil {
-		log.Printf("error marshaling messages: %v", err)
-		return
-	}
-
-	h.broadcast <- msgBytes
+	h.broadcast <- messages
 }
 
 func (c *Client) sendMessages() {
@@ -216,7 +301,7 @@ func (c *Client) sendMessages() {
 		return
 	}
 
-	msgBytes, err := json.Marshal(messages)
+	msgBytes, err := json.Marshal(c.visible(messages))
 	if err != nil {
 		log.Printf("error marshaling messages: %v", err)
 		return
@@ -237,7 +322,11 @@ func (c *Client) writePump() {
 		case message, ok := <-c.send:
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				closeFrame := c.closeFrame
+				if closeFrame == nil {
+					closeFrame = []byte{}
+				}
+				c.conn.WriteMessage(websocket.CloseMessage, closeFrame)
 				return
 			}
 
@@ -262,16 +351,30 @@ func (c *Client) writePump() {
 func ServeWs(hub *Hub, authClient *client.AuthClient) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		accessToken := r.URL.Query().Get("accessToken")
+		// Боты с персональным токеном могут передать его в заголовке
+		if accessToken == "" {
+			accessToken = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
 
-		log.Printf("Access token: %s", accessToken)
-
-		username, valid, err := authClient.ValidateToken(r.Context(), accessToken)
+		info, valid, err := authClient.ValidateToken(r.Context(), accessToken)
 		if err != nil || !valid {
 			log.Printf("Token validation error: %v", err)
 			respondWithUnauthorized(w, r, hub.upgrader)
 			return
 		}
 
+		if !info.HasScope("chat") {
+			log.Printf("Token of %s has no chat scope", info.Username)
+			respondWithUnauthorized(w, r, hub.upgrader)
+			return
+		}
+
+		hidden, err := hub.relationUC.HiddenAuthors(r.Context(), info.UserID)
+		if err != nil {
+			log.Printf("Failed to load hidden authors of %s: %v", info.Username, err)
+			hidden = make(map[int]bool)
+		}
+
 		conn, err := hub.upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			log.Println("WebSocket upgrade error:", err)
@@ -282,7 +385,11 @@ func ServeWs(hub *Hub, authClient *client.AuthClient) http.HandlerFunc {
 			hub:      hub,
 			conn:     conn,
 			send:     make(chan []byte, 256),
-			username: username,
+			userID:   info.UserID,
+			username: info.Username,
+			role:     info.Role,
+			ip:       hub.limiter.ClientIP(r),
+			hidden:   hidden,
 		}
 
 		hub.register <- client
@@ -297,8 +404,7 @@ func respondWithUnauthorized(w http.ResponseWriter, r *http.Request, upgrader *w
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	conn.WriteMessage(websocket.CloseMessage,
-		websocket.FormatCloseMessage(4001, "Unauthorized"))
+	conn.WriteMessage(websocket.CloseMessage, unauthorizedFrame)
 	conn.Close()
 }
 
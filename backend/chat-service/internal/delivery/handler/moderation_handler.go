@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"go-forum-project/chat-service/internal/usecase"
+)
+
+// GetMessageInternalHandler, RemoveMessageInternalHandler и ReleaseMessageInternalHandler - служебный API
+// для модерации в forum-service: /internal/messages/{messageId}.
+func GetMessageInternalHandler(uc usecase.MessageUseCase, token string) http.Handler {
+	return requireInternalToken(token, func(w http.ResponseWriter, r *http.Request) {
+		messageID, err := strconv.Atoi(r.PathValue("messageId"))
+		if err != nil {
+			http.Error(w, "invalid message id", http.StatusBadRequest)
+			return
+		}
+
+		message, err := uc.GetMessage(r.Context(), messageID)
+		if err != nil {
+			if errors.Is(err, usecase.ErrMessageNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			log.Printf("Failed to get message %d: %v", messageID, err)
+			http.Error(w, "failed to get message", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(message)
+	})
+}
+
+// RemoveMessageInternalHandler удаляет сообщение и рассылает клиентам
+// обновлённый список.
+func RemoveMessageInternalHandler(hub *Hub, token string) http.Handler {
+	return requireInternalToken(token, func(w http.ResponseWriter, r *http.Request) {
+		messageID, err := strconv.Atoi(r.PathValue("messageId"))
+		if err != nil {
+			http.Error(w, "invalid message id", http.StatusBadRequest)
+			return
+		}
+
+		// Модератор, от имени которого удаляется сообщение, нужен для журнала аудита
+		query := r.URL.Query()
+		actorID, err := strconv.Atoi(query.Get("actor_id"))
+		if err != nil || actorID <= 0 {
+			http.Error(w, "invalid actor id", http.StatusBadRequest)
+			return
+		}
+
+		err = hub.useCase.RemoveMessage(r.Context(), messageID, actorID, query.Get("actor_role"), query.Get("reason"))
+		if err != nil {
+			if errors.Is(err, usecase.ErrMessageNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			log.Printf("Failed to remove message %d: %v", messageID, err)
+			http.Error(w, "failed to remove message", http.StatusInternalServerError)
+			return
+		}
+
+		hub.broadcastMessages()
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// ReleaseMessageInternalHandler публикует сообщение, скрытое фильтром
+// контента, и рассылает клиентам обновлённый список.
+func ReleaseMessageInternalHandler(hub *Hub, token string) http.Handler {
+	return requireInternalToken(token, func(w http.ResponseWriter, r *http.Request) {
+		messageID, err := strconv.Atoi(r.PathValue("messageId"))
+		if err != nil {
+			http.Error(w, "invalid message id", http.StatusBadRequest)
+			return
+		}
+
+		if err := hub.useCase.ReleaseMessage(r.Context(), messageID); err != nil {
+			if errors.Is(err, usecase.ErrMessageNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			log.Printf("Failed to release message %d: %v", messageID, err)
+			http.Error(w, "failed to release message", http.StatusInternalServerError)
+			return
+		}
+
+		hub.broadcastMessages()
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
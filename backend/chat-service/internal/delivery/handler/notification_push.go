@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+const defaultNotificationBuffer = 50
+
+// userFrame - кадр для всех соединений одного пользователя.
+type userFrame struct {
+	userID int
+	data   []byte
+}
+
+type pendingFrame struct {
+	data      []byte
+	createdAt time.Time
+}
+
+// PushNotification отправляет кадр {"action": "notification"} во все
+// соединения пользователя. Если соединений нет, кадр ждёт переподключения.
+func (h *Hub) PushNotification(userID int, notification any) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"action":  "notification",
+		"payload": notification,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.notify <- userFrame{userID: userID, data: data}
+	return nil
+}
+
+func (h *Hub) deliver(frame userFrame) {
+	delivered := false
+	for client := range h.clients {
+		if client.userID != frame.userID {
+			continue
+		}
+		select {
+		case client.send <- frame.data:
+			delivered = true
+		default:
+			close(client.send)
+			delete(h.clients, client)
+		}
+	}
+
+	if !delivered {
+		h.bufferFrame(frame)
+	}
+}
+
+func (h *Hub) bufferFrame(frame userFrame) {
+	size := h.bufferSize
+	if size <= 0 {
+		size = defaultNotificationBuffer
+	}
+
+	pending := append(h.pending[frame.userID], pendingFrame{data: frame.data, createdAt: time.Now()})
+	if len(pending) > size {
+		log.Printf("Notification buffer of user %d is full, dropping oldest", frame.userID)
+		pending = pending[len(pending)-size:]
+	}
+	h.pending[frame.userID] = pending
+}
+
+// replayPending отправляет накопленные уведомления новому соединению.
+func (h *Hub) replayPending(client *Client) {
+	pending, ok := h.pending[client.userID]
+	if !ok {
+		return
+	}
+	delete(h.pending, client.userID)
+
+	for _, frame := range pending {
+		if h.bufferTTL > 0 && time.Since(frame.createdAt) > h.bufferTTL {
+			continue
+		}
+		select {
+		case client.send <- frame.data:
+		default:
+			// Буфер соединения переполнен, остаток вернётся при следующем подключении
+			h.bufferFrame(userFrame{userID: client.userID, data: frame.data})
+		}
+	}
+}
+
+// cleanupPending выбрасывает устаревшие уведомления пользователей,
+// которые так и не подключились.
+func (h *Hub) cleanupPending() {
+	if h.bufferTTL <= 0 {
+		return
+	}
+	for userID, pending := range h.pending {
+		fresh := pending[:0]
+		for _, frame := range pending {
+			if time.Since(frame.createdAt) <= h.bufferTTL {
+				fresh = append(fresh, frame)
+			}
+		}
+		if len(fresh) == 0 {
+			delete(h.pending, userID)
+		} else {
+			h.pending[userID] = fresh
+		}
+	}
+}
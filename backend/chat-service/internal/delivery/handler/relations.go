@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"go-forum-project/chat-service/internal/entity"
+	"go-forum-project/events"
+)
+
+// ApplyRelation обновляет скрытых авторов у открытых соединений
+// пользователя, который заблокировал, заглушил или снял ограничение.
+func (h *Hub) ApplyRelation(event events.RelationChanged) {
+	h.relations <- event
+}
+
+func (h *Hub) applyRelation(event events.RelationChanged) {
+	for client := range h.clients {
+		if client.userID != event.UserID {
+			continue
+		}
+		client.mu.Lock()
+		if event.Kind == "" {
+			delete(client.hidden, event.TargetID)
+		} else {
+			client.hidden[event.TargetID] = true
+		}
+		client.mu.Unlock()
+	}
+}
+
+// visible убирает сообщения авторов, которых клиент заблокировал или заглушил.
+func (c *Client) visible(messages []*entity.Message) []*entity.Message {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.hidden) == 0 {
+		return messages
+	}
+	result := make([]*entity.Message, 0, len(messages))
+	for _, m := range messages {
+		if !c.hidden[m.AuthorID] {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// fanOut рассылает список сообщений, каждому клиенту - без скрытых им авторов.
+// Общий кадр собирается один раз для клиентов без ограничений.
+func (h *Hub) fanOut(messages []*entity.Message) {
+	shared, err := broadcastFrame(messages)
+	if err != nil {
+		return
+	}
+
+	for client := range h.clients {
+		frame := shared
+		if own := client.visible(messages); len(own) != len(messages) {
+			if frame, err = broadcastFrame(own); err != nil {
+				continue
+			}
+		}
+
+		select {
+		case client.send <- frame:
+		default:
+			close(client.send)
+			delete(h.clients, client)
+		}
+	}
+}
+
+func broadcastFrame(messages []*entity.Message) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"action":  "broadcast",
+		"payload": messages,
+	})
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"go-forum-project/chat-service/internal/usecase"
+)
+
+// ExportUserDataHandler и EraseUserDataHandler - служебный API для
+// auth-service, пользователь берётся из пути /internal/users/{userId}/data.
+func ExportUserDataHandler(uc usecase.UserDataUseCase, token string) http.Handler {
+	return requireInternalToken(token, func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.Atoi(r.PathValue("userId"))
+		if err != nil {
+			http.Error(w, "invalid user id", http.StatusBadRequest)
+			return
+		}
+
+		data, err := uc.Export(r.Context(), userID)
+		if err != nil {
+			log.Printf("Failed to export user data: %v", err)
+			http.Error(w, "failed to export user data", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(data)
+	})
+}
+
+func EraseUserDataHandler(uc usecase.UserDataUseCase, token string) http.Handler {
+	return requireInternalToken(token, func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.Atoi(r.PathValue("userId"))
+		if err != nil {
+			http.Error(w, "invalid user id", http.StatusBadRequest)
+			return
+		}
+
+		if err := uc.Erase(r.Context(), userID); err != nil {
+			log.Printf("Failed to erase user data: %v", err)
+			http.Error(w, "failed to erase user data", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// requireInternalToken сверяет заголовок X-Internal-Token с общим секретом.
+// Пустой token закрывает доступ полностью.
+func requireInternalToken(token string, next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided := r.Header.Get("X-Internal-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			http.Error(w, "invalid internal token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	})
+}
@@ -0,0 +1,50 @@
+package subscriber
+
+import (
+	"context"
+
+	"go-forum-project/chat-service/internal/client"
+	"go-forum-project/chat-service/internal/delivery/handler"
+	"go-forum-project/chat-service/internal/usecase"
+	"go-forum-project/events"
+)
+
+// Register подписывает chat-service на события других сервисов.
+func Register(bus events.Bus, userDataUC usecase.UserDataUseCase, relationUC usecase.RelationUseCase,
+	hub *handler.Hub, authClient *client.AuthClient) {
+	events.Subscribe(bus, func(ctx context.Context, event events.UserRenamed) error {
+		return userDataUC.RenameAuthor(ctx, event.UserID, event.NewUsername)
+	})
+
+	// Уведомления форума доставляются в открытые WebSocket соединения
+	events.Subscribe(bus, func(ctx context.Context, event events.NotificationCreated) error {
+		return hub.PushNotification(event.UserID, event)
+	})
+
+	// Блокировки и заглушения ведёт forum-service, здесь - копия для чата
+	events.Subscribe(bus, func(ctx context.Context, event events.RelationChanged) error {
+		if err := relationUC.Apply(ctx, event); err != nil {
+			return err
+		}
+		hub.ApplyRelation(event)
+		return nil
+	})
+
+	events.Subscribe(bus, func(ctx context.Context, event events.UserBanned) error {
+		authClient.BanUser(event.UserID, event.ExpiresAt)
+		// Открытые соединения иначе продолжали бы получать сообщения
+		hub.DisconnectUser(event.UserID)
+		return nil
+	})
+
+	events.Subscribe(bus, func(ctx context.Context, event events.UserUnbanned) error {
+		authClient.UnbanUser(event.UserID)
+		return nil
+	})
+
+	events.Subscribe(bus, func(ctx context.Context, event events.UserDeleted) error {
+		authClient.DeleteUser(event.UserID)
+		hub.DisconnectUser(event.UserID)
+		return nil
+	})
+}
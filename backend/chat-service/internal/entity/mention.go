@@ -0,0 +1,7 @@
+package entity
+
+import "go-forum-project/mention"
+
+// Mention - упоминание пользователя в тексте. Offset и Length в единицах
+// UTF-16, как индексы строк в JavaScript, включая @.
+type Mention = mention.Mention
@@ -5,6 +5,10 @@ import "time"
 type Message struct {
 	ID        int
 	Author    string
+	AuthorID  int // 0, если автор удалил аккаунт
 	Text      string
 	CreatedAt time.Time
+	Mentions  []Mention
+	// Held - сообщение скрыто фильтром контента до проверки модератором
+	Held bool
 }
@@ -0,0 +1,8 @@
+package entity
+
+// Виды отношений из forum-service: mute скрывает сообщения пользователя,
+// block вдобавок не даёт ему упоминать заблокировавшего.
+const (
+	RelationBlock = "block"
+	RelationMute  = "mute"
+)
@@ -0,0 +1,7 @@
+package entity
+
+// UserData - всё, что chat-service хранит о пользователе, для выгрузки.
+type UserData struct {
+	UserID   int
+	Messages []*Message
+}
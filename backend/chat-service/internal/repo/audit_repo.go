@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"go-forum-project/events"
+)
+
+type AuditRepository interface {
+	// ListAfter возвращает записи журнала chat-service после afterID. Пишут
+	// журнал репозитории в транзакциях самих действий, см. events.RecordAudit.
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]events.AuditRecorded, error)
+}
+
+type AuditRepo struct {
+	Db *sql.DB
+}
+
+func NewAuditRepo(db *sql.DB) AuditRepository {
+	return &AuditRepo{Db: db}
+}
+
+func (r *AuditRepo) ListAfter(ctx context.Context, afterID int64, limit int) ([]events.AuditRecorded, error) {
+	return events.AuditAfter(ctx, r.Db, afterID, limit)
+}
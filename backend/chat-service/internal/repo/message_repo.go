@@ -3,13 +3,32 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"github.com/lib/pq"
 	"go-forum-project/chat-service/internal/entity"
+	"go-forum-project/events"
 )
 
 type MessageRepository interface {
-	CreateMessage(ctx context.Context, author, text string) error
-	DeleteMessage(ctx context.Context, id int) error
+	// CreateMessage с непустым holdReasons сохраняет сообщение скрытым до проверки модератором
+	CreateMessage(ctx context.Context, author string, authorID int, text string, mentions []entity.Mention,
+		holdReasons []string) error
+	// ReleaseMessage публикует скрытое сообщение, false - его нет или оно не скрыто
+	ReleaseMessage(ctx context.Context, id int) (bool, error)
+	// DeleteMessage удаляет сообщение. audit передаёт модератор, запись
+	// журнала сохраняется вместе с удалением.
+	DeleteMessage(ctx context.Context, id int, audit ...events.AuditRecorded) error
+	GetMessageByID(ctx context.Context, id int) (*entity.Message, error)
 	GetAllMessages(ctx context.Context) ([]*entity.Message, error)
+	GetMessagesByAuthor(ctx context.Context, authorID int) ([]*entity.Message, error)
+	// GetMentions возвращает упоминания сообщений пачкой, ключ - id сообщения
+	GetMentions(ctx context.Context, messageIDs []int) (map[int][]entity.Mention, error)
+	AnonymizeAuthor(ctx context.Context, authorID int, placeholder string) error
+	DeleteByAuthor(ctx context.Context, authorID int) error
+	RenameAuthor(ctx context.Context, authorID int, username string) error
+	// RenameMentions и DeleteMentions меняют упоминания пользователя в чужих сообщениях
+	RenameMentions(ctx context.Context, userID int, username string) error
+	DeleteMentions(ctx context.Context, userID int) error
 }
 
 type MessageRepo struct {
@@ -20,25 +39,151 @@ func NewMessageRepo(db *sql.DB) MessageRepository {
 	return &MessageRepo{db: db}
 }
 
-func (r *MessageRepo) CreateMessage(ctx context.Context, author, text string) error {
-	query := `INSERT INTO messages (author, text) VALUES ($1, $2)`
-	_, err := r.db.ExecContext(
+func (r *MessageRepo) CreateMessage(ctx context.Context, author string, authorID int, text string,
+	mentions []entity.Mention, holdReasons []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO messages (author, author_id, text, held) VALUES ($1, $2, $3, $4) RETURNING id`
+	var id int
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		author,
+		authorID,
 		text,
-	)
-	return err
+		len(holdReasons) > 0,
+	).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	var mentioned []int
+	seen := make(map[int]bool, len(mentions))
+	for _, m := range mentions {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO message_mentions (message_id, user_id, username, offset_utf16, length_utf16)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			id, m.UserID, m.Username, m.Offset, m.Length,
+		)
+		if err != nil {
+			return err
+		}
+		if !seen[m.UserID] {
+			seen[m.UserID] = true
+			mentioned = append(mentioned, m.UserID)
+		}
+	}
+
+	// Уведомления о скрытом сообщении уйдут, когда модератор его опубликует
+	var event events.Event = events.MessageCreated{
+		MessageID:        id,
+		AuthorID:         authorID,
+		Author:           author,
+		Text:             text,
+		MentionedUserIDs: mentioned,
+	}
+	if len(holdReasons) > 0 {
+		event = events.ContentHeld{
+			Service:    "chat",
+			TargetType: "message",
+			TargetID:   id,
+			AuthorID:   authorID,
+			Text:       text,
+			Reasons:    holdReasons,
+		}
+	}
+	if err := events.Enqueue(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *MessageRepo) DeleteMessage(ctx context.Context, id int) error {
-	query := `DELETE FROM messages WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+func (r *MessageRepo) ReleaseMessage(ctx context.Context, id int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	event := events.MessageCreated{MessageID: id}
+	err = tx.QueryRowContext(ctx,
+		`UPDATE messages SET held = FALSE WHERE id = $1 AND held RETURNING author, COALESCE(author_id, 0), text`,
+		id,
+	).Scan(&event.Author, &event.AuthorID, &event.Text)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT DISTINCT user_id FROM message_mentions WHERE message_id = $1 ORDER BY user_id`, id)
+	if err != nil {
+		return false, err
+	}
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return false, err
+		}
+		event.MentionedUserIDs = append(event.MentionedUserIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	if err := events.Enqueue(ctx, tx, event); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *MessageRepo) DeleteMessage(ctx context.Context, id int, audit ...events.AuditRecorded) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM messages WHERE id = $1`, id); err != nil {
+		return err
+	}
+	if err := events.RecordAudit(ctx, tx, audit...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *MessageRepo) GetMessageByID(ctx context.Context, id int) (*entity.Message, error) {
+	query := `SELECT id, author, COALESCE(author_id, 0), text, created_at, held FROM messages WHERE id = $1`
+
+	message := &entity.Message{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&message.ID,
+		&message.Author,
+		&message.AuthorID,
+		&message.Text,
+		&message.CreatedAt,
+		&message.Held,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
 }
 
 func (r *MessageRepo) GetAllMessages(ctx context.Context) ([]*entity.Message, error) {
-	query := `SELECT id, author, text, created_at FROM messages ORDER BY created_at DESC`
+	query := `SELECT id, author, COALESCE(author_id, 0), text, created_at FROM messages
+		WHERE NOT held ORDER BY created_at DESC`
 
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
@@ -52,6 +197,7 @@ func (r *MessageRepo) GetAllMessages(ctx context.Context) ([]*entity.Message, er
 		err := rows.Scan(
 			&message.ID,
 			&message.Author,
+			&message.AuthorID,
 			&message.Text,
 			&message.CreatedAt,
 		)
@@ -63,3 +209,81 @@ func (r *MessageRepo) GetAllMessages(ctx context.Context) ([]*entity.Message, er
 
 	return messages, nil
 }
+
+func (r *MessageRepo) GetMessagesByAuthor(ctx context.Context, authorID int) ([]*entity.Message, error) {
+	query := `SELECT id, author, COALESCE(author_id, 0), text, created_at FROM messages
+		WHERE author_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*entity.Message
+	for rows.Next() {
+		message := &entity.Message{}
+		err := rows.Scan(&message.ID, &message.Author, &message.AuthorID, &message.Text, &message.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *MessageRepo) GetMentions(ctx context.Context, messageIDs []int) (map[int][]entity.Mention, error) {
+	result := make(map[int][]entity.Mention)
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT message_id, user_id, username, offset_utf16, length_utf16 FROM message_mentions
+		WHERE message_id = ANY($1) ORDER BY offset_utf16`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(messageIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			messageID int
+			m         entity.Mention
+		)
+		if err := rows.Scan(&messageID, &m.UserID, &m.Username, &m.Offset, &m.Length); err != nil {
+			return nil, err
+		}
+		result[messageID] = append(result[messageID], m)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *MessageRepo) AnonymizeAuthor(ctx context.Context, authorID int, placeholder string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE messages SET author = $1, author_id = NULL WHERE author_id = $2`,
+		placeholder, authorID)
+	return err
+}
+
+func (r *MessageRepo) DeleteByAuthor(ctx context.Context, authorID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM messages WHERE author_id = $1`, authorID)
+	return err
+}
+
+func (r *MessageRepo) RenameAuthor(ctx context.Context, authorID int, username string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE messages SET author = $1 WHERE author_id = $2`, username, authorID)
+	return err
+}
+
+func (r *MessageRepo) RenameMentions(ctx context.Context, userID int, username string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE message_mentions SET username = $1 WHERE user_id = $2`, username, userID)
+	return err
+}
+
+func (r *MessageRepo) DeleteMentions(ctx context.Context, userID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM message_mentions WHERE user_id = $1`, userID)
+	return err
+}
@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"go-forum-project/chat-service/internal/entity"
+)
+
+// RelationRepository - копия блокировок и заглушений forum-service.
+type RelationRepository interface {
+	Set(ctx context.Context, userID, targetID int, kind string) error
+	Remove(ctx context.Context, userID, targetID int) error
+	// HiddenAuthorIDs - пользователи, чьи сообщения userID не хочет видеть
+	HiddenAuthorIDs(ctx context.Context, userID int) (map[int]bool, error)
+	// BlockedBy возвращает, кто из userIDs заблокировал authorID
+	BlockedBy(ctx context.Context, authorID int, userIDs []int) (map[int]bool, error)
+	DeleteByUser(ctx context.Context, userID int) error
+}
+
+type RelationRepo struct {
+	db *sql.DB
+}
+
+func NewRelationRepo(db *sql.DB) RelationRepository {
+	return &RelationRepo{db: db}
+}
+
+func (r *RelationRepo) Set(ctx context.Context, userID, targetID int, kind string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO user_relations (user_id, target_id, kind) VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id, target_id) DO UPDATE SET kind = EXCLUDED.kind`,
+		userID, targetID, kind)
+	return err
+}
+
+func (r *RelationRepo) Remove(ctx context.Context, userID, targetID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM user_relations WHERE user_id = $1 AND target_id = $2`,
+		userID, targetID)
+	return err
+}
+
+func (r *RelationRepo) HiddenAuthorIDs(ctx context.Context, userID int) (map[int]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT target_id FROM user_relations WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hidden := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		hidden[id] = true
+	}
+
+	return hidden, rows.Err()
+}
+
+func (r *RelationRepo) BlockedBy(ctx context.Context, authorID int, userIDs []int) (map[int]bool, error) {
+	blocked := make(map[int]bool)
+	if authorID == 0 || len(userIDs) == 0 {
+		return blocked, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id FROM user_relations WHERE target_id = $1 AND kind = $2 AND user_id = ANY($3)`,
+		authorID, entity.RelationBlock, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		blocked[id] = true
+	}
+
+	return blocked, rows.Err()
+}
+
+func (r *RelationRepo) DeleteByUser(ctx context.Context, userID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM user_relations WHERE user_id = $1 OR target_id = $1`, userID)
+	return err
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-forum-project/contentfilter"
+)
+
+var (
+	ErrContentRejected = errors.New("message rejected by content filter")
+	// ErrContentHeld - сообщение сохранено, но появится в чате после проверки модератором
+	ErrContentHeld = errors.New("message is held for moderation")
+)
+
+// ContentChecker - цепочка фильтров контента, в приложении это contentfilter.Reloader.
+type ContentChecker interface {
+	Run(ctx context.Context, in contentfilter.Input) contentfilter.Verdict
+}
+
+// filterContent прогоняет сообщение через фильтры. Возвращает текст после
+// маскировки и причины, по которым сообщение нужно скрыть до проверки.
+func filterContent(ctx context.Context, filter ContentChecker, in contentfilter.Input) (string, []string, error) {
+	verdict := filter.Run(ctx, in)
+	switch verdict.Action {
+	case contentfilter.ActionReject:
+		return "", nil, fmt.Errorf("%w: %s", ErrContentRejected, strings.Join(verdict.Filters, ", "))
+	case contentfilter.ActionHold:
+		return verdict.Text, verdict.Reasons, nil
+	}
+	return verdict.Text, nil, nil
+}
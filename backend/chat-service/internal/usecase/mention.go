@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go-forum-project/chat-service/internal/client"
+	"go-forum-project/chat-service/internal/entity"
+	"go-forum-project/chat-service/internal/repo"
+	"go-forum-project/mention"
+)
+
+// UserResolver ищет пользователя по имени, в приложении это клиент auth-service.
+type UserResolver interface {
+	LookupUser(ctx context.Context, username string) (int, string, error)
+}
+
+// resolveMentions проверяет упоминания в тексте и убирает тех, кто
+// заблокировал автора.
+func resolveMentions(ctx context.Context, users UserResolver, relations repo.RelationRepository,
+	authorID int, text string) []entity.Mention {
+	lookup := func(ctx context.Context, username string) (int, string, error) {
+		id, name, err := users.LookupUser(ctx, username)
+		if errors.Is(err, client.ErrUserNotFound) {
+			return 0, "", mention.ErrUserNotFound
+		}
+		return id, name, err
+	}
+
+	return mention.DropBlocked(ctx, relations.BlockedBy, authorID, mention.Resolve(ctx, lookup, text))
+}
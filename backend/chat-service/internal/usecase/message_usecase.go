@@ -2,51 +2,152 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"go-forum-project/chat-service/internal/entity"
 	"go-forum-project/chat-service/internal/repo"
+	"go-forum-project/contentfilter"
+	"go-forum-project/events"
 	"time"
 )
 
 var (
-	ErrLengthText = errors.New("text must be between 1 and 150 characters")
+	ErrLengthText      = errors.New("text must be between 1 and 150 characters")
+	ErrMessageNotFound = errors.New("message not found")
+	ErrNotMessageOwner = errors.New("you can only delete your own messages")
 )
 
 type MessageUseCase interface {
-	CreateMessage(ctx context.Context, author, text string) error
+	// CreateMessage возвращает ErrContentHeld, если сообщение сохранено скрытым до проверки
+	CreateMessage(ctx context.Context, author string, authorID int, text string) error
 	GetAllMessages(ctx context.Context) ([]*entity.Message, error)
-	DeleteMessage(ctx context.Context, id int) error
+	DeleteMessage(ctx context.Context, id, currentUserID int) error
+	GetMessage(ctx context.Context, id int) (*entity.Message, error)
+	// RemoveMessage удаляет сообщение по решению модератора, без проверки автора,
+	// и записывает удаление в журнал аудита
+	RemoveMessage(ctx context.Context, id, actorID int, actorRole, reason string) error
+	// ReleaseMessage публикует сообщение, скрытое фильтром контента
+	ReleaseMessage(ctx context.Context, id int) error
 	CleanupOldMessages(ctx context.Context) error
 }
 
 type messageUseCase struct {
-	repo repo.MessageRepository
+	repo      repo.MessageRepository
+	users     UserResolver
+	filter    ContentChecker
+	relations repo.RelationRepository
 }
 
-func NewMessageUseCase(repo repo.MessageRepository) MessageUseCase {
-	return &messageUseCase{repo: repo}
+func NewMessageUseCase(repo repo.MessageRepository, users UserResolver, filter ContentChecker,
+	relations repo.RelationRepository) MessageUseCase {
+	return &messageUseCase{repo: repo, users: users, filter: filter, relations: relations}
 }
 
-func (c *messageUseCase) CreateMessage(ctx context.Context, author, text string) error {
+func (c *messageUseCase) CreateMessage(ctx context.Context, author string, authorID int, text string) error {
 	if len(text) == 0 || len(author) > 150 {
 		return ErrLengthText
 	}
 
-	if err := c.repo.CreateMessage(ctx, author, text); err != nil {
+	text, holdReasons, err := filterContent(ctx, c.filter, contentfilter.Input{
+		AuthorID: authorID,
+		Author:   author,
+		Kind:     "message",
+		Text:     text,
+	})
+	if err != nil {
+		return err
+	}
+
+	mentions := resolveMentions(ctx, c.users, c.relations, authorID, text)
+	if err := c.repo.CreateMessage(ctx, author, authorID, text, mentions, holdReasons); err != nil {
 		return err
 	}
 
+	if len(holdReasons) > 0 {
+		return ErrContentHeld
+	}
 	return nil
 }
 
 func (c *messageUseCase) GetAllMessages(ctx context.Context) ([]*entity.Message, error) {
-	return c.repo.GetAllMessages(ctx)
+	messages, err := c.repo.GetAllMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+
+	mentions, err := c.repo.GetMentions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages {
+		message.Mentions = mentions[message.ID]
+	}
+	return messages, nil
 }
 
-func (c *messageUseCase) DeleteMessage(ctx context.Context, id int) error {
+func (c *messageUseCase) DeleteMessage(ctx context.Context, id, currentUserID int) error {
+	message, err := c.repo.GetMessageByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMessageNotFound
+		}
+		return err
+	}
+
+	// Без author_id остались сообщения, автора которых не нашла миграция
+	if message.AuthorID == 0 || message.AuthorID != currentUserID {
+		return ErrNotMessageOwner
+	}
+
 	return c.repo.DeleteMessage(ctx, id)
 }
 
+func (c *messageUseCase) GetMessage(ctx context.Context, id int) (*entity.Message, error) {
+	message, err := c.repo.GetMessageByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+	return message, nil
+}
+
+func (c *messageUseCase) RemoveMessage(ctx context.Context, id, actorID int, actorRole, reason string) error {
+	message, err := c.GetMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.repo.DeleteMessage(ctx, id, events.AuditRecorded{
+		ActorID:    actorID,
+		ActorRole:  actorRole,
+		Action:     events.AuditMessageRemove,
+		TargetType: "message",
+		TargetID:   id,
+		Reason:     reason,
+		Before: events.Snapshot(map[string]any{
+			"author": message.Author, "author_id": message.AuthorID, "text": message.Text,
+		}),
+	})
+}
+
+func (c *messageUseCase) ReleaseMessage(ctx context.Context, id int) error {
+	released, err := c.repo.ReleaseMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return ErrMessageNotFound
+	}
+	return nil
+}
+
 func (c *messageUseCase) CleanupOldMessages(ctx context.Context) error {
 	messages, err := c.repo.GetAllMessages(ctx)
 	if err != nil {
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"go-forum-project/chat-service/internal/repo"
+	"go-forum-project/events"
+)
+
+// RelationUseCase ведёт копию блокировок и заглушений forum-service.
+type RelationUseCase interface {
+	// HiddenAuthors - пользователи, чьи сообщения userID не показываются
+	HiddenAuthors(ctx context.Context, userID int) (map[int]bool, error)
+	// Apply применяет событие forum-service, повторное событие ничего не меняет
+	Apply(ctx context.Context, event events.RelationChanged) error
+}
+
+type relationUseCase struct {
+	repo repo.RelationRepository
+}
+
+func NewRelationUseCase(repo repo.RelationRepository) RelationUseCase {
+	return &relationUseCase{repo: repo}
+}
+
+func (uc *relationUseCase) HiddenAuthors(ctx context.Context, userID int) (map[int]bool, error) {
+	return uc.repo.HiddenAuthorIDs(ctx, userID)
+}
+
+func (uc *relationUseCase) Apply(ctx context.Context, event events.RelationChanged) error {
+	if event.Kind == "" {
+		return uc.repo.Remove(ctx, event.UserID, event.TargetID)
+	}
+	return uc.repo.Set(ctx, event.UserID, event.TargetID, event.Kind)
+}
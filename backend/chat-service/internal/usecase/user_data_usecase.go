@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"go-forum-project/chat-service/internal/config"
+	"go-forum-project/chat-service/internal/entity"
+	"go-forum-project/chat-service/internal/repo"
+)
+
+// UserDataUseCase выгружает и удаляет данные пользователя по запросу
+// auth-service при экспорте и удалении аккаунта.
+type UserDataUseCase interface {
+	Export(ctx context.Context, userID int) (*entity.UserData, error)
+	Erase(ctx context.Context, userID int) error
+	// RenameAuthor переподписывает сообщения после смены имени в auth-service.
+	RenameAuthor(ctx context.Context, userID int, username string) error
+}
+
+type userDataUseCase struct {
+	repo      repo.MessageRepository
+	relations repo.RelationRepository
+	cfg       config.AccountsConfig
+}
+
+func NewUserDataUseCase(repo repo.MessageRepository, relations repo.RelationRepository,
+	cfg config.AccountsConfig) UserDataUseCase {
+	return &userDataUseCase{repo: repo, relations: relations, cfg: cfg}
+}
+
+func (uc *userDataUseCase) Export(ctx context.Context, userID int) (*entity.UserData, error) {
+	messages, err := uc.repo.GetMessagesByAuthor(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	return &entity.UserData{UserID: userID, Messages: messages}, nil
+}
+
+func (uc *userDataUseCase) Erase(ctx context.Context, userID int) error {
+	if err := uc.relations.DeleteByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete relations: %w", err)
+	}
+	// Текст сообщений не меняется, упоминание просто перестаёт быть ссылкой
+	if err := uc.repo.DeleteMentions(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete mentions: %w", err)
+	}
+
+	if uc.cfg.DeletedContent == config.DeletedContentRemove {
+		return uc.repo.DeleteByAuthor(ctx, userID)
+	}
+
+	placeholder := uc.cfg.DeletedAuthor
+	if placeholder == "" {
+		placeholder = "deleted"
+	}
+	return uc.repo.AnonymizeAuthor(ctx, userID, placeholder)
+}
+
+func (uc *userDataUseCase) RenameAuthor(ctx context.Context, userID int, username string) error {
+	if err := uc.repo.RenameAuthor(ctx, userID, username); err != nil {
+		return err
+	}
+	if err := uc.repo.RenameMentions(ctx, userID, username); err != nil {
+		return fmt.Errorf("failed to rename mentions: %w", err)
+	}
+	return nil
+}
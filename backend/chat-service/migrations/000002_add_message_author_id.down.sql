@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_messages_author_id;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS author_id;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE messages ADD COLUMN author_id INTEGER;
+
+CREATE INDEX idx_messages_author_id ON messages (author_id);
\ No newline at end of file
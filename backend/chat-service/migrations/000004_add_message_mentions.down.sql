@@ -0,0 +1 @@
+DROP TABLE IF EXISTS message_mentions;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE message_mentions (
+    id SERIAL PRIMARY KEY,
+    message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    offset_utf16 INTEGER NOT NULL,
+    length_utf16 INTEGER NOT NULL
+);
+
+CREATE INDEX idx_message_mentions_message_id ON message_mentions (message_id);
\ No newline at end of file
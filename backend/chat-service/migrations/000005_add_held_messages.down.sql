@@ -0,0 +1 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS held;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Сообщения, скрытые фильтром до проверки модератором
+ALTER TABLE messages ADD COLUMN held BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
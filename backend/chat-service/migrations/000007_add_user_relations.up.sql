@@ -0,0 +1,9 @@
+-- Копия блокировок и заглушений из forum-service, обновляется по событиям
+CREATE TABLE user_relations (
+    user_id INTEGER NOT NULL,
+    target_id INTEGER NOT NULL,
+    kind VARCHAR(10) NOT NULL,
+    PRIMARY KEY (user_id, target_id)
+);
+
+CREATE INDEX idx_user_relations_target_id ON user_relations (target_id);
\ No newline at end of file
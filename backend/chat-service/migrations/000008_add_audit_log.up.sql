@@ -0,0 +1,26 @@
+-- Журнал действий модераторов в этом сервисе. Запись делается в одной
+-- транзакции с действием, auth-service забирает записи в общий журнал
+-- через GET /internal/audit
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INTEGER NOT NULL,
+    actor_role VARCHAR(20) NOT NULL DEFAULT '',
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    before_state JSONB,
+    after_state JSONB,
+    occurred_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Журнал только дополняется: изменить или удалить запись нельзя
+CREATE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX idx_message_mentions_user_id ON message_mentions (user_id);
\ No newline at end of file
@@ -0,0 +1,98 @@
+package contentfilter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DuplicateRule - повтор одного и того же текста одним автором.
+type DuplicateRule struct {
+	// Window - за какой срок одинаковый текст считается повтором
+	Window time.Duration `yaml:"window"`
+	Action string        `yaml:"action"`
+}
+
+// DuplicateStore помнит отпечатки недавнего контента. Он живёт дольше
+// цепочки, чтобы перезагрузка правил не сбрасывала историю.
+type DuplicateStore struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastPrune time.Time
+}
+
+func NewDuplicateStore() *DuplicateStore {
+	return &DuplicateStore{seen: make(map[string]time.Time)}
+}
+
+func (s *DuplicateStore) seenAfter(key string, after time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	at, ok := s.seen[key]
+	return ok && at.After(after)
+}
+
+func (s *DuplicateStore) add(key string, now time.Time, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seen[key] = now
+	if now.Sub(s.lastPrune) < window {
+		return
+	}
+	for k, at := range s.seen {
+		if now.Sub(at) > window {
+			delete(s.seen, k)
+		}
+	}
+	s.lastPrune = now
+}
+
+type duplicateFilter struct {
+	rule  DuplicateRule
+	store *DuplicateStore
+}
+
+func newDuplicateFilter(rule DuplicateRule, store *DuplicateStore) (*duplicateFilter, error) {
+	if err := validateAction(rule.Action, ActionHold, ActionReject); err != nil {
+		return nil, fmt.Errorf("duplicates: %w", err)
+	}
+	if rule.Window <= 0 {
+		return nil, fmt.Errorf("duplicates: window must be positive")
+	}
+	return &duplicateFilter{rule: rule, store: store}, nil
+}
+
+func (f *duplicateFilter) Name() string { return "duplicates" }
+
+func (f *duplicateFilter) Check(_ context.Context, in Input) (Result, error) {
+	if in.Edit || !f.store.seenAfter(fingerprint(in), time.Now().Add(-f.rule.Window)) {
+		return Result{Action: ActionAllow, Title: in.Title, Text: in.Text}, nil
+	}
+	return Result{
+		Action: f.rule.Action,
+		Title:  in.Title,
+		Text:   in.Text,
+		Reason: fmt.Sprintf("same %s was posted within %s", in.Kind, f.rule.Window),
+	}, nil
+}
+
+func (f *duplicateFilter) Record(in Input) {
+	if in.Edit {
+		return
+	}
+	f.store.add(fingerprint(in), time.Now(), f.rule.Window)
+}
+
+// fingerprint не зависит от регистра и пробелов, чтобы повтор нельзя было
+// обойти лишним пробелом.
+func fingerprint(in Input) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(in.Title+"\n"+in.Text), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	return strconv.Itoa(in.AuthorID) + ":" + in.Kind + ":" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,118 @@
+// Package contentfilter - цепочка фильтров, через которую проходит контент
+// перед публикацией: запрещённые слова, ссылки от новых аккаунтов, повторы
+// и злоупотребление Unicode. Каждый фильтр может пропустить контент,
+// замаскировать часть текста, отправить его на проверку модератору или
+// отклонить.
+package contentfilter
+
+import (
+	"context"
+	"log"
+)
+
+// Решения фильтров в порядке строгости
+const (
+	ActionAllow  = "allow"
+	ActionMask   = "mask"
+	ActionHold   = "hold"
+	ActionReject = "reject"
+)
+
+var severity = map[string]int{
+	ActionAllow:  0,
+	ActionMask:   1,
+	ActionHold:   2,
+	ActionReject: 3,
+}
+
+// Input - проверяемый контент. Title пустой для комментариев и сообщений.
+type Input struct {
+	AuthorID int
+	Author   string
+	// Kind - post, comment или message, повторы ищутся в пределах одного вида
+	Kind  string
+	Title string
+	Text  string
+	// Edit - правка уже опубликованного контента, повтором она не считается
+	Edit bool
+}
+
+// Result - решение одного фильтра. Для ActionMask Title и Text - текст
+// после маскировки. Reason - пояснение для модераторов.
+type Result struct {
+	Action string
+	Title  string
+	Text   string
+	Reason string
+}
+
+type Filter interface {
+	Name() string
+	Check(ctx context.Context, in Input) (Result, error)
+}
+
+// Recorder - фильтр, которому нужно знать о пропущенном контенте.
+type Recorder interface {
+	Record(in Input)
+}
+
+// Verdict - итог цепочки: самое строгое из решений фильтров и текст после
+// маскировки. Filters - имена сработавших фильтров, их можно показать автору.
+type Verdict struct {
+	Action  string
+	Title   string
+	Text    string
+	Filters []string
+	Reasons []string
+}
+
+type Chain struct {
+	filters []Filter
+}
+
+func NewChain(filters ...Filter) *Chain {
+	return &Chain{filters: filters}
+}
+
+// Run прогоняет контент через фильтры по порядку. Маскировка меняет текст
+// для следующих фильтров, reject останавливает цепочку. Ошибка фильтра
+// только логируется: недоступный auth-service не должен блокировать публикацию.
+func (c *Chain) Run(ctx context.Context, in Input) Verdict {
+	verdict := Verdict{Action: ActionAllow, Title: in.Title, Text: in.Text}
+	if c == nil {
+		return verdict
+	}
+
+	for _, f := range c.filters {
+		in.Title, in.Text = verdict.Title, verdict.Text
+
+		result, err := f.Check(ctx, in)
+		if err != nil {
+			log.Printf("Content filter %s failed: %v", f.Name(), err)
+			continue
+		}
+		if result.Action == "" || result.Action == ActionAllow {
+			continue
+		}
+
+		verdict.Filters = append(verdict.Filters, f.Name())
+		verdict.Reasons = append(verdict.Reasons, result.Reason)
+		if result.Action == ActionMask {
+			verdict.Title, verdict.Text = result.Title, result.Text
+		}
+		if severity[result.Action] > severity[verdict.Action] {
+			verdict.Action = result.Action
+		}
+		if verdict.Action == ActionReject {
+			return verdict
+		}
+	}
+
+	in.Title, in.Text = verdict.Title, verdict.Text
+	for _, f := range c.filters {
+		if r, ok := f.(Recorder); ok {
+			r.Record(in)
+		}
+	}
+	return verdict
+}
@@ -0,0 +1,127 @@
+package contentfilter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubFilter возвращает заданное решение, маскирует текст через mask.
+type stubFilter struct {
+	name   string
+	action string
+	mask   func(string) string
+	err    error
+
+	seen     []string
+	recorded []Input
+}
+
+func (f *stubFilter) Name() string { return f.name }
+
+func (f *stubFilter) Check(_ context.Context, in Input) (Result, error) {
+	f.seen = append(f.seen, in.Text)
+	if f.err != nil {
+		return Result{}, f.err
+	}
+	result := Result{Action: f.action, Title: in.Title, Text: in.Text, Reason: f.name + " matched"}
+	if f.mask != nil {
+		result.Title, result.Text = f.mask(in.Title), f.mask(in.Text)
+	}
+	return result, nil
+}
+
+func (f *stubFilter) Record(in Input) {
+	f.recorded = append(f.recorded, in)
+}
+
+func TestChainRun(t *testing.T) {
+	stars := func(s string) string { return strings.ReplaceAll(s, "bad", "***") }
+
+	tests := []struct {
+		name     string
+		filters  []*stubFilter
+		want     Verdict
+		seen     map[string][]string
+		recorded bool
+	}{
+		{
+			name:     "no filters",
+			want:     Verdict{Action: ActionAllow, Title: "bad title", Text: "bad text"},
+			recorded: true,
+		},
+		{
+			name:     "all allow",
+			filters:  []*stubFilter{{name: "a", action: ActionAllow}, {name: "b"}},
+			want:     Verdict{Action: ActionAllow, Title: "bad title", Text: "bad text"},
+			recorded: true,
+		},
+		{
+			name:    "mask changes text for next filters",
+			filters: []*stubFilter{{name: "words", action: ActionMask, mask: stars}, {name: "links"}},
+			want: Verdict{Action: ActionMask, Title: "*** title", Text: "*** text",
+				Filters: []string{"words"}, Reasons: []string{"words matched"}},
+			seen:     map[string][]string{"links": {"*** text"}},
+			recorded: true,
+		},
+		{
+			name:    "strictest action wins",
+			filters: []*stubFilter{{name: "a", action: ActionHold}, {name: "b", action: ActionMask, mask: stars}},
+			want: Verdict{Action: ActionHold, Title: "*** title", Text: "*** text",
+				Filters: []string{"a", "b"}, Reasons: []string{"a matched", "b matched"}},
+			recorded: true,
+		},
+		{
+			name:    "reject stops chain",
+			filters: []*stubFilter{{name: "a", action: ActionReject}, {name: "b", action: ActionHold}},
+			want: Verdict{Action: ActionReject, Title: "bad title", Text: "bad text",
+				Filters: []string{"a"}, Reasons: []string{"a matched"}},
+			seen: map[string][]string{"b": nil},
+		},
+		{
+			name:    "failed filter is skipped",
+			filters: []*stubFilter{{name: "a", err: errors.New("auth unavailable")}, {name: "b", action: ActionHold}},
+			want: Verdict{Action: ActionHold, Title: "bad title", Text: "bad text",
+				Filters: []string{"b"}, Reasons: []string{"b matched"}},
+			recorded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := make([]Filter, 0, len(tt.filters))
+			for _, f := range tt.filters {
+				filters = append(filters, f)
+			}
+
+			in := Input{Kind: "post", Title: "bad title", Text: "bad text"}
+			got := NewChain(filters...).Run(context.Background(), in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run() = %+v, want %+v", got, tt.want)
+			}
+
+			for _, f := range tt.filters {
+				if seen, ok := tt.seen[f.name]; ok && !reflect.DeepEqual(f.seen, seen) {
+					t.Errorf("filter %s saw %q, want %q", f.name, f.seen, seen)
+				}
+				if recorded := len(f.recorded) > 0; recorded != tt.recorded {
+					t.Errorf("filter %s recorded = %v, want %v", f.name, recorded, tt.recorded)
+				}
+				// Записывается итоговый текст, а не исходный
+				if len(f.recorded) > 0 && f.recorded[0].Text != tt.want.Text {
+					t.Errorf("filter %s recorded text %q, want %q", f.name, f.recorded[0].Text, tt.want.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestNilChainAllows(t *testing.T) {
+	var chain *Chain
+	got := chain.Run(context.Background(), Input{Text: "text"})
+	if got.Action != ActionAllow || got.Text != "text" {
+		t.Errorf("nil chain Run() = %+v, want allow", got)
+	}
+}
@@ -0,0 +1,72 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+`)
+
+// LinkRule ограничивает число ссылок от новых аккаунтов.
+type LinkRule struct {
+	MaxLinks int `yaml:"max_links"`
+	// NewAccountAge - аккаунт младше этого считается новым. 0 - ограничение для всех
+	NewAccountAge time.Duration `yaml:"new_account_age"`
+	// Action mask заменяет ссылки на [link removed]
+	Action string `yaml:"action"`
+}
+
+// AccountSource сообщает дату регистрации, в приложении это клиент auth-service.
+type AccountSource interface {
+	JoinedAt(ctx context.Context, username string) (time.Time, error)
+}
+
+type linkFilter struct {
+	rule     LinkRule
+	accounts AccountSource
+}
+
+func newLinkFilter(rule LinkRule, accounts AccountSource) (*linkFilter, error) {
+	if err := validateAction(rule.Action, ActionMask, ActionHold, ActionReject); err != nil {
+		return nil, fmt.Errorf("links: %w", err)
+	}
+	if rule.MaxLinks < 0 {
+		return nil, fmt.Errorf("links: max_links must not be negative")
+	}
+	return &linkFilter{rule: rule, accounts: accounts}, nil
+}
+
+func (f *linkFilter) Name() string { return "links" }
+
+func (f *linkFilter) Check(ctx context.Context, in Input) (Result, error) {
+	allow := Result{Action: ActionAllow, Title: in.Title, Text: in.Text}
+
+	count := len(linkPattern.FindAllStringIndex(in.Title, -1)) + len(linkPattern.FindAllStringIndex(in.Text, -1))
+	if count <= f.rule.MaxLinks {
+		return allow, nil
+	}
+
+	if f.rule.NewAccountAge > 0 {
+		joinedAt, err := f.accounts.JoinedAt(ctx, in.Author)
+		if err != nil {
+			return allow, fmt.Errorf("failed to get account age: %w", err)
+		}
+		if time.Since(joinedAt) >= f.rule.NewAccountAge {
+			return allow, nil
+		}
+	}
+
+	result := Result{
+		Action: f.rule.Action,
+		Title:  in.Title,
+		Text:   in.Text,
+		Reason: fmt.Sprintf("%d links from a new account, at most %d allowed", count, f.rule.MaxLinks),
+	}
+	if f.rule.Action == ActionMask {
+		result.Title = linkPattern.ReplaceAllString(in.Title, "[link removed]")
+		result.Text = linkPattern.ReplaceAllString(in.Text, "[link removed]")
+	}
+	return result, nil
+}
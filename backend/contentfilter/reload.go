@@ -0,0 +1,110 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Config - где лежат правила и как часто их перечитывать. Сервисы встраивают
+// его в свой конфиг.
+type Config struct {
+	// RulesPath - файл правил, пустой путь отключает фильтрацию
+	RulesPath string `yaml:"rules_path"`
+	// ReloadInterval - как часто проверять, изменился ли файл. 0 - не перечитывать
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+// Reloader держит актуальную цепочку и перечитывает файл правил, когда он
+// меняется. Ошибка в новом файле не сбрасывает рабочие правила.
+type Reloader struct {
+	path       string
+	interval   time.Duration
+	accounts   AccountSource
+	duplicates *DuplicateStore
+
+	chain   atomic.Pointer[Chain]
+	modTime time.Time
+}
+
+// NewReloader загружает правила. Без RulesPath фильтрация отключена и
+// Run пропускает любой контент.
+func NewReloader(cfg Config, accounts AccountSource) (*Reloader, error) {
+	r := &Reloader{
+		path:       cfg.RulesPath,
+		interval:   cfg.ReloadInterval,
+		accounts:   accounts,
+		duplicates: NewDuplicateStore(),
+	}
+	r.chain.Store(NewChain())
+
+	if r.path != "" {
+		if err := r.Reload(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Reloader) Run(ctx context.Context, in Input) Verdict {
+	return r.chain.Load().Run(ctx, in)
+}
+
+// Reload перечитывает файл правил и подменяет цепочку.
+func (r *Reloader) Reload() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat content filter rules: %w", err)
+	}
+
+	rules, err := LoadRules(r.path)
+	if err != nil {
+		return err
+	}
+	chain, err := Build(rules, r.accounts, r.duplicates)
+	if err != nil {
+		return fmt.Errorf("invalid content filter rules: %w", err)
+	}
+
+	r.chain.Store(chain)
+	r.modTime = info.ModTime()
+	return nil
+}
+
+// Watch проверяет время изменения файла каждые ReloadInterval, пока не
+// отменён ctx. Без пути или интервала сразу возвращается.
+func (r *Reloader) Watch(ctx context.Context) {
+	if r.path == "" || r.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(r.path)
+			if err != nil {
+				log.Printf("Failed to check content filter rules: %v", err)
+				continue
+			}
+			if info.ModTime().Equal(r.modTime) {
+				continue
+			}
+
+			if err := r.Reload(); err != nil {
+				log.Printf("Keeping previous content filter rules: %v", err)
+				// Не повторяем ошибку на каждом тике, ждём следующей правки файла
+				r.modTime = info.ModTime()
+				continue
+			}
+			log.Printf("Content filter rules reloaded from %s", r.path)
+		}
+	}
+}
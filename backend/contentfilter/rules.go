@@ -0,0 +1,75 @@
+package contentfilter
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules - файл правил фильтрации. Не заданный раздел отключает фильтр.
+type Rules struct {
+	BannedWords []WordRule     `yaml:"banned_words"`
+	Links       *LinkRule      `yaml:"links"`
+	Duplicates  *DuplicateRule `yaml:"duplicates"`
+	Unicode     *UnicodeRule   `yaml:"unicode"`
+}
+
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content filter rules: %w", err)
+	}
+
+	rules := &Rules{}
+	if err := yaml.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("failed to parse content filter rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Build собирает цепочку по правилам. Unicode идёт первым, чтобы слова
+// нельзя было спрятать за комбинируемыми знаками, повторы - последними.
+func Build(rules *Rules, accounts AccountSource, duplicates *DuplicateStore) (*Chain, error) {
+	var filters []Filter
+
+	if rules.Unicode != nil {
+		f, err := newUnicodeFilter(*rules.Unicode)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if len(rules.BannedWords) > 0 {
+		f, err := newWordFilter(rules.BannedWords)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if rules.Links != nil {
+		f, err := newLinkFilter(*rules.Links, accounts)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if rules.Duplicates != nil {
+		f, err := newDuplicateFilter(*rules.Duplicates, duplicates)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+
+	return NewChain(filters...), nil
+}
+
+func validateAction(action string, allowed ...string) error {
+	if !slices.Contains(allowed, action) {
+		return fmt.Errorf("action must be one of: %s", strings.Join(allowed, ", "))
+	}
+	return nil
+}
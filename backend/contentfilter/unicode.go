@@ -0,0 +1,86 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// UnicodeRule - злоупотребление Unicode: zalgo из нагромождения
+// комбинируемых знаков и управляющие символы направления текста.
+type UnicodeRule struct {
+	// MaxCombining - сколько комбинируемых знаков подряд допускается на
+	// символ, в обычных языках хватает двух-трёх
+	MaxCombining int `yaml:"max_combining"`
+	// Action mask удаляет лишние знаки и управляющие символы
+	Action string `yaml:"action"`
+}
+
+type unicodeFilter struct {
+	rule UnicodeRule
+}
+
+func newUnicodeFilter(rule UnicodeRule) (*unicodeFilter, error) {
+	if err := validateAction(rule.Action, ActionMask, ActionHold, ActionReject); err != nil {
+		return nil, fmt.Errorf("unicode: %w", err)
+	}
+	if rule.MaxCombining < 1 {
+		return nil, fmt.Errorf("unicode: max_combining must be at least 1")
+	}
+	return &unicodeFilter{rule: rule}, nil
+}
+
+func (f *unicodeFilter) Name() string { return "unicode" }
+
+func (f *unicodeFilter) Check(_ context.Context, in Input) (Result, error) {
+	title, titleAbused := f.clean(in.Title)
+	text, textAbused := f.clean(in.Text)
+	if !titleAbused && !textAbused {
+		return Result{Action: ActionAllow, Title: in.Title, Text: in.Text}, nil
+	}
+
+	result := Result{
+		Action: f.rule.Action,
+		Title:  in.Title,
+		Text:   in.Text,
+		Reason: "stacked combining marks or direction control characters",
+	}
+	if f.rule.Action == ActionMask {
+		result.Title, result.Text = title, text
+	}
+	return result, nil
+}
+
+// clean убирает лишние комбинируемые знаки и управляющие символы
+// направления, второе значение - было ли что убирать.
+func (f *unicodeFilter) clean(text string) (string, bool) {
+	var (
+		b         strings.Builder
+		combining int
+		abused    bool
+	)
+	for _, r := range text {
+		if isDirectionControl(r) {
+			abused = true
+			continue
+		}
+		if unicode.In(r, unicode.Mn, unicode.Me) {
+			combining++
+			if combining > f.rule.MaxCombining {
+				abused = true
+				continue
+			}
+		} else {
+			combining = 0
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), abused
+}
+
+// isDirectionControl - встраивания и переопределения направления, ими
+// подменяют видимый порядок символов, например в ссылках.
+func isDirectionControl(r rune) bool {
+	return (r >= '\u202a' && r <= '\u202e') || (r >= '\u2066' && r <= '\u2069')
+}
@@ -0,0 +1,125 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordRule - запрещённое слово или регулярное выражение.
+type WordRule struct {
+	Pattern string `yaml:"pattern"`
+	// Regex - Pattern это регулярное выражение RE2, иначе слово целиком
+	// без учёта регистра
+	Regex  bool   `yaml:"regex"`
+	Action string `yaml:"action"`
+}
+
+type wordMatcher struct {
+	rule WordRule
+	re   *regexp.Regexp
+}
+
+type wordFilter struct {
+	matchers []wordMatcher
+}
+
+func newWordFilter(rules []WordRule) (*wordFilter, error) {
+	f := &wordFilter{}
+	for _, rule := range rules {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("banned_words: empty pattern")
+		}
+		if err := validateAction(rule.Action, ActionMask, ActionHold, ActionReject); err != nil {
+			return nil, fmt.Errorf("banned_words %q: %w", rule.Pattern, err)
+		}
+
+		pattern := "(?i)" + regexp.QuoteMeta(rule.Pattern)
+		if rule.Regex {
+			pattern = rule.Pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("banned_words %q: %w", rule.Pattern, err)
+		}
+		f.matchers = append(f.matchers, wordMatcher{rule: rule, re: re})
+	}
+	return f, nil
+}
+
+func (f *wordFilter) Name() string { return "banned_words" }
+
+func (f *wordFilter) Check(_ context.Context, in Input) (Result, error) {
+	result := Result{Action: ActionAllow, Title: in.Title, Text: in.Text}
+
+	var matched []string
+	for _, m := range f.matchers {
+		titleHits := m.find(result.Title)
+		textHits := m.find(result.Text)
+		if len(titleHits) == 0 && len(textHits) == 0 {
+			continue
+		}
+
+		matched = append(matched, m.rule.Pattern)
+		if m.rule.Action == ActionMask {
+			result.Title = mask(result.Title, titleHits)
+			result.Text = mask(result.Text, textHits)
+		}
+		if severity[m.rule.Action] > severity[result.Action] {
+			result.Action = m.rule.Action
+		}
+	}
+
+	if len(matched) > 0 {
+		result.Reason = "banned words: " + strings.Join(matched, ", ")
+	}
+	return result, nil
+}
+
+// find возвращает границы совпадений. Простое слово должно стоять отдельно,
+// а не быть частью другого слова.
+func (m wordMatcher) find(text string) [][]int {
+	if text == "" {
+		return nil
+	}
+
+	hits := m.re.FindAllStringIndex(text, -1)
+	if m.rule.Regex {
+		return hits
+	}
+
+	var whole [][]int
+	for _, hit := range hits {
+		before, _ := utf8.DecodeLastRuneInString(text[:hit[0]])
+		after, _ := utf8.DecodeRuneInString(text[hit[1]:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+		whole = append(whole, hit)
+	}
+	return whole
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// mask заменяет каждый символ совпадений на *.
+func mask(text string, hits [][]int) string {
+	if len(hits) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, hit := range hits {
+		b.WriteString(text[last:hit[0]])
+		b.WriteString(strings.Repeat("*", utf8.RuneCountInString(text[hit[0]:hit[1]])))
+		last = hit[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
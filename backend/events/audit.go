@@ -0,0 +1,77 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const (
+	defaultAuditBatch = 100
+	maxAuditBatch     = 1000
+)
+
+// RecordAudit пишет записи в audit_log сервиса в транзакции действия,
+// которое они описывают: без записи в журнале действие не сохранится.
+func RecordAudit(ctx context.Context, tx *sql.Tx, entries ...AuditRecorded) error {
+	for _, entry := range entries {
+		if entry.OccurredAt.IsZero() {
+			entry.OccurredAt = time.Now()
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO audit_log (actor_id, actor_role, action, target_type, target_id, reason,
+				before_state, after_state, occurred_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::jsonb, NULLIF($8, '')::jsonb, $9)`,
+			entry.ActorID, entry.ActorRole, entry.Action, entry.TargetType, entry.TargetID, entry.Reason,
+			string(entry.Before), string(entry.After), entry.OccurredAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AuditAfter возвращает записи audit_log с ID больше afterID по возрастанию,
+// не больше 1000 за раз. По нему auth-service забирает новые записи в общий
+// журнал.
+func AuditAfter(ctx context.Context, db *sql.DB, afterID int64, limit int) ([]AuditRecorded, error) {
+	if limit <= 0 {
+		limit = defaultAuditBatch
+	}
+	if limit > maxAuditBatch {
+		limit = maxAuditBatch
+	}
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT id, actor_id, actor_role, action, target_type, target_id, reason,
+			COALESCE(before_state::text, ''), COALESCE(after_state::text, ''), occurred_at
+		 FROM audit_log WHERE id > $1 ORDER BY id LIMIT $2`,
+		afterID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []AuditRecorded
+	for rows.Next() {
+		var (
+			entry         AuditRecorded
+			before, after string
+		)
+		if err := rows.Scan(&entry.ID, &entry.ActorID, &entry.ActorRole, &entry.Action, &entry.TargetType,
+			&entry.TargetID, &entry.Reason, &before, &after, &entry.OccurredAt); err != nil {
+			return nil, err
+		}
+		if before != "" {
+			entry.Before = []byte(before)
+		}
+		if after != "" {
+			entry.After = []byte(after)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	DriverMemory   = "memory"
+	DriverPostgres = "postgres"
+)
+
+type Config struct {
+	// Driver - memory (все сервисы в одном процессе, main.go)
+	// или postgres (LISTEN/NOTIFY, сервисы запущены отдельно)
+	Driver string `yaml:"driver"`
+	// DSN - общая для всех сервисов база, через которую идут уведомления
+	DSN     string `yaml:"dsn"`
+	Channel string `yaml:"channel"`
+
+	RelayInterval time.Duration `yaml:"relay_interval"`
+	BatchSize     int           `yaml:"batch_size"`
+	// MaxAttempts - после стольких неудачных отправок событие помечается
+	// failed_at и больше не отправляется
+	MaxAttempts int `yaml:"max_attempts"`
+	// Retention - сколько хранить уже опубликованные события в outbox
+	Retention time.Duration `yaml:"retention"`
+}
+
+type Handler func(ctx context.Context, envelope Envelope) error
+
+type Bus interface {
+	Publish(ctx context.Context, envelope Envelope) error
+	// Subscribe регистрирует обработчик событий указанного типа. Ошибка
+	// обработчика не мешает остальным подписчикам. MemoryBus возвращает её
+	// из Publish, и Relay доставит событие повторно; PostgresBus только
+	// логирует: NOTIFY не знает, обработал ли событие получатель.
+	Subscribe(eventType string, handler Handler)
+	Close() error
+}
+
+// Subscribe - типизированная подписка: payload разбирается в схему T.
+func Subscribe[T Event](bus Bus, handler func(ctx context.Context, event T) error) {
+	var zero T
+	bus.Subscribe(zero.EventType(), func(ctx context.Context, envelope Envelope) error {
+		var event T
+		if err := json.Unmarshal(envelope.Payload, &event); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", envelope.Type, err)
+		}
+		return handler(ctx, event)
+	})
+}
+
+var (
+	processBus     *MemoryBus
+	processBusOnce sync.Once
+)
+
+// NewBus создаёт шину по конфигу. Драйвер memory возвращает одну шину на
+// процесс, так сервисы, запущенные из main.go, видят события друг друга.
+func NewBus(cfg Config) (Bus, error) {
+	switch cfg.Driver {
+	case "", DriverMemory:
+		processBusOnce.Do(func() {
+			processBus = NewMemoryBus()
+		})
+		return processBus, nil
+	case DriverPostgres:
+		return NewPostgresBus(cfg.DSN, cfg.Channel)
+	default:
+		return nil, fmt.Errorf("unknown event bus driver: %s", cfg.Driver)
+	}
+}
+
+type handlerSet struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func (s *handlerSet) Subscribe(eventType string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[string][]Handler)
+	}
+	s.handlers[eventType] = append(s.handlers[eventType], handler)
+}
+
+// dispatch вызывает всех подписчиков и возвращает их ошибки.
+func (s *handlerSet) dispatch(ctx context.Context, envelope Envelope) error {
+	s.mu.RLock()
+	handlers := s.handlers[envelope.Type]
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, envelope); err != nil {
+			log.Printf("Failed to handle event %s (%s): %v", envelope.ID, envelope.Type, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
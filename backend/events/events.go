@@ -0,0 +1,27 @@
+// Package events - доменные события, общие для всех сервисов: схемы событий,
+// шина для их доставки и transactional outbox, через который сервисы
+// публикуют события вместе с изменением своих данных.
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event - схема доменного события. EventType должен быть объявлен на
+// значении, а не на указателе: по нулевому значению определяется тип подписки.
+type Event interface {
+	EventType() string
+}
+
+// Envelope - событие в том виде, в каком оно идёт по шине.
+type Envelope struct {
+	// ID уникален в пределах всех сервисов. С MemoryBus доставка
+	// at-least-once, и подписчики могут дедуплицировать события по ID;
+	// с PostgresBus - at-most-once (см. Relay)
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Source     string          `json:"source"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
@@ -0,0 +1,22 @@
+package events
+
+import "context"
+
+// MemoryBus доставляет события синхронно в том же процессе. Publish
+// возвращает ошибки подписчиков, поэтому событие остаётся в outbox, пока
+// его не обработают все.
+type MemoryBus struct {
+	handlerSet
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{}
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, envelope Envelope) error {
+	return b.dispatch(ctx, envelope)
+}
+
+func (b *MemoryBus) Close() error {
+	return nil
+}
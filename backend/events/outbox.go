@@ -0,0 +1,21 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// Enqueue записывает событие в таблицу outbox в транзакции изменения,
+// которое его породило. Публикует события Relay.
+func Enqueue(ctx context.Context, tx *sql.Tx, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", event.EventType(), err)
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO outbox (event_type, payload) VALUES ($1, $2)",
+		event.EventType(), payload)
+	return err
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const defaultChannel = "domain_events"
+
+// PostgresBus рассылает события через NOTIFY в общей базе. Уведомления
+// не хранятся: сервис, который был недоступен, пропустит события.
+// Размер события ограничен лимитом NOTIFY (8000 байт).
+type PostgresBus struct {
+	handlerSet
+	db       *sql.DB
+	listener *pq.Listener
+	channel  string
+	done     chan struct{}
+}
+
+func NewPostgresBus(dsn, channel string) (*PostgresBus, error) {
+	if dsn == "" {
+		return nil, errors.New("event bus dsn is required for postgres driver")
+	}
+	if channel == "" {
+		channel = defaultChannel
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Event bus listener error: %v", err)
+		}
+	})
+	if err := listener.Listen(channel); err != nil {
+		listener.Close()
+		db.Close()
+		return nil, err
+	}
+
+	bus := &PostgresBus{
+		db:       db,
+		listener: listener,
+		channel:  channel,
+		done:     make(chan struct{}),
+	}
+	go bus.listen()
+
+	return bus, nil
+}
+
+func (b *PostgresBus) Publish(ctx context.Context, envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	_, err = b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", b.channel, string(data))
+	return err
+}
+
+func (b *PostgresBus) listen() {
+	for {
+		select {
+		case <-b.done:
+			return
+		case notification, ok := <-b.listener.Notify:
+			if !ok {
+				return
+			}
+			// nil приходит после переподключения
+			if notification == nil {
+				continue
+			}
+
+			var envelope Envelope
+			if err := json.Unmarshal([]byte(notification.Extra), &envelope); err != nil {
+				log.Printf("Invalid event on bus: %v", err)
+				continue
+			}
+			// Ошибки уже залогированы в dispatch, повторить доставку нельзя
+			_ = b.dispatch(context.Background(), envelope)
+		case <-time.After(90 * time.Second):
+			go b.listener.Ping()
+		}
+	}
+}
+
+func (b *PostgresBus) Close() error {
+	close(b.done)
+	b.listener.Close()
+	return b.db.Close()
+}
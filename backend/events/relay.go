@@ -0,0 +1,226 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	defaultRelayInterval = time.Second
+	defaultBatchSize     = 100
+	defaultMaxAttempts   = 10
+	maxRetryDelay        = time.Hour
+	cleanupInterval      = time.Hour
+)
+
+// Relay переносит события из outbox сервиса в шину. Событие помечается
+// опубликованным только после успешной отправки, поэтому при сбое оно
+// может прийти подписчикам повторно. С MemoryBus отправка успешна, когда
+// событие обработали все подписчики, и доставка at-least-once. С
+// PostgresBus успех значит только выполненный NOTIFY: получатель, который
+// в этот момент не слушал канал или упал на обработке, событие теряет.
+//
+// Событие, которое не удалось отправить, откладывается с растущей паузой и
+// не задерживает следующие. После MaxAttempts неудач ему ставится
+// failed_at: оно остаётся в outbox для разбора и больше не отправляется.
+type Relay struct {
+	db          *sql.DB
+	bus         Bus
+	source      string
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	retention   time.Duration
+}
+
+type outboxRow struct {
+	id       int64
+	attempts int
+	envelope Envelope
+	err      error
+}
+
+func NewRelay(db *sql.DB, bus Bus, source string, cfg Config) *Relay {
+	relay := &Relay{
+		db:          db,
+		bus:         bus,
+		source:      source,
+		interval:    cfg.RelayInterval,
+		batchSize:   cfg.BatchSize,
+		maxAttempts: cfg.MaxAttempts,
+		retention:   cfg.Retention,
+	}
+	if relay.interval <= 0 {
+		relay.interval = defaultRelayInterval
+	}
+	if relay.batchSize <= 0 {
+		relay.batchSize = defaultBatchSize
+	}
+	if relay.maxAttempts <= 0 {
+		relay.maxAttempts = defaultMaxAttempts
+	}
+	return relay
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			processed, err := r.publishBatch(ctx)
+			if err != nil {
+				log.Printf("Failed to relay %s events: %v", r.source, err)
+				break
+			}
+			if processed < r.batchSize {
+				break
+			}
+		}
+
+		if r.retention > 0 && time.Since(lastCleanup) > cleanupInterval {
+			lastCleanup = time.Now()
+			if err := r.cleanup(ctx); err != nil {
+				log.Printf("Failed to clean %s outbox: %v", r.source, err)
+			}
+		}
+	}
+}
+
+// publishBatch отправляет пачку событий, которым пора уходить, и
+// возвращает, сколько строк outbox обработано.
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED позволяет запускать несколько экземпляров сервиса
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, attempts, event_type, payload, created_at FROM outbox
+		 WHERE published_at IS NULL AND failed_at IS NULL
+		   AND (next_attempt_at IS NULL OR next_attempt_at <= NOW())
+		 ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`,
+		r.batchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var batch []outboxRow
+	for rows.Next() {
+		var row outboxRow
+		if err := rows.Scan(&row.id, &row.attempts, &row.envelope.Type, &row.envelope.Payload,
+			&row.envelope.OccurredAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		row.envelope.ID = fmt.Sprintf("%s-%d", r.source, row.id)
+		row.envelope.Source = r.source
+		batch = append(batch, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	published, failed := r.deliver(ctx, batch)
+
+	if len(published) > 0 {
+		if _, err := tx.ExecContext(ctx, "UPDATE outbox SET published_at = NOW() WHERE id = ANY($1)",
+			pq.Array(published)); err != nil {
+			return 0, err
+		}
+	}
+	for _, row := range failed {
+		if err := r.markFailed(ctx, tx, row); err != nil {
+			return 0, err
+		}
+	}
+	if len(published) > 0 || len(failed) > 0 {
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+	}
+
+	// При остановке сервиса пачка обработана не вся, следующей не нужно
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return len(batch), nil
+}
+
+// deliver отправляет события по одному. Ошибка одного события не
+// останавливает остальные, иначе оно навсегда заблокировало бы outbox.
+// У неудачных строк attempts увеличивается, а в err - причина.
+func (r *Relay) deliver(ctx context.Context, batch []outboxRow) (published []int64, failed []outboxRow) {
+	for _, row := range batch {
+		// Отмена контекста - не вина события, попытка не засчитывается
+		if ctx.Err() != nil {
+			break
+		}
+		if err := r.bus.Publish(ctx, row.envelope); err != nil {
+			row.attempts++
+			row.err = err
+			failed = append(failed, row)
+			continue
+		}
+		published = append(published, row.id)
+	}
+	return published, failed
+}
+
+func (r *Relay) markFailed(ctx context.Context, tx *sql.Tx, row outboxRow) error {
+	if r.exhausted(row.attempts) {
+		log.Printf("Giving up on %s event %s after %d attempts: %v",
+			row.envelope.Type, row.envelope.ID, row.attempts, row.err)
+		_, err := tx.ExecContext(ctx,
+			"UPDATE outbox SET attempts = $2, last_error = $3, failed_at = NOW() WHERE id = $1",
+			row.id, row.attempts, row.err.Error(),
+		)
+		return err
+	}
+
+	log.Printf("Failed to publish %s event %s (attempt %d): %v",
+		row.envelope.Type, row.envelope.ID, row.attempts, row.err)
+	_, err := tx.ExecContext(ctx,
+		`UPDATE outbox SET attempts = $2, last_error = $3,
+			next_attempt_at = NOW() + $4 * INTERVAL '1 second'
+		 WHERE id = $1`,
+		row.id, row.attempts, row.err.Error(), r.retryDelay(row.attempts).Seconds(),
+	)
+	return err
+}
+
+func (r *Relay) exhausted(attempts int) bool {
+	return attempts >= r.maxAttempts
+}
+
+// retryDelay - пауза после attempts неудач: интервал relay, удваиваемый
+// с каждой неудачей, но не больше maxRetryDelay.
+func (r *Relay) retryDelay(attempts int) time.Duration {
+	delay := r.interval
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+func (r *Relay) cleanup(ctx context.Context) error {
+	// Строки с failed_at не удаляются: их нужно разобрать вручную
+	_, err := r.db.ExecContext(ctx, "DELETE FROM outbox WHERE published_at < $1", time.Now().Add(-r.retention))
+	return err
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRelayDeliverSkipsPoisonedEvent(t *testing.T) {
+	bus := NewMemoryBus()
+	var delivered []string
+	bus.Subscribe(TypeUserRenamed, func(ctx context.Context, envelope Envelope) error {
+		delivered = append(delivered, envelope.ID)
+		return nil
+	})
+	// Обработчик, который падает на каждом событии
+	bus.Subscribe(TypeUserDeleted, func(ctx context.Context, envelope Envelope) error {
+		return errors.New("handler keeps failing")
+	})
+
+	relay := NewRelay(nil, bus, "test", Config{MaxAttempts: 3})
+	batch := []outboxRow{
+		{id: 1, envelope: Envelope{ID: "test-1", Type: TypeUserDeleted}},
+		{id: 2, envelope: Envelope{ID: "test-2", Type: TypeUserRenamed}},
+		{id: 3, envelope: Envelope{ID: "test-3", Type: TypeUserRenamed}},
+	}
+
+	published, failed := relay.deliver(context.Background(), batch)
+	if !reflect.DeepEqual(published, []int64{2, 3}) {
+		t.Errorf("published = %v, want [2 3]", published)
+	}
+	if !reflect.DeepEqual(delivered, []string{"test-2", "test-3"}) {
+		t.Errorf("delivered = %v, want events after the poisoned one", delivered)
+	}
+
+	// Повторные попытки, пока событие не будет снято с отправки
+	for round := 1; round <= 3; round++ {
+		if len(failed) != 1 || failed[0].id != 1 {
+			t.Fatalf("round %d: failed = %+v, want event 1", round, failed)
+		}
+		if failed[0].attempts != round || failed[0].err == nil {
+			t.Fatalf("round %d: attempts = %d, err = %v", round, failed[0].attempts, failed[0].err)
+		}
+		if exhausted := relay.exhausted(failed[0].attempts); exhausted != (round == 3) {
+			t.Fatalf("round %d: exhausted = %v", round, exhausted)
+		}
+		if round < 3 {
+			_, failed = relay.deliver(context.Background(), failed)
+		}
+	}
+}
+
+func TestRelayDeliverStopsOnCancel(t *testing.T) {
+	bus := NewMemoryBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	relay := NewRelay(nil, bus, "test", Config{})
+	published, failed := relay.deliver(ctx, []outboxRow{{id: 1, envelope: Envelope{Type: TypeUserDeleted}}})
+	if len(published) != 0 || len(failed) != 0 {
+		t.Errorf("deliver() = %v, %v; want nothing after cancel", published, failed)
+	}
+}
+
+func TestRelayRetryDelay(t *testing.T) {
+	relay := NewRelay(nil, NewMemoryBus(), "test", Config{RelayInterval: time.Second})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{12, 2048 * time.Second},
+		{13, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := relay.retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
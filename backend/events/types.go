@@ -0,0 +1,188 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	TypeUserBanned     = "user.banned"
+	TypeUserUnbanned   = "user.unbanned"
+	TypeUserRenamed    = "user.renamed"
+	TypeUserDeleted    = "user.deleted"
+	TypePostCreated    = "post.created"
+	TypePostDeleted    = "post.deleted"
+	TypeCommentCreated = "comment.created"
+	TypeMessageCreated = "message.created"
+
+	TypeNotificationCreated = "notification.created"
+	TypeContentHeld         = "content.held"
+	TypeRelationChanged     = "user.relation_changed"
+)
+
+// UserBanned публикует auth-service. Нулевой ExpiresAt - бессрочная блокировка.
+type UserBanned struct {
+	UserID    int       `json:"user_id"`
+	Reason    string    `json:"reason"`
+	BannedBy  int       `json:"banned_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (UserBanned) EventType() string { return TypeUserBanned }
+
+type UserUnbanned struct {
+	UserID    int `json:"user_id"`
+	RevokedBy int `json:"revoked_by"`
+}
+
+func (UserUnbanned) EventType() string { return TypeUserUnbanned }
+
+type UserRenamed struct {
+	UserID      int    `json:"user_id"`
+	OldUsername string `json:"old_username"`
+	NewUsername string `json:"new_username"`
+}
+
+func (UserRenamed) EventType() string { return TypeUserRenamed }
+
+// UserDeleted публикуется после обезличивания аккаунта в auth-service.
+type UserDeleted struct {
+	UserID int `json:"user_id"`
+}
+
+func (UserDeleted) EventType() string { return TypeUserDeleted }
+
+type PostCreated struct {
+	PostID   int    `json:"post_id"`
+	AuthorID int    `json:"author_id"`
+	Author   string `json:"author"`
+	Title    string `json:"title"`
+
+	// MentionedUserIDs - пользователи, упомянутые в тексте через @
+	MentionedUserIDs []int `json:"mentioned_user_ids,omitempty"`
+}
+
+func (PostCreated) EventType() string { return TypePostCreated }
+
+type PostDeleted struct {
+	PostID   int `json:"post_id"`
+	AuthorID int `json:"author_id"`
+}
+
+func (PostDeleted) EventType() string { return TypePostDeleted }
+
+type CommentCreated struct {
+	CommentID int    `json:"comment_id"`
+	PostID    int    `json:"post_id"`
+	ParentID  int    `json:"parent_id"`
+	AuthorID  int    `json:"author_id"`
+	Author    string `json:"author"`
+	Content   string `json:"content"`
+
+	MentionedUserIDs []int `json:"mentioned_user_ids,omitempty"`
+}
+
+func (CommentCreated) EventType() string { return TypeCommentCreated }
+
+type MessageCreated struct {
+	MessageID int    `json:"message_id"`
+	AuthorID  int    `json:"author_id"`
+	Author    string `json:"author"`
+	Text      string `json:"text"`
+
+	MentionedUserIDs []int `json:"mentioned_user_ids,omitempty"`
+}
+
+func (MessageCreated) EventType() string { return TypeMessageCreated }
+
+// NotificationCreated публикует forum-service для доставки уведомления
+// пользователю в реальном времени.
+type NotificationCreated struct {
+	NotificationID int       `json:"notification_id"`
+	UserID         int       `json:"user_id"`
+	Type           string    `json:"type"`
+	ActorID        int       `json:"actor_id"`
+	Actor          string    `json:"actor"`
+	PostID         int       `json:"post_id"`
+	CommentID      int       `json:"comment_id"`
+	MessageID      int       `json:"message_id,omitempty"`
+	Text           string    `json:"text"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (NotificationCreated) EventType() string { return TypeNotificationCreated }
+
+// ContentHeld публикуется, когда фильтр контента скрыл пост, комментарий
+// или сообщение до проверки модератором. forum-service ставит такой
+// контент в очередь модерации.
+type ContentHeld struct {
+	Service    string   `json:"service"`
+	TargetType string   `json:"target_type"`
+	TargetID   int      `json:"target_id"`
+	AuthorID   int      `json:"author_id"`
+	Text       string   `json:"text"`
+	Reasons    []string `json:"reasons"`
+}
+
+func (ContentHeld) EventType() string { return TypeContentHeld }
+
+// RelationChanged публикует forum-service, когда пользователь блокирует или
+// заглушает другого. Kind - block или mute, пустой Kind - отношение снято.
+type RelationChanged struct {
+	UserID   int    `json:"user_id"`
+	TargetID int    `json:"target_id"`
+	Kind     string `json:"kind"`
+}
+
+func (RelationChanged) EventType() string { return TypeRelationChanged }
+
+// Действия, которые попадают в журнал аудита
+const (
+	AuditUserBan        = "user.ban"
+	AuditUserUnban      = "user.unban"
+	AuditUserRoleChange = "user.role_change"
+	AuditUserWarn       = "user.warn"
+	AuditPostRemove     = "post.remove"
+	AuditPostPin        = "post.pin"
+	AuditPostUnpin      = "post.unpin"
+	AuditPostLock       = "post.lock"
+	AuditPostUnlock     = "post.unlock"
+	AuditPostArchive    = "post.archive"
+	AuditPostUnarchive  = "post.unarchive"
+	AuditCommentRemove  = "comment.remove"
+	AuditMessageRemove  = "message.remove"
+	AuditReportResolve  = "report.resolve"
+)
+
+// AuditRecorded - запись журнала действий модераторов и администраторов.
+// По шине не ходит: каждый сервис пишет записи в свой audit_log в одной
+// транзакции с действием (RecordAudit), а auth-service собирает их в общий
+// журнал через служебный API. Before и After - состояние объекта до и
+// после действия в JSON, могут быть пустыми.
+type AuditRecorded struct {
+	// ID - номер записи в audit_log сервиса, где она сделана
+	ID int64 `json:"id"`
+	// Service проставляет auth-service, когда забирает запись
+	Service    string          `json:"service"`
+	ActorID    int             `json:"actor_id"`
+	ActorRole  string          `json:"actor_role"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   int             `json:"target_id"`
+	Reason     string          `json:"reason"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// Snapshot сериализует состояние объекта для AuditRecorded. nil остаётся пустым.
+func Snapshot(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -5,12 +5,18 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/gin-gonic/gin"
+	"go-forum-project/contentfilter"
+	"go-forum-project/events"
 	"go-forum-project/forum-service/internal/client"
 	"go-forum-project/forum-service/internal/config"
 	"go-forum-project/forum-service/internal/delivery/http/router"
+	"go-forum-project/forum-service/internal/delivery/subscriber"
 	"go-forum-project/forum-service/internal/middleware"
 	"go-forum-project/forum-service/internal/repo"
 	"go-forum-project/forum-service/internal/usecase"
+	"go-forum-project/mailer"
+	"go-forum-project/ratelimit"
 	"log"
 	"net/http"
 	"os"
@@ -33,19 +39,92 @@ func RunForumApp() {
 
 	authClient, err := client.NewAuthClient(context.Background(), cfg)
 	if err != nil {
-		log.Fatalf("failed to create auth client: %w", err)
+		log.Fatalf("failed to create auth client: %v", err)
 	}
 	defer authClient.Close()
 
 	postRepo := repo.NewPostRepo(db)
 	commentRepo := repo.NewCommentRepo(db)
+	notificationRepo := repo.NewNotificationRepo(db)
+	mentionRepo := repo.NewMentionRepo(db)
+	watchRepo := repo.NewWatchRepo(db)
+	reportRepo := repo.NewReportRepo(db)
+	auditRepo := repo.NewAuditRepo(db)
+	relationRepo := repo.NewRelationRepo(db)
+	chatClient := client.NewChatClient(cfg)
 
-	postUseCase := usecase.NewPostUseCase(postRepo)
-	commentUseCase := usecase.NewCommentUseCase(commentRepo, postRepo)
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		log.Fatalf("failed to create mailer: %v", err)
+	}
+
+	contentFilter, err := contentfilter.NewReloader(cfg.ContentFilter, authClient)
+	if err != nil {
+		log.Fatalf("failed to load content filter: %v", err)
+	}
+
+	postUseCase := usecase.NewPostUseCase(postRepo, mentionRepo, authClient, contentFilter, relationRepo)
+	commentUseCase := usecase.NewCommentUseCase(commentRepo, postRepo, mentionRepo, authClient, contentFilter,
+		relationRepo)
+	profileUseCase := usecase.NewProfileUseCase(authClient, postRepo, commentRepo)
+	userDataUseCase := usecase.NewUserDataUseCase(postRepo, commentRepo, watchRepo, relationRepo,
+		notificationRepo, mentionRepo, cfg.Accounts)
+	notificationUseCase := usecase.NewNotificationUseCase(notificationRepo, postRepo, commentRepo, relationRepo)
+	watchUseCase := usecase.NewWatchUseCase(watchRepo, postRepo)
+	digestUseCase := usecase.NewDigestUseCase(watchRepo, authClient, mail, cfg.Digest.BaseURL)
+	reportUseCase := usecase.NewReportUseCase(reportRepo, postRepo, commentRepo, chatClient, authClient,
+		notificationUseCase)
+	relationUseCase := usecase.NewRelationUseCase(relationRepo, authClient)
+	auditUseCase := usecase.NewAuditUseCase(auditRepo)
+
+	bus, err := events.NewBus(cfg.Events)
+	if err != nil {
+		log.Fatalf("failed to create event bus: %v", err)
+	}
+	defer bus.Close()
+
+	subscriber.Register(bus, userDataUseCase, notificationUseCase, reportUseCase, authClient)
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go events.NewRelay(db, bus, "forum", cfg.Events).Run(relayCtx)
+	go contentFilter.Watch(relayCtx)
+
+	rateLimitStore, err := ratelimit.NewStore(cfg.RateLimit, db)
+	if err != nil {
+		log.Fatalf("failed to create rate limit store: %v", err)
+	}
+	limiter, err := ratelimit.NewLimiter(cfg.RateLimit, rateLimitStore)
+	if err != nil {
+		log.Fatalf("failed to create rate limiter: %v", err)
+	}
+	go limiter.Run(relayCtx)
+
+	if cfg.Digest.Interval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.Digest.Interval)
+			for range ticker.C {
+				if err := digestUseCase.SendDigests(context.Background()); err != nil {
+					log.Printf("Failed to send digests: %v", err)
+				}
+			}
+		}()
+	}
 
 	authMiddleware := middleware.AuthMiddleware(authClient)
+	optionalAuthMiddleware := middleware.OptionalAuth(authClient)
+	verifiedMiddleware := middleware.RequireVerifiedEmail(cfg.Restrictions.UnverifiedReadOnly)
+	staffMiddleware := middleware.RequireStaff()
+	internalMiddleware := middleware.InternalToken(cfg.Internal.Token)
+	rateLimit := middleware.RateLimit(limiter)
 
-	r := router.NewRouter(postUseCase, commentUseCase, authMiddleware)
+	r := router.NewRouter(postUseCase, commentUseCase, profileUseCase, userDataUseCase, notificationUseCase,
+		watchUseCase, reportUseCase, relationUseCase, auditUseCase, authMiddleware, optionalAuthMiddleware,
+		verifiedMiddleware, staffMiddleware, internalMiddleware, rateLimit, cfg.RateLimit.TrustedProxies)
+	// Метрики клиента auth-service - только для своих сервисов и мониторинга
+	r.GET("/internal/debug/auth-client", internalMiddleware, func(c *gin.Context) {
+		c.JSON(http.StatusOK, authClient.Metrics())
+	})
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
@@ -8,6 +8,7 @@ import (
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"go-forum-project/forum-service/internal/config"
+	"go-forum-project/migrator"
 	"log"
 )
 
@@ -16,12 +17,15 @@ func main() {
 		action     string
 		steps      int
 		configPath string
+		authDBName string
 	)
 
 	flag.StringVar(&action, "action", "up", "Миграция: up, down, force, version")
 	flag.IntVar(&steps, "steps", 0, "Количество шагов (для up/down)")
 	flag.StringVar(&configPath, "config", "forum-service/internal/config/config.yaml",
 		"Путь к конфигурационному файлу")
+	flag.StringVar(&authDBName, "auth-db", "auth_db",
+		"База auth-service на том же сервере, по ней восстанавливаются авторы старых записей")
 	flag.Parse()
 
 	cfg, err := config.LoadConfig(configPath)
@@ -47,11 +51,11 @@ func main() {
 
 	switch action {
 	case "up":
-		if steps > 0 {
-			err = m.Steps(steps)
-		} else {
-			err = m.Up()
-		}
+		authDB := cfg.Database
+		authDB.Name = authDBName
+		err = migrator.Up(m, steps, map[uint]migrator.Backfill{
+			4: migrator.AuthorBackfill(db, authDB.GetConnectionString(), "posts", "comments"),
+		})
 	case "down":
 		if steps > 0 {
 			err = m.Steps(-steps)
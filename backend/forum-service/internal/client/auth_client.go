@@ -9,12 +9,40 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"log"
+	"slices"
+	"strings"
 	"time"
 )
 
+// Персональные токены не являются JWT и всегда проверяются через auth-service
+const personalTokenPrefix = "gfp_"
+
+// TokenInfo - данные пользователя, извлечённые из access токена.
+type TokenInfo struct {
+	UserID        int
+	Username      string
+	EmailVerified bool
+	// Role - user, moderator или admin
+	Role string
+	// Scopes задан только для персональных токенов
+	Scopes []string
+}
+
+// HasScope сообщает, разрешено ли действие. Токен сессии разрешает всё.
+func (i *TokenInfo) HasScope(scope string) bool {
+	return len(i.Scopes) == 0 || slices.Contains(i.Scopes, scope)
+}
+
 type AuthClient struct {
-	conn   *grpc.ClientConn
-	client pb.AuthServiceClient
+	conn    *grpc.ClientConn
+	client  pb.AuthServiceClient
+	keys    *keyStore
+	cache   *tokenCache
+	bans    *banList
+	metrics *Metrics
+	cancel  context.CancelFunc
+	// internalToken - общий секрет для служебных вызовов auth-service
+	internalToken string
 }
 
 func NewAuthClient(ctx context.Context, cfg *config.Config) (*AuthClient, error) {
@@ -29,39 +57,122 @@ func NewAuthClient(ctx context.Context, cfg *config.Config) (*AuthClient, error)
 		return nil, fmt.Errorf("failed to connect to auth service: %v", err)
 	}
 
-	return &AuthClient{
-		conn:   conn,
-		client: pb.NewAuthServiceClient(conn),
-	}, nil
+	authClient := &AuthClient{
+		conn:    conn,
+		client:  pb.NewAuthServiceClient(conn),
+		bans:    newBanList(),
+		metrics: &Metrics{},
+		cancel:  func() {},
+
+		internalToken: cfg.Internal.Token,
+	}
+
+	if cfg.AuthService.CacheSize > 0 && cfg.AuthService.CacheTTL > 0 {
+		authClient.cache = newTokenCache(cfg.AuthService.CacheSize, cfg.AuthService.CacheTTL, authClient.metrics)
+	}
+
+	if cfg.AuthService.LocalVerification {
+		refreshInterval := cfg.AuthService.KeysRefreshInterval
+		if refreshInterval <= 0 {
+			refreshInterval = 10 * time.Minute
+		}
+
+		var refreshCtx context.Context
+		refreshCtx, authClient.cancel = context.WithCancel(context.Background())
+		authClient.keys = newKeyStore(authClient.client, refreshInterval)
+		go authClient.keys.RunRefresh(refreshCtx)
+	}
+
+	return authClient, nil
 }
 
 func (c *AuthClient) GetUsername(ctx context.Context, token string) (string, error) {
-	resp, err := c.client.ValidateToken(ctx, &pb.ValidateTokenRequest{
-		AccessToken: token,
-	})
+	info, valid, err := c.ValidateToken(ctx, token)
 	if err != nil {
 		return "", err
 	}
-	if !resp.Valid {
+	if !valid {
 		return "", errors.New("invalid token")
 	}
-	return resp.Username, nil
+	return info.Username, nil
 }
 
 func (c *AuthClient) Close() {
+	c.cancel()
 	if err := c.conn.Close(); err != nil {
 		log.Fatalf("failed to close auth client connection: %v", err)
 	}
 }
 
-func (c *AuthClient) ValidateToken(ctx context.Context, token string) (string, bool, error) {
+// ValidateToken сначала смотрит в кэш, затем пробует проверить токен
+// опубликованными ключами и только если ключ неизвестен идёт в auth-service.
+// Токены заблокированных пользователей считаются недействительными.
+func (c *AuthClient) ValidateToken(ctx context.Context, token string) (*TokenInfo, bool, error) {
+	info, valid, err := c.validateToken(ctx, token)
+	if err != nil || !valid {
+		return info, valid, err
+	}
+	if c.bans.IsBanned(info.UserID) {
+		return nil, false, nil
+	}
+	return info, true, nil
+}
+
+func (c *AuthClient) validateToken(ctx context.Context, token string) (*TokenInfo, bool, error) {
+	if c.cache != nil {
+		if info, ok := c.cache.Get(token); ok {
+			return info, true, nil
+		}
+	}
+
+	if c.keys != nil && !strings.HasPrefix(token, personalTokenPrefix) {
+		info, expiresAt, err := c.keys.Verify(ctx, token)
+		switch {
+		case err == nil:
+			c.metrics.localValidations.Add(1)
+			if c.cache != nil {
+				c.cache.Set(token, info, expiresAt)
+			}
+			return info, true, nil
+		case errors.Is(err, errUnknownKey):
+			c.metrics.unknownKeys.Add(1)
+		default:
+			c.metrics.localValidations.Add(1)
+			return nil, false, fmt.Errorf("validate token error: %w", err)
+		}
+	}
+
+	c.metrics.remoteValidations.Add(1)
 	resp, err := c.client.ValidateToken(ctx, &pb.ValidateTokenRequest{
 		AccessToken: token,
 	})
 	if err != nil {
-		return "", false, fmt.Errorf("validate token error: %w", err)
+		return nil, false, fmt.Errorf("validate token error: %w", err)
 	}
-	return resp.Username, resp.Valid, nil
+
+	info := &TokenInfo{
+		UserID:        int(resp.UserId),
+		Username:      resp.Username,
+		EmailVerified: resp.EmailVerified,
+		Role:          resp.Role,
+		Scopes:        resp.Scopes,
+	}
+
+	if resp.Valid && c.cache != nil {
+		// Бессрочный персональный токен живёт в кэше не дольше его TTL,
+		// поэтому отзыв вступает в силу через CacheTTL
+		var expiresAt time.Time
+		if resp.ExpiresAt != 0 {
+			expiresAt = time.Unix(resp.ExpiresAt, 0)
+		}
+		c.cache.Set(token, info, expiresAt)
+	}
+
+	return info, resp.Valid, nil
+}
+
+func (c *AuthClient) Metrics() MetricsSnapshot {
+	return c.metrics.Snapshot()
 }
 
 func (c *AuthClient) Refresh(ctx context.Context, refreshToken string) (*pb.TokenResponse, error) {
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-forum-project/forum-service/internal/config"
+)
+
+var ErrMessageNotFound = errors.New("message not found")
+
+// ChatMessage - сообщение чата в том виде, в каком его отдаёт chat-service.
+type ChatMessage struct {
+	ID       int
+	Author   string
+	AuthorID int
+	Text     string
+}
+
+// ChatClient ходит в служебный API chat-service: /internal/messages/{messageId}.
+type ChatClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewChatClient(cfg *config.Config) *ChatClient {
+	return &ChatClient{
+		baseURL: strings.TrimRight(cfg.ChatService.URL, "/"),
+		token:   cfg.Internal.Token,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *ChatClient) GetMessage(ctx context.Context, id int) (*ChatMessage, error) {
+	resp, err := c.do(ctx, http.MethodGet, messagePath(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var message ChatMessage
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		return nil, fmt.Errorf("chat-service: failed to decode message: %w", err)
+	}
+	return &message, nil
+}
+
+// RemoveMessage удаляет сообщение от имени модератора. chat-service сам
+// записывает удаление в журнал аудита.
+func (c *ChatClient) RemoveMessage(ctx context.Context, id, actorID int, actorRole, reason string) error {
+	query := url.Values{}
+	query.Set("actor_id", strconv.Itoa(actorID))
+	query.Set("actor_role", actorRole)
+	query.Set("reason", reason)
+
+	resp, err := c.do(ctx, http.MethodDelete, messagePath(id), query)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ReleaseMessage публикует сообщение, скрытое фильтром контента.
+func (c *ChatClient) ReleaseMessage(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, http.MethodPost, messagePath(id)+"/release", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func messagePath(id int) string {
+	return fmt.Sprintf("/internal/messages/%d", id)
+}
+
+func (c *ChatClient) do(ctx context.Context, method, path string, query url.Values) (*http.Response, error) {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Internal-Token", c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("chat-service: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrMessageNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("chat-service: unexpected status %s", resp.Status)
+	}
+	return resp, nil
+}
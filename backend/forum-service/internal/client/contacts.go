@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"go-forum-project/forum-service/internal/entity"
+	pb "go-forum-project/proto/gRPC"
+	"google.golang.org/grpc/metadata"
+)
+
+// GetUserContacts запрашивает адреса пользователей для рассылок. Удалённые
+// пользователи в ответ не попадают.
+func (c *AuthClient) GetUserContacts(ctx context.Context, userIDs []int) ([]entity.Contact, error) {
+	ids := make([]int64, 0, len(userIDs))
+	for _, id := range userIDs {
+		ids = append(ids, int64(id))
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-internal-token", c.internalToken)
+	resp, err := c.client.GetUserContacts(ctx, &pb.GetUserContactsRequest{UserIds: ids})
+	if err != nil {
+		return nil, fmt.Errorf("get user contacts error: %w", err)
+	}
+
+	contacts := make([]entity.Contact, 0, len(resp.Contacts))
+	for _, contact := range resp.Contacts {
+		contacts = append(contacts, entity.Contact{
+			UserID:        int(contact.UserId),
+			Username:      contact.Username,
+			Email:         contact.Email,
+			EmailVerified: contact.EmailVerified,
+		})
+	}
+	return contacts, nil
+}
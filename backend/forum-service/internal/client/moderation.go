@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "go-forum-project/proto/gRPC"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrPermissionDenied = errors.New("permission denied by auth service")
+	ErrBanRejected      = errors.New("ban rejected by auth service")
+)
+
+// AdminBanUser банит пользователя в auth-service от имени модератора.
+// Права проверяет auth-service по переданному access токену.
+func (c *AuthClient) AdminBanUser(ctx context.Context, accessToken string, userID int, reason string,
+	duration time.Duration) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+accessToken)
+	_, err := c.client.AdminBanUser(ctx, &pb.AdminBanUserRequest{
+		UserId:          int64(userID),
+		Reason:          reason,
+		DurationSeconds: int64(duration / time.Second),
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.PermissionDenied, codes.Unauthenticated:
+			return ErrPermissionDenied
+		case codes.InvalidArgument, codes.FailedPrecondition, codes.NotFound:
+			return fmt.Errorf("%w: %s", ErrBanRejected, status.Convert(err).Message())
+		}
+		return fmt.Errorf("ban user error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-forum-project/forum-service/internal/entity"
+	pb "go-forum-project/proto/gRPC"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrProfileNotFound = errors.New("profile not found")
+
+func (c *AuthClient) GetProfile(ctx context.Context, username string) (*entity.Profile, error) {
+	resp, err := c.client.GetProfile(ctx, &pb.GetProfileRequest{Username: username})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrProfileNotFound
+		}
+		return nil, fmt.Errorf("get profile error: %w", err)
+	}
+
+	return &entity.Profile{
+		UserID:      int(resp.UserId),
+		Username:    resp.Username,
+		DisplayName: resp.DisplayName,
+		Bio:         resp.Bio,
+		AvatarURL:   resp.AvatarUrl,
+		Location:    resp.Location,
+		JoinedAt:    time.Unix(resp.JoinedAt, 0),
+	}, nil
+}
+
+// JoinedAt - дата регистрации для фильтра контента.
+func (c *AuthClient) JoinedAt(ctx context.Context, username string) (time.Time, error) {
+	profile, err := c.GetProfile(ctx, username)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return profile.JoinedAt, nil
+}
@@ -2,18 +2,76 @@ package config
 
 import (
 	"fmt"
+	"go-forum-project/contentfilter"
+	"go-forum-project/events"
+	"go-forum-project/mailer"
+	"go-forum-project/ratelimit"
 	"gopkg.in/yaml.v3"
 	"os"
+	"time"
 )
 
 type Config struct {
-	AuthService AuthServiceConfig `yaml:"auth_service"`
-	Server      ServerConfig      `yaml:"server"`
-	Database    DatabaseConfig    `yaml:"database"`
+	AuthService  AuthServiceConfig  `yaml:"auth_service"`
+	ChatService  ChatServiceConfig  `yaml:"chat_service"`
+	Server       ServerConfig       `yaml:"server"`
+	Database     DatabaseConfig     `yaml:"database"`
+	Restrictions RestrictionsConfig `yaml:"restrictions"`
+	Internal     InternalConfig     `yaml:"internal"`
+	Accounts     AccountsConfig     `yaml:"accounts"`
+	Events       events.Config      `yaml:"events"`
+	Mail         mailer.Config      `yaml:"mail"`
+	Digest       DigestConfig       `yaml:"digest"`
+	// ContentFilter - проверка постов и комментариев перед публикацией
+	ContentFilter contentfilter.Config `yaml:"content_filter"`
+	// RateLimit - ограничение частоты записи
+	RateLimit ratelimit.Config `yaml:"rate_limit"`
+}
+
+// DigestConfig - рассылка новой активности в отслеживаемых постах.
+type DigestConfig struct {
+	// Interval - как часто проверять, кому пора отправить дайджест. 0 отключает рассылку
+	Interval time.Duration `yaml:"interval"`
+	// BaseURL - адрес фронтенда для ссылок на посты
+	BaseURL string `yaml:"base_url"`
+}
+
+// InternalConfig - служебный API для других сервисов (/internal).
+// Пустой Token отключает его.
+type InternalConfig struct {
+	Token string `yaml:"token"`
+}
+
+const (
+	DeletedContentAnonymize = "anonymize"
+	DeletedContentRemove    = "remove"
+)
+
+type AccountsConfig struct {
+	// DeletedContent - что делать с постами и комментариями удалённого
+	// пользователя: anonymize или remove
+	DeletedContent string `yaml:"deleted_content"`
+	// DeletedAuthor - автор, на которого переписывается контент при anonymize
+	DeletedAuthor string `yaml:"deleted_author"`
+}
+
+type RestrictionsConfig struct {
+	// UnverifiedReadOnly - пользователи без подтверждённого email могут только читать
+	UnverifiedReadOnly bool `yaml:"unverified_read_only"`
 }
 
 type AuthServiceConfig struct {
-	Address string `yaml:"address"`
+	Address             string        `yaml:"address"`
+	LocalVerification   bool          `yaml:"local_verification"`
+	KeysRefreshInterval time.Duration `yaml:"keys_refresh_interval"`
+	CacheSize           int           `yaml:"cache_size"`
+	CacheTTL            time.Duration `yaml:"cache_ttl"`
+}
+
+// ChatServiceConfig - служебный API chat-service для модерации сообщений.
+// Используется общий секрет из Internal.Token.
+type ChatServiceConfig struct {
+	URL string `yaml:"url"`
 }
 
 type ServerConfig struct {
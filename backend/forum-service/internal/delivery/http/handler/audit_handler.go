@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go-forum-project/forum-service/internal/usecase"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type AuditHandler struct {
+	auditUC usecase.AuditUseCase
+}
+
+func NewAuditHandler(auditUC usecase.AuditUseCase) *AuditHandler {
+	return &AuditHandler{auditUC: auditUC}
+}
+
+// ListAuditEntries отдаёт записи журнала с id больше after.
+func (h *AuditHandler) ListAuditEntries(c *gin.Context) {
+	afterID, err := strconv.ParseInt(c.DefaultQuery("after", "0"), 10, 64)
+	if err != nil || afterID < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid after"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	entries, err := h.auditUC.ListAfter(c.Request.Context(), afterID, limit)
+	if err != nil {
+		log.Printf("Failed to list audit entries: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list audit entries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
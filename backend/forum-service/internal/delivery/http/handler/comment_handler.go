@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go-forum-project/forum-service/internal/usecase"
 	"log"
@@ -25,7 +26,8 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	}
 
 	var req struct {
-		Content string `json:"content" binding:"required"`
+		Content  string `json:"content" binding:"required"`
+		ParentID int    `json:"parent_id"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Printf("Bad request: %v", err)
@@ -41,9 +43,28 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 	}
 
 	log.Printf("Creating comment for post %d by user %s", postID, username.(string))
-	err = h.commentUC.Create(c.Request.Context(), postID, req.Content, username.(string))
+	err = h.commentUC.Create(c.Request.Context(), postID, req.ParentID, req.Content, username.(string),
+		c.GetInt("user_id"))
 	if err != nil {
 		log.Printf("Error creating comment: %v", err)
+		switch {
+		case errors.Is(err, usecase.ErrInvalidParent):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, usecase.ErrPostNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, usecase.ErrPostLocked), errors.Is(err, usecase.ErrPostArchived),
+			errors.Is(err, usecase.ErrBlockedByUser):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, usecase.ErrContentHeld):
+			c.JSON(http.StatusAccepted, gin.H{"message": "comment will be published after moderator review"})
+			return
+		case errors.Is(err, usecase.ErrContentRejected):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   err.Error(),
 			"details": "failed to create comment",
@@ -62,7 +83,7 @@ func (h *CommentHandler) GetCommentsByPostID(c *gin.Context) {
 		return
 	}
 
-	comments, err := h.commentUC.GetByPostID(c.Request.Context(), postID)
+	comments, err := h.commentUC.GetByPostID(c.Request.Context(), postID, c.GetInt("user_id"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -80,15 +101,22 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 		return
 	}
 
-	username, exists := c.Get("username")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
 
-	err = h.commentUC.DeleteComment(c.Request.Context(), commentID, username.(string))
+	err = h.commentUC.DeleteComment(c.Request.Context(), commentID, userID.(int))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, usecase.ErrCommentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrNotCommentOwner), errors.Is(err, usecase.ErrPostArchived):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/usecase"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type NotificationHandler struct {
+	notificationUC usecase.NotificationUseCase
+}
+
+func NewNotificationHandler(notificationUC usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{notificationUC: notificationUC}
+}
+
+// GetNotifications - GET /api/notifications?unread=true&page=1&page_size=20
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size"))
+
+	notifications, unread, err := h.notificationUC.List(c.Request.Context(), c.GetInt("user_id"), unreadOnly,
+		page, pageSize)
+	if err != nil {
+		log.Printf("Failed to list notifications: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"unread_count":  unread,
+	})
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("notificationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification id"})
+		return
+	}
+
+	err = h.notificationUC.MarkRead(c.Request.Context(), c.GetInt("user_id"), id)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to mark notification read: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mark notification read"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	if err := h.notificationUC.MarkAllRead(c.Request.Context(), c.GetInt("user_id")); err != nil {
+		log.Printf("Failed to mark notifications read: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to mark notifications read"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	prefs, err := h.notificationUC.GetPreferences(c.Request.Context(), c.GetInt("user_id"))
+	if err != nil {
+		log.Printf("Failed to get notification preferences: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"preferences": prefs})
+}
+
+// UpdatePreferences меняет только переданные поля.
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req struct {
+		CommentOnPost *bool   `json:"comment_on_post"`
+		CommentReply  *bool   `json:"comment_reply"`
+		Mention       *bool   `json:"mention"`
+		Digest        *string `json:"digest"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefs, err := h.notificationUC.UpdatePreferences(c.Request.Context(), c.GetInt("user_id"),
+		entity.NotificationPreferencesUpdate{
+			CommentOnPost: req.CommentOnPost,
+			CommentReply:  req.CommentReply,
+			Mention:       req.Mention,
+			Digest:        req.Digest,
+		})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidDigest) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to update notification preferences: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"preferences": prefs})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go-forum-project/forum-service/internal/usecase"
 	"net/http"
@@ -32,9 +33,17 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
-	err := h.postUC.CreatePost(c.Request.Context(), request.Title, request.Content, author.(string))
+	err := h.postUC.CreatePost(c.Request.Context(), request.Title, request.Content, author.(string),
+		c.GetInt("user_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, usecase.ErrContentHeld):
+			c.JSON(http.StatusAccepted, gin.H{"message": "post will be published after moderator review"})
+		case errors.Is(err, usecase.ErrContentRejected):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -42,7 +51,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 }
 
 func (h *PostHandler) GetAllPosts(c *gin.Context) {
-	posts, err := h.postUC.GetAllPosts(c.Request.Context())
+	posts, err := h.postUC.GetAllPosts(c.Request.Context(), c.GetInt("user_id"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -52,7 +61,7 @@ func (h *PostHandler) GetAllPosts(c *gin.Context) {
 }
 
 func (h *PostHandler) DeletePost(c *gin.Context) {
-	username, exists := c.Get("username")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authorized"})
 		return
@@ -70,13 +79,19 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	if post.Author != username.(string) {
+	// Посты без author_id - старые записи, автора которых миграция не нашла,
+	// их удаляют только модераторы через жалобы
+	if post.AuthorID == 0 || post.AuthorID != userID.(int) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "you can only delete your own posts"})
 		return
 	}
 
 	err = h.postUC.DeletePost(c.Request.Context(), postID)
 	if err != nil {
+		if errors.Is(err, usecase.ErrPostArchived) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -85,7 +100,7 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 }
 
 func (h *PostHandler) UpdatePost(c *gin.Context) {
-	username, exists := c.Get("username")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authorized"})
 		return
@@ -103,7 +118,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	if post.Author != username.(string) {
+	if post.AuthorID == 0 || post.AuthorID != userID.(int) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "you can only edit your own posts"})
 		return
 	}
@@ -120,7 +135,14 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 
 	err = h.postUC.UpdatePost(c.Request.Context(), postID, request.Title, request.Content)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, usecase.ErrPostArchived):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrContentRejected):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -142,3 +164,31 @@ func (h *PostHandler) GetPostByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, post)
 }
+
+// SetPostFlag возвращает обработчик, который ставит или снимает флаг
+// модерации поста: POST - ставит, DELETE - снимает.
+func (h *PostHandler) SetPostFlag(flag string, value bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		postID, err := strconv.Atoi(c.Param("postId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+			return
+		}
+
+		post, err := h.postUC.SetFlag(c.Request.Context(), c.GetInt("user_id"), c.GetString("role"), postID,
+			flag, value)
+		if err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrPostNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, usecase.ErrInvalidPostFlag):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update post"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, post)
+	}
+}
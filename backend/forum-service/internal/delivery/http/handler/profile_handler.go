@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-forum-project/forum-service/internal/usecase"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+type ProfileHandler struct {
+	profileUC usecase.ProfileUseCase
+}
+
+func NewProfileHandler(profileUC usecase.ProfileUseCase) *ProfileHandler {
+	return &ProfileHandler{profileUC: profileUC}
+}
+
+func (h *ProfileHandler) GetUserProfile(c *gin.Context) {
+	page, err := h.profileUC.GetUserPage(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		// Старое имя может со временем занять другой пользователь,
+		// поэтому редирект временный
+		var renamed *usecase.RenamedError
+		if errors.As(err, &renamed) {
+			c.Redirect(http.StatusFound, "/api/users/"+url.PathEscape(renamed.Username))
+			return
+		}
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		log.Printf("Failed to get user profile: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profile":         page.Profile,
+		"post_count":      page.PostCount,
+		"comment_count":   page.CommentCount,
+		"recent_activity": page.RecentActivity,
+	})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go-forum-project/forum-service/internal/usecase"
+)
+
+type RelationHandler struct {
+	relationUC usecase.RelationUseCase
+}
+
+func NewRelationHandler(relationUC usecase.RelationUseCase) *RelationHandler {
+	return &RelationHandler{relationUC: relationUC}
+}
+
+func (h *RelationHandler) GetRelations(c *gin.Context) {
+	relations, err := h.relationUC.List(c.Request.Context(), c.GetInt("user_id"))
+	if err != nil {
+		log.Printf("Failed to list relations: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list relations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"relations": relations})
+}
+
+// SetRelation возвращает обработчик, который блокирует или заглушает
+// пользователя из пути: kind - block или mute.
+func (h *RelationHandler) SetRelation(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		relation, err := h.relationUC.Set(c.Request.Context(), c.GetInt("user_id"), c.Param("username"), kind)
+		if err != nil {
+			h.respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, relation)
+	}
+}
+
+// RemoveRelation возвращает обработчик, который снимает блокировку или заглушение.
+func (h *RelationHandler) RemoveRelation(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := h.relationUC.Remove(c.Request.Context(), c.GetInt("user_id"), c.Param("username"), kind)
+		if err != nil {
+			h.respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "relation removed"})
+	}
+}
+
+func (h *RelationHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound), errors.Is(err, usecase.ErrRelationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrRelationSelf), errors.Is(err, usecase.ErrInvalidRelation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Failed to update relation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update relation"})
+	}
+}
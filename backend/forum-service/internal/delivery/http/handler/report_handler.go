@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-forum-project/forum-service/internal/client"
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/usecase"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ReportHandler struct {
+	reportUC usecase.ReportUseCase
+}
+
+func NewReportHandler(reportUC usecase.ReportUseCase) *ReportHandler {
+	return &ReportHandler{reportUC: reportUC}
+}
+
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	var req struct {
+		TargetType string `json:"target_type" binding:"required"`
+		TargetID   int    `json:"target_id" binding:"required"`
+		Reason     string `json:"reason" binding:"required"`
+		Details    string `json:"details"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.reportUC.Create(c.Request.Context(), c.GetInt("user_id"), req.TargetType, req.TargetID,
+		req.Reason, req.Details)
+	if err != nil {
+		reportError(c, err, "failed to create report")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"report": report})
+}
+
+// GetReports - очередь модерации. По умолчанию показывает открытые жалобы,
+// status=all снимает фильтр.
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	filter := entity.ReportFilter{
+		Status:     c.DefaultQuery("status", entity.ReportStatusOpen),
+		TargetType: c.Query("target_type"),
+	}
+	if filter.Status == "all" {
+		filter.Status = ""
+	}
+	filter.TargetID, _ = strconv.Atoi(c.Query("target_id"))
+	filter.ReporterID, _ = strconv.Atoi(c.Query("reporter_id"))
+	filter.Page, _ = strconv.Atoi(c.Query("page"))
+	filter.PageSize, _ = strconv.Atoi(c.Query("page_size"))
+
+	reports, total, err := h.reportUC.List(c.Request.Context(), filter)
+	if err != nil {
+		reportError(c, err, "failed to list reports")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports, "total": total})
+}
+
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	reportID, err := strconv.Atoi(c.Param("reportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	report, err := h.reportUC.Get(c.Request.Context(), reportID)
+	if err != nil {
+		reportError(c, err, "failed to get report")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	reportID, err := strconv.Atoi(c.Param("reportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+		return
+	}
+
+	var req struct {
+		Action string `json:"action" binding:"required"`
+		Note   string `json:"note"`
+		// BanDurationSeconds - срок бана для action=ban, 0 - бессрочно
+		BanDurationSeconds int64 `json:"ban_duration_seconds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.BanDurationSeconds < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ban_duration_seconds must not be negative"})
+		return
+	}
+
+	report, err := h.reportUC.Resolve(c.Request.Context(), c.GetInt("user_id"), c.GetString("role"),
+		c.GetString("access_token"), reportID, entity.ReportResolution{
+			Action:      req.Action,
+			Note:        req.Note,
+			BanDuration: time.Duration(req.BanDurationSeconds) * time.Second,
+		})
+	if err != nil {
+		reportError(c, err, "failed to resolve report")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+func reportError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidReportTarget), errors.Is(err, usecase.ErrInvalidReportReason),
+		errors.Is(err, usecase.ErrReportDetailsLength), errors.Is(err, usecase.ErrCannotReportOwn),
+		errors.Is(err, usecase.ErrInvalidReportAction), errors.Is(err, usecase.ErrReportNoteLength),
+		errors.Is(err, usecase.ErrTargetAuthorUnknown), errors.Is(err, client.ErrBanRejected):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrReportTargetNotFound), errors.Is(err, usecase.ErrReportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrAlreadyReported), errors.Is(err, usecase.ErrReportClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrBanForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go-forum-project/forum-service/internal/usecase"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type UserDataHandler struct {
+	userDataUC usecase.UserDataUseCase
+}
+
+func NewUserDataHandler(userDataUC usecase.UserDataUseCase) *UserDataHandler {
+	return &UserDataHandler{userDataUC: userDataUC}
+}
+
+func (h *UserDataHandler) ExportUserData(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	data, err := h.userDataUC.Export(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to export user data: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export user data"})
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *UserDataHandler) EraseUserData(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := h.userDataUC.Erase(c.Request.Context(), userID); err != nil {
+		log.Printf("Failed to erase user data: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to erase user data"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-forum-project/forum-service/internal/usecase"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type WatchHandler struct {
+	watchUC usecase.WatchUseCase
+}
+
+func NewWatchHandler(watchUC usecase.WatchUseCase) *WatchHandler {
+	return &WatchHandler{watchUC: watchUC}
+}
+
+func (h *WatchHandler) WatchPost(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("postId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	if err := h.watchUC.Watch(c.Request.Context(), c.GetInt("user_id"), postID); err != nil {
+		if errors.Is(err, usecase.ErrPostNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Failed to watch post %d: %v", postID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to watch post"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "post watched"})
+}
+
+func (h *WatchHandler) UnwatchPost(c *gin.Context) {
+	postID, err := strconv.Atoi(c.Param("postId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	if err := h.watchUC.Unwatch(c.Request.Context(), c.GetInt("user_id"), postID); err != nil {
+		log.Printf("Failed to unwatch post %d: %v", postID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unwatch post"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "post unwatched"})
+}
+
+func (h *WatchHandler) GetWatches(c *gin.Context) {
+	watches, err := h.watchUC.List(c.Request.Context(), c.GetInt("user_id"))
+	if err != nil {
+		log.Printf("Failed to list watches: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list watches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"watches": watches})
+}
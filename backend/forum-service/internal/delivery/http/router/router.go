@@ -1,15 +1,30 @@
 package router
 
 import (
+	"log"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"go-forum-project/forum-service/internal/delivery/http/handler"
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/middleware"
 	"go-forum-project/forum-service/internal/usecase"
 )
 
-func NewRouter(postUC usecase.PostUseCase, commentUC usecase.CommentUseCase, authMiddleware gin.HandlerFunc) *gin.Engine {
+func NewRouter(postUC usecase.PostUseCase, commentUC usecase.CommentUseCase, profileUC usecase.ProfileUseCase,
+	userDataUC usecase.UserDataUseCase, notificationUC usecase.NotificationUseCase, watchUC usecase.WatchUseCase,
+	reportUC usecase.ReportUseCase, relationUC usecase.RelationUseCase, auditUC usecase.AuditUseCase,
+	authMiddleware, optionalAuthMiddleware, verifiedMiddleware, staffMiddleware, internalMiddleware gin.HandlerFunc,
+	rateLimit func(action string) gin.HandlerFunc, trustedProxies []string) *gin.Engine {
 	router := gin.Default()
 
+	// IP клиента - адрес соединения, X-Forwarded-For читается только от
+	// доверенных прокси. Пустой список не доверяет никому
+	router.RemoteIPHeaders = []string{"X-Forwarded-For"}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("invalid trusted proxies: %v", err)
+	}
+
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
@@ -20,10 +35,20 @@ func NewRouter(postUC usecase.PostUseCase, commentUC usecase.CommentUseCase, aut
 
 	postHandler := handler.NewPostHandler(postUC)
 	commentHandler := handler.NewCommentHandler(commentUC)
+	profileHandler := handler.NewProfileHandler(profileUC)
+	userDataHandler := handler.NewUserDataHandler(userDataUC)
+	auditHandler := handler.NewAuditHandler(auditUC)
+	notificationHandler := handler.NewNotificationHandler(notificationUC)
+	watchHandler := handler.NewWatchHandler(watchUC)
+	reportHandler := handler.NewReportHandler(reportUC)
+	relationHandler := handler.NewRelationHandler(relationUC)
 
+	// Вошедшему пользователю списки показываются без заблокированных и заглушённых авторов
 	publicGroup := router.Group("/api")
+	publicGroup.Use(optionalAuthMiddleware)
 	{
 		publicGroup.GET("/posts", postHandler.GetAllPosts)
+		publicGroup.GET("/users/:username", profileHandler.GetUserProfile)
 
 		postGroup := publicGroup.Group("/posts/:postId")
 		{
@@ -35,12 +60,54 @@ func NewRouter(postUC usecase.PostUseCase, commentUC usecase.CommentUseCase, aut
 	authGroup := router.Group("/api")
 	authGroup.Use(authMiddleware)
 	{
-		authGroup.POST("/posts", postHandler.CreatePost)
-		authGroup.PUT("/posts/:postId", postHandler.UpdatePost)
+		authGroup.POST("/posts", rateLimit(middleware.ActionPostCreate), verifiedMiddleware, postHandler.CreatePost)
+		authGroup.PUT("/posts/:postId", rateLimit(middleware.ActionPostUpdate), postHandler.UpdatePost)
 		authGroup.DELETE("/posts/:postId", postHandler.DeletePost)
+		authGroup.POST("/posts/:postId/watch", watchHandler.WatchPost)
+		authGroup.DELETE("/posts/:postId/watch", watchHandler.UnwatchPost)
+		authGroup.GET("/watches", watchHandler.GetWatches)
 
-		authGroup.POST("/posts/:postId/comments", commentHandler.CreateComment)
+		authGroup.POST("/posts/:postId/comments", rateLimit(middleware.ActionCommentCreate), verifiedMiddleware,
+			commentHandler.CreateComment)
 		authGroup.DELETE("/comments/:commentId", commentHandler.DeleteComment) // Единственный маршрут для удаления
+
+		authGroup.GET("/notifications", notificationHandler.GetNotifications)
+		authGroup.POST("/notifications/:notificationId/read", notificationHandler.MarkRead)
+		authGroup.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+		authGroup.GET("/notifications/preferences", notificationHandler.GetPreferences)
+		authGroup.PUT("/notifications/preferences", notificationHandler.UpdatePreferences)
+
+		authGroup.GET("/relationships", relationHandler.GetRelations)
+		authGroup.POST("/users/:username/block", relationHandler.SetRelation(entity.RelationBlock))
+		authGroup.DELETE("/users/:username/block", relationHandler.RemoveRelation(entity.RelationBlock))
+		authGroup.POST("/users/:username/mute", relationHandler.SetRelation(entity.RelationMute))
+		authGroup.DELETE("/users/:username/mute", relationHandler.RemoveRelation(entity.RelationMute))
+
+		authGroup.POST("/reports", rateLimit(middleware.ActionReportCreate), reportHandler.CreateReport)
+
+		moderationGroup := authGroup.Group("/moderation")
+		moderationGroup.Use(staffMiddleware)
+		{
+			moderationGroup.GET("/reports", reportHandler.GetReports)
+			moderationGroup.GET("/reports/:reportId", reportHandler.GetReport)
+			moderationGroup.POST("/reports/:reportId/resolve", reportHandler.ResolveReport)
+
+			moderationGroup.POST("/posts/:postId/pin", postHandler.SetPostFlag(entity.PostFlagPinned, true))
+			moderationGroup.DELETE("/posts/:postId/pin", postHandler.SetPostFlag(entity.PostFlagPinned, false))
+			moderationGroup.POST("/posts/:postId/lock", postHandler.SetPostFlag(entity.PostFlagLocked, true))
+			moderationGroup.DELETE("/posts/:postId/lock", postHandler.SetPostFlag(entity.PostFlagLocked, false))
+			moderationGroup.POST("/posts/:postId/archive", postHandler.SetPostFlag(entity.PostFlagArchived, true))
+			moderationGroup.DELETE("/posts/:postId/archive", postHandler.SetPostFlag(entity.PostFlagArchived, false))
+		}
+	}
+
+	// Служебные маршруты для auth-service
+	internalGroup := router.Group("/internal")
+	internalGroup.Use(internalMiddleware)
+	{
+		internalGroup.GET("/users/:userId/data", userDataHandler.ExportUserData)
+		internalGroup.DELETE("/users/:userId/data", userDataHandler.EraseUserData)
+		internalGroup.GET("/audit", auditHandler.ListAuditEntries)
 	}
 
 	return router
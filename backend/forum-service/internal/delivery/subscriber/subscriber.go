@@ -0,0 +1,37 @@
+package subscriber
+
+import (
+	"context"
+
+	"go-forum-project/events"
+	"go-forum-project/forum-service/internal/client"
+	"go-forum-project/forum-service/internal/usecase"
+)
+
+// Register подписывает forum-service на события других сервисов.
+func Register(bus events.Bus, userDataUC usecase.UserDataUseCase, notificationUC usecase.NotificationUseCase,
+	reportUC usecase.ReportUseCase, authClient *client.AuthClient) {
+	events.Subscribe(bus, func(ctx context.Context, event events.UserRenamed) error {
+		return userDataUC.RenameAuthor(ctx, event.UserID, event.NewUsername)
+	})
+
+	events.Subscribe(bus, notificationUC.HandleCommentCreated)
+	events.Subscribe(bus, notificationUC.HandlePostCreated)
+	events.Subscribe(bus, notificationUC.HandleMessageCreated)
+	events.Subscribe(bus, reportUC.HandleContentHeld)
+
+	events.Subscribe(bus, func(ctx context.Context, event events.UserBanned) error {
+		authClient.BanUser(event.UserID, event.ExpiresAt)
+		return nil
+	})
+
+	events.Subscribe(bus, func(ctx context.Context, event events.UserUnbanned) error {
+		authClient.UnbanUser(event.UserID)
+		return nil
+	})
+
+	events.Subscribe(bus, func(ctx context.Context, event events.UserDeleted) error {
+		authClient.DeleteUser(event.UserID)
+		return nil
+	})
+}
@@ -5,7 +5,12 @@ import "time"
 type Comment struct {
 	ID        int
 	PostID    int
+	ParentID  int // 0 для комментария верхнего уровня
 	Content   string
 	Author    string
+	AuthorID  int
 	CreatedAt time.Time
+	Mentions  []Mention
+	// Held - комментарий скрыт фильтром контента до проверки модератором
+	Held bool
 }
@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+const (
+	NotificationCommentOnPost = "comment_on_post"
+	NotificationCommentReply  = "comment_reply"
+	NotificationMention       = "mention"
+	// NotificationWarning - предупреждение модератора, отключить нельзя
+	NotificationWarning = "warning"
+)
+
+// Частота дайджеста по отслеживаемым постам
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+type Notification struct {
+	ID     int
+	UserID int
+	Type   string
+	// ActorID - кто вызвал уведомление, 0 для системных
+	ActorID   int
+	Actor     string
+	PostID    int
+	CommentID int
+	// MessageID - сообщение чата для упоминаний в чате
+	MessageID int
+	Text      string
+	Read      bool
+	CreatedAt time.Time
+}
+
+// NotificationPreferences - какие уведомления пользователь хочет получать.
+// По умолчанию включены все, дайджест приходит раз в неделю.
+type NotificationPreferences struct {
+	CommentOnPost bool
+	CommentReply  bool
+	Mention       bool
+	Digest        string
+}
+
+func DefaultNotificationPreferences() NotificationPreferences {
+	return NotificationPreferences{CommentOnPost: true, CommentReply: true, Mention: true, Digest: DigestWeekly}
+}
+
+// Allows сообщает, включён ли тип уведомлений.
+func (p NotificationPreferences) Allows(notificationType string) bool {
+	switch notificationType {
+	case NotificationCommentOnPost:
+		return p.CommentOnPost
+	case NotificationCommentReply:
+		return p.CommentReply
+	case NotificationMention:
+		return p.Mention
+	default:
+		return true
+	}
+}
+
+// NotificationPreferencesUpdate - частичное обновление, nil поля не меняются.
+type NotificationPreferencesUpdate struct {
+	CommentOnPost *bool
+	CommentReply  *bool
+	Mention       *bool
+	Digest        *string
+}
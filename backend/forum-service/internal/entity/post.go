@@ -2,11 +2,28 @@ package entity
 
 import "time"
 
+// Флаги поста, которые меняют модераторы
+const (
+	PostFlagPinned   = "pinned"
+	PostFlagLocked   = "locked"
+	PostFlagArchived = "archived"
+)
+
 type Post struct {
 	ID        int
 	Title     string
 	Content   string
 	Author    string
+	AuthorID  int // 0, если автор удалил аккаунт
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Mentions  []Mention
+
+	// Pinned - пост выше остальных в ленте, Locked - новые комментарии
+	// запрещены, Archived - пост и комментарии только для чтения
+	Pinned   bool
+	Locked   bool
+	Archived bool
+	// Held - пост скрыт фильтром контента до проверки модератором
+	Held bool
 }
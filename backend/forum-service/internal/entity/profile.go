@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// Profile - профиль пользователя из auth-service.
+type Profile struct {
+	UserID      int
+	Username    string
+	DisplayName string
+	Bio         string
+	AvatarURL   string
+	Location    string
+	JoinedAt    time.Time
+}
+
+// Activity - запись ленты последних действий пользователя.
+type Activity struct {
+	Type      string
+	PostID    int
+	CommentID int
+	Title     string
+	Excerpt   string
+	CreatedAt time.Time
+}
+
+const (
+	ActivityPost    = "post"
+	ActivityComment = "comment"
+)
+
+// UserPage - публичная страница пользователя.
+type UserPage struct {
+	Profile        *Profile
+	PostCount      int
+	CommentCount   int
+	RecentActivity []Activity
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// Виды отношений между пользователями. mute скрывает контент пользователя
+// и уведомления о его действиях, block вдобавок запрещает ему упоминать
+// заблокировавшего и отвечать ему.
+const (
+	RelationBlock = "block"
+	RelationMute  = "mute"
+)
+
+// Relation - пользователь, которого текущий пользователь заблокировал или заглушил.
+type Relation struct {
+	TargetID       int
+	TargetUsername string
+	Kind           string
+	CreatedAt      time.Time
+}
@@ -0,0 +1,72 @@
+package entity
+
+import "time"
+
+// На что можно пожаловаться
+const (
+	ReportTargetPost    = "post"
+	ReportTargetComment = "comment"
+	ReportTargetMessage = "message"
+)
+
+// Причины жалоб
+const (
+	ReportReasonSpam       = "spam"
+	ReportReasonAbuse      = "abuse"
+	ReportReasonHarassment = "harassment"
+	ReportReasonIllegal    = "illegal"
+	ReportReasonOther      = "other"
+	// ReportReasonFilter ставит фильтр контента, у таких жалоб нет автора
+	ReportReasonFilter = "content_filter"
+)
+
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// Решения модератора по жалобе
+const (
+	ReportActionDismiss = "dismiss"
+	ReportActionRemove  = "remove"
+	ReportActionWarn    = "warn"
+	ReportActionBan     = "ban"
+)
+
+// Report - жалоба на пост, комментарий или сообщение чата. Автор и текст
+// сохраняются на момент жалобы: контент могут изменить или удалить.
+type Report struct {
+	ID             int
+	TargetType     string
+	TargetID       int
+	TargetAuthorID int
+	TargetExcerpt  string
+	ReporterID     int // 0 для жалоб фильтра контента
+	Reason         string
+	Details        string
+	Status         string
+	// Action, ResolvedBy, Note и ResolvedAt заполнены после решения
+	Action     string
+	ResolvedBy int
+	Note       string
+	ResolvedAt time.Time
+	CreatedAt  time.Time
+}
+
+// ReportFilter - параметры очереди модерации, пустые поля не фильтруют.
+type ReportFilter struct {
+	Status     string
+	TargetType string
+	TargetID   int
+	ReporterID int
+	Page       int
+	PageSize   int
+}
+
+// ReportResolution - решение модератора. BanDuration 0 - бессрочный бан.
+type ReportResolution struct {
+	Action      string
+	Note        string
+	BanDuration time.Duration
+}
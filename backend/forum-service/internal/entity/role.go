@@ -0,0 +1,13 @@
+package entity
+
+// Роли пользователей из auth-service
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// IsStaff сообщает, может ли роль модерировать контент.
+func IsStaff(role string) bool {
+	return role == RoleModerator || role == RoleAdmin
+}
@@ -0,0 +1,14 @@
+package entity
+
+// UserData - всё, что forum-service хранит о пользователе, для выгрузки.
+type UserData struct {
+	UserID   int
+	Posts    []*Post
+	Comments []Comment
+	Watches  []Watch
+	// Relations - кого пользователь заблокировал или заглушил
+	Relations []Relation
+	// Notifications - уведомления, адресованные пользователю
+	Notifications           []Notification
+	NotificationPreferences NotificationPreferences
+}
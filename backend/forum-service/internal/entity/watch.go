@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// Watch - пост, за новой активностью в котором следит пользователь.
+type Watch struct {
+	PostID    int
+	Title     string
+	CreatedAt time.Time
+}
+
+// DigestItem - новая активность в одном отслеживаемом посте.
+type DigestItem struct {
+	PostID       int
+	Title        string
+	NewComments  int
+	LastActivity time.Time
+}
+
+// DigestRecipient - пользователь, которому пора отправить дайджест.
+// LastSentAt нулевой, если дайджест ещё не отправлялся.
+type DigestRecipient struct {
+	UserID     int
+	LastSentAt time.Time
+}
+
+// Contact - адрес пользователя из auth-service для рассылок.
+type Contact struct {
+	UserID        int
+	Username      string
+	Email         string
+	EmailVerified bool
+}
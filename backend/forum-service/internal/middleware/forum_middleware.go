@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"github.com/gin-gonic/gin"
 	"go-forum-project/forum-service/internal/client"
+	"go-forum-project/forum-service/internal/entity"
 	"net/http"
 	"strings"
 )
@@ -15,7 +17,7 @@ func AuthMiddleware(authClient *client.AuthClient) gin.HandlerFunc {
 			return
 		}
 
-		username, valid, err := authClient.ValidateToken(c.Request.Context(), accessToken)
+		info, valid, err := authClient.ValidateToken(c.Request.Context(), accessToken)
 		if err != nil {
 			refreshToken := c.GetHeader("X-Refresh-Token")
 			if refreshToken == "" {
@@ -33,19 +35,24 @@ func AuthMiddleware(authClient *client.AuthClient) gin.HandlerFunc {
 			c.Header("New-Access-Token", newTokens.AccessToken)
 			c.Header("New-Refresh-Token", newTokens.RefreshToken)
 
-			newUsername, _, validationErr := authClient.ValidateToken(c.Request.Context(), newTokens.AccessToken)
+			newInfo, _, validationErr := authClient.ValidateToken(c.Request.Context(), newTokens.AccessToken)
 			if validationErr != nil {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "New token validation failed"})
 				return
 			}
 
-			c.Set("username", newUsername)
+			setTokenInfo(c, newInfo, newTokens.AccessToken)
 			c.Next()
 			return
 		}
 
 		if valid {
-			c.Set("username", username)
+			if !info.HasScope(requiredScope(c.Request.Method)) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Token scope does not allow this action"})
+				return
+			}
+
+			setTokenInfo(c, info, accessToken)
 			c.Next()
 			return
 		}
@@ -65,13 +72,73 @@ func AuthMiddleware(authClient *client.AuthClient) gin.HandlerFunc {
 		c.Header("New-Access-Token", newTokens.AccessToken)
 		c.Header("New-Refresh-Token", newTokens.RefreshToken)
 
-		newUsername, _, err := authClient.ValidateToken(c.Request.Context(), newTokens.AccessToken)
+		newInfo, _, err := authClient.ValidateToken(c.Request.Context(), newTokens.AccessToken)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token validation failed"})
 			return
 		}
 
-		c.Set("username", newUsername)
+		setTokenInfo(c, newInfo, newTokens.AccessToken)
+		c.Next()
+	}
+}
+
+// OptionalAuth для публичных маршрутов: с действующим токеном кладёт данные
+// пользователя в контекст, без токена или с недействительным пропускает
+// запрос анонимно. Токены здесь не обновляются.
+func OptionalAuth(authClient *client.AuthClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accessToken := extractTokenFromHeader(c)
+		if accessToken != "" {
+			info, valid, err := authClient.ValidateToken(c.Request.Context(), accessToken)
+			if err == nil && valid {
+				setTokenInfo(c, info, accessToken)
+			}
+		}
+		c.Next()
+	}
+}
+
+// setTokenInfo кладёт данные пользователя в контекст. access_token нужен
+// обработчикам, которые действуют в auth-service от имени пользователя.
+func setTokenInfo(c *gin.Context, info *client.TokenInfo, accessToken string) {
+	c.Set("user_id", info.UserID)
+	c.Set("username", info.Username)
+	c.Set("email_verified", info.EmailVerified)
+	c.Set("role", info.Role)
+	c.Set("access_token", accessToken)
+}
+
+// requiredScope - право персонального токена, нужное для запроса:
+// чтение для безопасных методов, "post" для остальных.
+func requiredScope(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return "read"
+	default:
+		return "post"
+	}
+}
+
+// RequireVerifiedEmail не пускает пользователей с неподтверждённым email.
+// При enabled == false ничего не проверяет.
+func RequireVerifiedEmail(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if enabled && !c.GetBool("email_verified") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "email verification required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireStaff пускает только модераторов и администраторов.
+func RequireStaff() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !entity.IsStaff(c.GetString("role")) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "moderator role required"})
+			return
+		}
 		c.Next()
 	}
 }
@@ -89,3 +156,16 @@ func extractTokenFromHeader(c *gin.Context) string {
 
 	return tokenParts[1]
 }
+
+// InternalToken пускает только запросы других сервисов с общим секретом
+// в заголовке X-Internal-Token. Пустой token закрывает доступ полностью.
+func InternalToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader("X-Internal-Token")
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid internal token"})
+			return
+		}
+		c.Next()
+	}
+}
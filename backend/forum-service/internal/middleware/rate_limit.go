@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go-forum-project/ratelimit"
+)
+
+// Действия forum-service, для которых в конфиге задаются лимиты
+const (
+	ActionPostCreate    = "post_create"
+	ActionPostUpdate    = "post_update"
+	ActionCommentCreate = "comment_create"
+	ActionReportCreate  = "report_create"
+)
+
+// RateLimit возвращает фабрику middleware: на каждый маршрут свой action.
+// Ставится после AuthMiddleware, чтобы лимит считался на пользователя.
+// IP - c.ClientIP(), доверенные прокси задаются в router.NewRouter.
+func RateLimit(limiter *ratelimit.Limiter) func(action string) gin.HandlerFunc {
+	return func(action string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			allowed, retryAfter := limiter.Allow(c.Request.Context(), action, ratelimit.Subject{
+				UserID: c.GetInt("user_id"),
+				Role:   c.GetString("role"),
+				IP:     c.ClientIP(),
+			})
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				c.Header("Retry-After", strconv.Itoa(seconds))
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+					"error":       "too many requests, try again later",
+					"retry_after": seconds,
+				})
+				return
+			}
+			c.Next()
+		}
+	}
+}
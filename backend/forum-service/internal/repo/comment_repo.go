@@ -4,15 +4,28 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"go-forum-project/events"
 	"go-forum-project/forum-service/internal/entity"
 	"time"
 )
 
 type CommentRepository interface {
-	CreateComm(ctx context.Context, postId int, content, author string) error
+	// CreateComm с непустым holdReasons сохраняет комментарий скрытым до проверки модератором
+	CreateComm(ctx context.Context, postId, parentID int, content, author string, authorID int,
+		mentions []entity.Mention, holdReasons []string) error
 	GetByPostID(ctx context.Context, postID int) ([]entity.Comment, error)
 	GetCommentByID(ctx context.Context, id int) (entity.Comment, error)
-	Delete(ctx context.Context, postID int) error
+	// Delete удаляет комментарий. audit передаёт модератор, запись журнала
+	// сохраняется вместе с удалением.
+	Delete(ctx context.Context, id int, audit ...events.AuditRecorded) error
+	// ReleaseComment публикует скрытый комментарий, false - его нет или он не скрыт
+	ReleaseComment(ctx context.Context, id int) (bool, error)
+	CountByAuthor(ctx context.Context, authorID int) (int, error)
+	GetRecentByAuthor(ctx context.Context, authorID int, limit int) ([]entity.Comment, error)
+	GetAllByAuthor(ctx context.Context, authorID int) ([]entity.Comment, error)
+	AnonymizeAuthor(ctx context.Context, authorID int, placeholder string) error
+	DeleteByAuthor(ctx context.Context, authorID int) error
+	RenameAuthor(ctx context.Context, authorID int, username string) error
 }
 
 type CommentRepo struct {
@@ -23,18 +36,63 @@ func NewCommentRepo(db *sql.DB) CommentRepository {
 	return &CommentRepo{db: db}
 }
 
-func (r *CommentRepo) CreateComm(ctx context.Context, postId int, content, author string) error {
-	query := `INSERT INTO comments (post_id, content, author, created_at) VALUES ($1, $2, $3, $4)`
+func (r *CommentRepo) CreateComm(ctx context.Context, postId, parentID int, content, author string,
+	authorID int, mentions []entity.Mention, holdReasons []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	_, err := r.db.ExecContext(ctx, query, postId, content, author, time.Now())
-	return err
+	query := `INSERT INTO comments (post_id, parent_id, content, author, author_id, created_at, held)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7) RETURNING id`
+
+	var id int
+	err = tx.QueryRowContext(ctx, query, postId, parentID, content, author, authorID, time.Now(),
+		len(holdReasons) > 0).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertMentions(ctx, tx, postId, id, mentions); err != nil {
+		return err
+	}
+	if err := insertWatch(ctx, tx, authorID, postId); err != nil {
+		return err
+	}
+
+	// Уведомления о скрытом комментарии уйдут, когда модератор его опубликует
+	var event events.Event = events.CommentCreated{
+		CommentID:        id,
+		PostID:           postId,
+		ParentID:         parentID,
+		AuthorID:         authorID,
+		Author:           author,
+		Content:          content,
+		MentionedUserIDs: mentionedUserIDs(mentions),
+	}
+	if len(holdReasons) > 0 {
+		event = events.ContentHeld{
+			Service:    "forum",
+			TargetType: entity.ReportTargetComment,
+			TargetID:   id,
+			AuthorID:   authorID,
+			Text:       content,
+			Reasons:    holdReasons,
+		}
+	}
+	if err := events.Enqueue(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *CommentRepo) GetByPostID(ctx context.Context, postID int) ([]entity.Comment, error) {
 	query := `
-        SELECT id, post_id, content, author, created_at 
+        SELECT id, post_id, COALESCE(parent_id, 0), content, author, COALESCE(author_id, 0), created_at 
         FROM comments 
-        WHERE post_id = $1
+        WHERE post_id = $1 AND NOT held
         ORDER BY created_at DESC
     `
 
@@ -50,8 +108,10 @@ func (r *CommentRepo) GetByPostID(ctx context.Context, postID int) ([]entity.Com
 		if err := rows.Scan(
 			&c.ID,
 			&c.PostID,
+			&c.ParentID,
 			&c.Content,
 			&c.Author,
+			&c.AuthorID,
 			&c.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -64,7 +124,7 @@ func (r *CommentRepo) GetByPostID(ctx context.Context, postID int) ([]entity.Com
 
 func (r *CommentRepo) GetCommentByID(ctx context.Context, id int) (entity.Comment, error) {
 	query := `
-		SELECT id, post_id, content, author, created_at 
+		SELECT id, post_id, COALESCE(parent_id, 0), content, author, COALESCE(author_id, 0), created_at, held
 		FROM comments 
 		WHERE id = $1
 	`
@@ -73,9 +133,12 @@ func (r *CommentRepo) GetCommentByID(ctx context.Context, id int) (entity.Commen
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&c.ID,
 		&c.PostID,
+		&c.ParentID,
 		&c.Content,
 		&c.Author,
+		&c.AuthorID,
 		&c.CreatedAt,
+		&c.Held,
 	)
 
 	if err != nil {
@@ -88,8 +151,124 @@ func (r *CommentRepo) GetCommentByID(ctx context.Context, id int) (entity.Commen
 	return c, nil
 }
 
-func (r *CommentRepo) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM comments WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+func (r *CommentRepo) Delete(ctx context.Context, id int, audit ...events.AuditRecorded) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM comments WHERE id = $1`, id); err != nil {
+		return err
+	}
+	if err := events.RecordAudit(ctx, tx, audit...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *CommentRepo) ReleaseComment(ctx context.Context, id int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	event := events.CommentCreated{CommentID: id}
+	err = tx.QueryRowContext(ctx,
+		`UPDATE comments SET held = FALSE WHERE id = $1 AND held
+		 RETURNING post_id, COALESCE(parent_id, 0), content, author, COALESCE(author_id, 0)`,
+		id,
+	).Scan(&event.PostID, &event.ParentID, &event.Content, &event.Author, &event.AuthorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	event.MentionedUserIDs, err = storedMentionedUserIDs(ctx, tx, event.PostID, id)
+	if err != nil {
+		return false, err
+	}
+	if err := events.Enqueue(ctx, tx, event); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *CommentRepo) CountByAuthor(ctx context.Context, authorID int) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM comments WHERE author_id = $1 AND NOT held`, authorID).Scan(&count)
+	return count, err
+}
+
+func (r *CommentRepo) GetRecentByAuthor(ctx context.Context, authorID int, limit int) ([]entity.Comment, error) {
+	query := `
+		SELECT id, post_id, COALESCE(parent_id, 0), content, author, COALESCE(author_id, 0), created_at
+		FROM comments
+		WHERE author_id = $1 AND NOT held
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, authorID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []entity.Comment
+	for rows.Next() {
+		var c entity.Comment
+		if err := rows.Scan(&c.ID, &c.PostID, &c.ParentID, &c.Content, &c.Author, &c.AuthorID, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+
+	return comments, rows.Err()
+}
+
+func (r *CommentRepo) GetAllByAuthor(ctx context.Context, authorID int) ([]entity.Comment, error) {
+	query := `
+		SELECT id, post_id, COALESCE(parent_id, 0), content, author, COALESCE(author_id, 0), created_at
+		FROM comments
+		WHERE author_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []entity.Comment
+	for rows.Next() {
+		var c entity.Comment
+		if err := rows.Scan(&c.ID, &c.PostID, &c.ParentID, &c.Content, &c.Author, &c.AuthorID, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+
+	return comments, rows.Err()
+}
+
+func (r *CommentRepo) AnonymizeAuthor(ctx context.Context, authorID int, placeholder string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE comments SET author = $1, author_id = NULL WHERE author_id = $2`,
+		placeholder, authorID)
+	return err
+}
+
+func (r *CommentRepo) RenameAuthor(ctx context.Context, authorID int, username string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE comments SET author = $1 WHERE author_id = $2`, username, authorID)
+	return err
+}
+
+func (r *CommentRepo) DeleteByAuthor(ctx context.Context, authorID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM comments WHERE author_id = $1`, authorID)
 	return err
 }
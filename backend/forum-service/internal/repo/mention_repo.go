@@ -0,0 +1,127 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"go-forum-project/forum-service/internal/entity"
+)
+
+// MentionRepository читает упоминания пачкой для списка постов или комментариев.
+// Сохраняются упоминания вместе с постом или комментарием в одной транзакции.
+type MentionRepository interface {
+	GetForPosts(ctx context.Context, postIDs []int) (map[int][]entity.Mention, error)
+	GetForComments(ctx context.Context, commentIDs []int) (map[int][]entity.Mention, error)
+	// RenameUser и DeleteByUser меняют упоминания пользователя в чужих постах и комментариях
+	RenameUser(ctx context.Context, userID int, username string) error
+	DeleteByUser(ctx context.Context, userID int) error
+}
+
+type MentionRepo struct {
+	Db *sql.DB
+}
+
+func NewMentionRepo(db *sql.DB) MentionRepository {
+	return &MentionRepo{Db: db}
+}
+
+func (r *MentionRepo) GetForPosts(ctx context.Context, postIDs []int) (map[int][]entity.Mention, error) {
+	query := `SELECT post_id, user_id, username, offset_utf16, length_utf16 FROM mentions
+		WHERE post_id = ANY($1) AND comment_id IS NULL ORDER BY offset_utf16`
+	return r.query(ctx, query, postIDs)
+}
+
+func (r *MentionRepo) GetForComments(ctx context.Context, commentIDs []int) (map[int][]entity.Mention, error) {
+	query := `SELECT comment_id, user_id, username, offset_utf16, length_utf16 FROM mentions
+		WHERE comment_id = ANY($1) ORDER BY offset_utf16`
+	return r.query(ctx, query, commentIDs)
+}
+
+func (r *MentionRepo) query(ctx context.Context, query string, ids []int) (map[int][]entity.Mention, error) {
+	result := make(map[int][]entity.Mention)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.Db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id int
+			m  entity.Mention
+		)
+		if err := rows.Scan(&id, &m.UserID, &m.Username, &m.Offset, &m.Length); err != nil {
+			return nil, err
+		}
+		result[id] = append(result[id], m)
+	}
+
+	return result, rows.Err()
+}
+
+// insertMentions сохраняет упоминания поста (commentID == 0) или комментария.
+func insertMentions(ctx context.Context, tx *sql.Tx, postID, commentID int, mentions []entity.Mention) error {
+	for _, m := range mentions {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO mentions (post_id, comment_id, user_id, username, offset_utf16, length_utf16)
+			 VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6)`,
+			postID, commentID, m.UserID, m.Username, m.Offset, m.Length,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mentionedUserIDs возвращает упомянутых пользователей без повторов.
+func mentionedUserIDs(mentions []entity.Mention) []int {
+	var ids []int
+	seen := make(map[int]bool, len(mentions))
+	for _, m := range mentions {
+		if !seen[m.UserID] {
+			seen[m.UserID] = true
+			ids = append(ids, m.UserID)
+		}
+	}
+	return ids
+}
+
+// storedMentionedUserIDs читает уже сохранённые упоминания поста
+// (commentID == 0) или комментария, когда скрытый контент публикуется.
+func storedMentionedUserIDs(ctx context.Context, tx *sql.Tx, postID, commentID int) ([]int, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT DISTINCT user_id FROM mentions
+		 WHERE post_id = $1 AND COALESCE(comment_id, 0) = $2 ORDER BY user_id`,
+		postID, commentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *MentionRepo) RenameUser(ctx context.Context, userID int, username string) error {
+	_, err := r.Db.ExecContext(ctx, "UPDATE mentions SET username = $1 WHERE user_id = $2", username, userID)
+	return err
+}
+
+func (r *MentionRepo) DeleteByUser(ctx context.Context, userID int) error {
+	_, err := r.Db.ExecContext(ctx, "DELETE FROM mentions WHERE user_id = $1", userID)
+	return err
+}
@@ -0,0 +1,208 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go-forum-project/events"
+	"go-forum-project/forum-service/internal/entity"
+)
+
+type NotificationRepository interface {
+	// Create не создаёт дубль уведомления об одном и том же комментарии, посте или сообщении.
+	// Вместе с уведомлением в outbox пишется событие для доставки в реальном времени.
+	Create(ctx context.Context, n *entity.Notification) error
+	List(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]entity.Notification, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	MarkRead(ctx context.Context, userID, id int) (bool, error)
+	MarkAllRead(ctx context.Context, userID int) error
+	GetPreferences(ctx context.Context, userID int) (entity.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, userID int, prefs entity.NotificationPreferences) error
+
+	GetAllByUser(ctx context.Context, userID int) ([]entity.Notification, error)
+	// DeleteByUser удаляет уведомления и настройки удалённого аккаунта.
+	DeleteByUser(ctx context.Context, userID int) error
+	// AnonymizeActor и RenameActor меняют подпись в чужих уведомлениях,
+	// которые вызвал пользователь.
+	AnonymizeActor(ctx context.Context, actorID int, placeholder string) error
+	RenameActor(ctx context.Context, actorID int, username string) error
+}
+
+type NotificationRepo struct {
+	Db *sql.DB
+}
+
+func NewNotificationRepo(db *sql.DB) NotificationRepository {
+	return &NotificationRepo{Db: db}
+}
+
+func (r *NotificationRepo) Create(ctx context.Context, n *entity.Notification) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO notifications (user_id, type, actor_id, actor, post_id, comment_id, message_id, text)
+		 VALUES ($1, $2, NULLIF($3, 0), $4, NULLIF($5, 0), NULLIF($6, 0), NULLIF($7, 0), $8)
+		 ON CONFLICT DO NOTHING
+		 RETURNING id, created_at`,
+		n.UserID, n.Type, n.ActorID, n.Actor, n.PostID, n.CommentID, n.MessageID, n.Text,
+	).Scan(&n.ID, &n.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = events.Enqueue(ctx, tx, events.NotificationCreated{
+		NotificationID: n.ID,
+		UserID:         n.UserID,
+		Type:           n.Type,
+		ActorID:        n.ActorID,
+		Actor:          n.Actor,
+		PostID:         n.PostID,
+		CommentID:      n.CommentID,
+		MessageID:      n.MessageID,
+		Text:           n.Text,
+		CreatedAt:      n.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *NotificationRepo) List(ctx context.Context, userID int, unreadOnly bool,
+	limit, offset int) ([]entity.Notification, error) {
+	query := `
+		SELECT id, user_id, type, COALESCE(actor_id, 0), actor, COALESCE(post_id, 0), COALESCE(comment_id, 0),
+			COALESCE(message_id, 0), text, read_at IS NOT NULL, created_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.Db.QueryContext(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return scanNotifications(rows)
+}
+
+func (r *NotificationRepo) GetAllByUser(ctx context.Context, userID int) ([]entity.Notification, error) {
+	query := `
+		SELECT id, user_id, type, COALESCE(actor_id, 0), actor, COALESCE(post_id, 0), COALESCE(comment_id, 0),
+			COALESCE(message_id, 0), text, read_at IS NOT NULL, created_at
+		FROM notifications
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanNotifications(rows)
+}
+
+func scanNotifications(rows *sql.Rows) ([]entity.Notification, error) {
+	defer rows.Close()
+
+	notifications := []entity.Notification{}
+	for rows.Next() {
+		var n entity.Notification
+		err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.ActorID, &n.Actor, &n.PostID, &n.CommentID,
+			&n.MessageID, &n.Text, &n.Read, &n.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *NotificationRepo) CountUnread(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.Db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL", userID,
+	).Scan(&count)
+	return count, err
+}
+
+// MarkRead возвращает false, если у пользователя нет такого уведомления.
+func (r *NotificationRepo) MarkRead(ctx context.Context, userID, id int) (bool, error) {
+	result, err := r.Db.ExecContext(ctx,
+		"UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2",
+		id, userID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (r *NotificationRepo) MarkAllRead(ctx context.Context, userID int) error {
+	_, err := r.Db.ExecContext(ctx,
+		"UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL", userID)
+	return err
+}
+
+func (r *NotificationRepo) GetPreferences(ctx context.Context, userID int) (entity.NotificationPreferences, error) {
+	var prefs entity.NotificationPreferences
+	err := r.Db.QueryRowContext(ctx,
+		`SELECT comment_on_post, comment_reply, mention, digest
+		 FROM notification_preferences WHERE user_id = $1`,
+		userID,
+	).Scan(&prefs.CommentOnPost, &prefs.CommentReply, &prefs.Mention, &prefs.Digest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.DefaultNotificationPreferences(), nil
+	}
+	return prefs, err
+}
+
+func (r *NotificationRepo) SavePreferences(ctx context.Context, userID int,
+	prefs entity.NotificationPreferences) error {
+	_, err := r.Db.ExecContext(ctx,
+		`INSERT INTO notification_preferences (user_id, comment_on_post, comment_reply, mention, digest)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id) DO UPDATE SET
+		     comment_on_post = EXCLUDED.comment_on_post,
+		     comment_reply = EXCLUDED.comment_reply,
+		     mention = EXCLUDED.mention,
+		     digest = EXCLUDED.digest,
+		     updated_at = NOW()`,
+		userID, prefs.CommentOnPost, prefs.CommentReply, prefs.Mention, prefs.Digest,
+	)
+	return err
+}
+
+func (r *NotificationRepo) DeleteByUser(ctx context.Context, userID int) error {
+	if _, err := r.Db.ExecContext(ctx, "DELETE FROM notifications WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	_, err := r.Db.ExecContext(ctx, "DELETE FROM notification_preferences WHERE user_id = $1", userID)
+	return err
+}
+
+func (r *NotificationRepo) AnonymizeActor(ctx context.Context, actorID int, placeholder string) error {
+	_, err := r.Db.ExecContext(ctx, "UPDATE notifications SET actor = $1, actor_id = NULL WHERE actor_id = $2",
+		placeholder, actorID)
+	return err
+}
+
+func (r *NotificationRepo) RenameActor(ctx context.Context, actorID int, username string) error {
+	_, err := r.Db.ExecContext(ctx, "UPDATE notifications SET actor = $1 WHERE actor_id = $2", username, actorID)
+	return err
+}
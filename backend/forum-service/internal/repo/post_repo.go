@@ -3,19 +3,40 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 
+	"go-forum-project/events"
 	"go-forum-project/forum-service/internal/entity"
 )
 
 type PostRepository interface {
-	CreatePost(ctx context.Context, title, content, author string) error
-	UpdatePost(ctx context.Context, id int, title, content string) error
+	// CreatePost с непустым holdReasons сохраняет пост скрытым до проверки модератором
+	CreatePost(ctx context.Context, title, content, author string, authorID int, mentions []entity.Mention,
+		holdReasons []string) error
+	// UpdatePost заменяет упоминания поста на переданные
+	UpdatePost(ctx context.Context, id int, title, content string, mentions []entity.Mention) error
 	GetAllPosts(ctx context.Context) ([]*entity.Post, error)
 	GetPostByID(ctx context.Context, id int) (*entity.Post, error)
-	DeletePost(ctx context.Context, id int) error
+	// DeletePost удаляет пост. audit передаёт модератор, запись журнала
+	// сохраняется вместе с удалением.
+	DeletePost(ctx context.Context, id int, audit ...events.AuditRecorded) error
+	// SetFlag меняет флаг модерации поста и пишет audit в журнал, false - поста нет
+	SetFlag(ctx context.Context, id int, flag string, value bool, audit events.AuditRecorded) (bool, error)
+	// ReleasePost публикует скрытый пост, false - поста нет или он не скрыт
+	ReleasePost(ctx context.Context, id int) (bool, error)
+	CountByAuthor(ctx context.Context, authorID int) (int, error)
+	GetRecentByAuthor(ctx context.Context, authorID int, limit int) ([]*entity.Post, error)
+	GetAllByAuthor(ctx context.Context, authorID int) ([]*entity.Post, error)
+	AnonymizeAuthor(ctx context.Context, authorID int, placeholder string) error
+	DeleteByAuthor(ctx context.Context, authorID int) error
+	RenameAuthor(ctx context.Context, authorID int, username string) error
 }
 
+const postColumns = `id, title, content, author, COALESCE(author_id, 0), created_at, updated_at,
+	pinned, locked, archived, held`
+
 type PostRepo struct {
 	DB *sql.DB
 }
@@ -24,29 +45,89 @@ func NewPostRepo(db *sql.DB) PostRepository {
 	return &PostRepo{DB: db}
 }
 
-func (r *PostRepo) CreatePost(ctx context.Context, title, content, author string) error {
-	query := "INSERT INTO posts (title, content, author) VALUES ($1, $2, $3)"
-	_, err := r.DB.ExecContext(
+func (r *PostRepo) CreatePost(ctx context.Context, title, content, author string, authorID int,
+	mentions []entity.Mention, holdReasons []string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO posts (title, content, author, author_id, held) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	var id int
+	err = tx.QueryRowContext(
 		ctx,
 		query,
-		title, content, author,
-	)
-	return err
+		title, content, author, authorID, len(holdReasons) > 0,
+	).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertMentions(ctx, tx, id, 0, mentions); err != nil {
+		return err
+	}
+	if err := insertWatch(ctx, tx, authorID, id); err != nil {
+		return err
+	}
+
+	// Уведомления о скрытом посте уйдут, когда модератор его опубликует
+	var event events.Event = events.PostCreated{
+		PostID:           id,
+		AuthorID:         authorID,
+		Author:           author,
+		Title:            title,
+		MentionedUserIDs: mentionedUserIDs(mentions),
+	}
+	if len(holdReasons) > 0 {
+		event = events.ContentHeld{
+			Service:    "forum",
+			TargetType: entity.ReportTargetPost,
+			TargetID:   id,
+			AuthorID:   authorID,
+			Text:       title + "\n" + content,
+			Reasons:    holdReasons,
+		}
+	}
+	if err := events.Enqueue(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *PostRepo) UpdatePost(ctx context.Context, id int, title, content string) error {
+func (r *PostRepo) UpdatePost(ctx context.Context, id int, title, content string,
+	mentions []entity.Mention) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := "UPDATE posts SET title = $1, content = $2, updated_at = $3 WHERE id = $4"
-	_, err := r.DB.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		query,
 		title, content, time.Now(), id,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	_, err = tx.ExecContext(ctx, "DELETE FROM mentions WHERE post_id = $1 AND comment_id IS NULL", id)
+	if err != nil {
+		return err
+	}
+	if err := insertMentions(ctx, tx, id, 0, mentions); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *PostRepo) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
-	query := "SELECT id, title, content, author, created_at, updated_at FROM posts ORDER BY created_at DESC"
+	query := "SELECT " + postColumns + ` FROM posts
+		WHERE NOT held ORDER BY pinned DESC, created_at DESC`
 
 	rows, err := r.DB.QueryContext(ctx, query)
 	if err != nil {
@@ -62,8 +143,13 @@ func (r *PostRepo) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
 			&p.Title,
 			&p.Content,
 			&p.Author,
+			&p.AuthorID,
 			&p.CreatedAt,
 			&p.UpdatedAt,
+			&p.Pinned,
+			&p.Locked,
+			&p.Archived,
+			&p.Held,
 		)
 		if err != nil {
 			return nil, err
@@ -75,13 +161,15 @@ func (r *PostRepo) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
 }
 
 func (r *PostRepo) GetPostByID(ctx context.Context, id int) (*entity.Post, error) {
-	query := "SELECT id, title, content, author, created_at, updated_at FROM posts WHERE id = $1"
+	query := "SELECT " + postColumns + ` FROM posts
+		WHERE id = $1`
 
 	var p entity.Post
 	err := r.DB.QueryRowContext(ctx,
 		query,
 		id,
-	).Scan(&p.ID, &p.Title, &p.Content, &p.Author, &p.CreatedAt, &p.UpdatedAt)
+	).Scan(&p.ID, &p.Title, &p.Content, &p.Author, &p.AuthorID, &p.CreatedAt, &p.UpdatedAt,
+		&p.Pinned, &p.Locked, &p.Archived, &p.Held)
 	if err != nil {
 		return nil, err
 	}
@@ -89,8 +177,163 @@ func (r *PostRepo) GetPostByID(ctx context.Context, id int) (*entity.Post, error
 	return &p, nil
 }
 
-func (r *PostRepo) DeletePost(ctx context.Context, id int) error {
-	query := "DELETE FROM posts WHERE id = $1"
-	_, err := r.DB.ExecContext(ctx, query, id)
+func (r *PostRepo) DeletePost(ctx context.Context, id int, audit ...events.AuditRecorded) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM posts WHERE id = $1 RETURNING COALESCE(author_id, 0)"
+	var authorID int
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&authorID); err != nil {
+		// Уже удалённый пост - не ошибка
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if err := events.Enqueue(ctx, tx, events.PostDeleted{PostID: id, AuthorID: authorID}); err != nil {
+		return err
+	}
+	if err := events.RecordAudit(ctx, tx, audit...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostRepo) SetFlag(ctx context.Context, id int, flag string, value bool,
+	audit events.AuditRecorded) (bool, error) {
+	var column string
+	switch flag {
+	case entity.PostFlagPinned, entity.PostFlagLocked, entity.PostFlagArchived:
+		column = flag
+	default:
+		return false, fmt.Errorf("unknown post flag: %s", flag)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE posts SET "+column+" = $1 WHERE id = $2", value, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return false, err
+	}
+
+	if err := events.RecordAudit(ctx, tx, audit); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *PostRepo) ReleasePost(ctx context.Context, id int) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	event := events.PostCreated{PostID: id}
+	err = tx.QueryRowContext(ctx,
+		`UPDATE posts SET held = FALSE WHERE id = $1 AND held RETURNING title, author, COALESCE(author_id, 0)`,
+		id,
+	).Scan(&event.Title, &event.Author, &event.AuthorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	event.MentionedUserIDs, err = storedMentionedUserIDs(ctx, tx, id, 0)
+	if err != nil {
+		return false, err
+	}
+	if err := events.Enqueue(ctx, tx, event); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *PostRepo) CountByAuthor(ctx context.Context, authorID int) (int, error) {
+	var count int
+	err := r.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM posts WHERE author_id = $1 AND NOT held", authorID).Scan(&count)
+	return count, err
+}
+
+func (r *PostRepo) GetRecentByAuthor(ctx context.Context, authorID int, limit int) ([]*entity.Post, error) {
+	query := "SELECT " + postColumns + ` FROM posts
+		WHERE author_id = $1 AND NOT held ORDER BY created_at DESC LIMIT $2`
+
+	rows, err := r.DB.QueryContext(ctx, query, authorID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		p := &entity.Post{}
+		err := rows.Scan(&p.ID, &p.Title, &p.Content, &p.Author, &p.AuthorID, &p.CreatedAt, &p.UpdatedAt,
+			&p.Pinned, &p.Locked, &p.Archived, &p.Held)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, p)
+	}
+
+	return posts, rows.Err()
+}
+
+func (r *PostRepo) GetAllByAuthor(ctx context.Context, authorID int) ([]*entity.Post, error) {
+	query := "SELECT " + postColumns + ` FROM posts
+		WHERE author_id = $1 ORDER BY created_at`
+
+	rows, err := r.DB.QueryContext(ctx, query, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*entity.Post
+	for rows.Next() {
+		p := &entity.Post{}
+		err := rows.Scan(&p.ID, &p.Title, &p.Content, &p.Author, &p.AuthorID, &p.CreatedAt, &p.UpdatedAt,
+			&p.Pinned, &p.Locked, &p.Archived, &p.Held)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, p)
+	}
+
+	return posts, rows.Err()
+}
+
+// AnonymizeAuthor отвязывает посты от автора и подписывает их placeholder.
+func (r *PostRepo) AnonymizeAuthor(ctx context.Context, authorID int, placeholder string) error {
+	_, err := r.DB.ExecContext(ctx, "UPDATE posts SET author = $1, author_id = NULL WHERE author_id = $2",
+		placeholder, authorID)
+	return err
+}
+
+// RenameAuthor обновляет подпись постов после смены имени пользователя.
+func (r *PostRepo) RenameAuthor(ctx context.Context, authorID int, username string) error {
+	_, err := r.DB.ExecContext(ctx, "UPDATE posts SET author = $1 WHERE author_id = $2", username, authorID)
+	return err
+}
+
+func (r *PostRepo) DeleteByAuthor(ctx context.Context, authorID int) error {
+	_, err := r.DB.ExecContext(ctx, "DELETE FROM posts WHERE author_id = $1", authorID)
 	return err
 }
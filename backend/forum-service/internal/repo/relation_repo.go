@@ -0,0 +1,176 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"go-forum-project/events"
+	"go-forum-project/forum-service/internal/entity"
+)
+
+type RelationRepository interface {
+	// Set создаёт отношение или меняет mute на block. Заглушение не снимает
+	// уже действующую блокировку. Возвращает итоговый вид отношения.
+	Set(ctx context.Context, userID, targetID int, targetUsername, kind string) (string, error)
+	// Remove снимает отношение указанного вида, false - такого нет
+	Remove(ctx context.Context, userID, targetID int, kind string) (bool, error)
+	List(ctx context.Context, userID int) ([]entity.Relation, error)
+	// HiddenAuthorIDs - пользователи, чей контент userID не хочет видеть
+	HiddenAuthorIDs(ctx context.Context, userID int) (map[int]bool, error)
+	// BlockedBy возвращает, кто из userIDs заблокировал authorID
+	BlockedBy(ctx context.Context, authorID int, userIDs []int) (map[int]bool, error)
+	DeleteByUser(ctx context.Context, userID int) error
+	RenameTarget(ctx context.Context, targetID int, username string) error
+}
+
+type RelationRepo struct {
+	Db *sql.DB
+}
+
+func NewRelationRepo(db *sql.DB) RelationRepository {
+	return &RelationRepo{Db: db}
+}
+
+func (r *RelationRepo) Set(ctx context.Context, userID, targetID int, targetUsername,
+	kind string) (string, error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO user_relations (user_id, target_id, target_username, kind) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_id, target_id) DO UPDATE SET kind = EXCLUDED.kind, target_username = EXCLUDED.target_username
+		 WHERE EXCLUDED.kind = 'block'`,
+		userID, targetID, targetUsername, kind,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var current string
+	err = tx.QueryRowContext(ctx,
+		`SELECT kind FROM user_relations WHERE user_id = $1 AND target_id = $2`, userID, targetID,
+	).Scan(&current)
+	if err != nil {
+		return "", err
+	}
+
+	err = events.Enqueue(ctx, tx, events.RelationChanged{UserID: userID, TargetID: targetID, Kind: current})
+	if err != nil {
+		return "", err
+	}
+
+	return current, tx.Commit()
+}
+
+func (r *RelationRepo) Remove(ctx context.Context, userID, targetID int, kind string) (bool, error) {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`DELETE FROM user_relations WHERE user_id = $1 AND target_id = $2 AND kind = $3`, userID, targetID, kind)
+	if err != nil {
+		return false, err
+	}
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if removed == 0 {
+		return false, nil
+	}
+
+	if err := events.Enqueue(ctx, tx, events.RelationChanged{UserID: userID, TargetID: targetID}); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *RelationRepo) List(ctx context.Context, userID int) ([]entity.Relation, error) {
+	rows, err := r.Db.QueryContext(ctx,
+		`SELECT target_id, target_username, kind, created_at FROM user_relations
+		 WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	relations := []entity.Relation{}
+	for rows.Next() {
+		var rel entity.Relation
+		if err := rows.Scan(&rel.TargetID, &rel.TargetUsername, &rel.Kind, &rel.CreatedAt); err != nil {
+			return nil, err
+		}
+		relations = append(relations, rel)
+	}
+
+	return relations, rows.Err()
+}
+
+func (r *RelationRepo) HiddenAuthorIDs(ctx context.Context, userID int) (map[int]bool, error) {
+	hidden := make(map[int]bool)
+	if userID == 0 {
+		return hidden, nil
+	}
+
+	rows, err := r.Db.QueryContext(ctx, `SELECT target_id FROM user_relations WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		hidden[id] = true
+	}
+
+	return hidden, rows.Err()
+}
+
+func (r *RelationRepo) BlockedBy(ctx context.Context, authorID int, userIDs []int) (map[int]bool, error) {
+	blocked := make(map[int]bool)
+	if authorID == 0 || len(userIDs) == 0 {
+		return blocked, nil
+	}
+
+	rows, err := r.Db.QueryContext(ctx,
+		`SELECT user_id FROM user_relations WHERE target_id = $1 AND kind = $2 AND user_id = ANY($3)`,
+		authorID, entity.RelationBlock, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		blocked[id] = true
+	}
+
+	return blocked, rows.Err()
+}
+
+// DeleteByUser удаляет отношения удалённого аккаунта в обе стороны.
+// chat-service чистит свою копию сам при удалении аккаунта.
+func (r *RelationRepo) DeleteByUser(ctx context.Context, userID int) error {
+	_, err := r.Db.ExecContext(ctx, `DELETE FROM user_relations WHERE user_id = $1 OR target_id = $1`, userID)
+	return err
+}
+
+func (r *RelationRepo) RenameTarget(ctx context.Context, targetID int, username string) error {
+	_, err := r.Db.ExecContext(ctx, `UPDATE user_relations SET target_username = $1 WHERE target_id = $2`,
+		username, targetID)
+	return err
+}
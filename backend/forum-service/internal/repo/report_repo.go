@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-forum-project/events"
+	"go-forum-project/forum-service/internal/entity"
+)
+
+var ErrReportExists = errors.New("open report already exists")
+
+type ReportRepository interface {
+	// Create возвращает ErrReportExists, если у пользователя уже есть открытая жалоба на этот контент.
+	// ReporterID 0 - жалоба фильтра контента, такая открытая жалоба на контент тоже одна.
+	Create(ctx context.Context, r *entity.Report) error
+	GetByID(ctx context.Context, id int) (*entity.Report, error)
+	List(ctx context.Context, filter entity.ReportFilter, limit, offset int) ([]entity.Report, int, error)
+	// ResolveTarget закрывает все открытые жалобы на контент одним решением
+	// и в той же транзакции пишет audit в журнал.
+	ResolveTarget(ctx context.Context, targetType string, targetID int, status string, resolvedBy int,
+		resolution entity.ReportResolution, audit ...events.AuditRecorded) error
+}
+
+type ReportRepo struct {
+	Db *sql.DB
+}
+
+func NewReportRepo(db *sql.DB) ReportRepository {
+	return &ReportRepo{Db: db}
+}
+
+const reportColumns = `id, target_type, target_id, COALESCE(target_author_id, 0), target_excerpt,
+	COALESCE(reporter_id, 0), reason, details, status, COALESCE(action, ''), COALESCE(resolved_by, 0), note,
+	resolved_at, created_at`
+
+// rowScanner - общий интерфейс *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReport(row rowScanner) (entity.Report, error) {
+	var (
+		r          entity.Report
+		resolvedAt sql.NullTime
+	)
+	err := row.Scan(&r.ID, &r.TargetType, &r.TargetID, &r.TargetAuthorID, &r.TargetExcerpt, &r.ReporterID,
+		&r.Reason, &r.Details, &r.Status, &r.Action, &r.ResolvedBy, &r.Note, &resolvedAt, &r.CreatedAt)
+	r.ResolvedAt = resolvedAt.Time
+	return r, err
+}
+
+func (r *ReportRepo) Create(ctx context.Context, report *entity.Report) error {
+	err := r.Db.QueryRowContext(ctx,
+		`INSERT INTO reports (target_type, target_id, target_author_id, target_excerpt, reporter_id, reason, details)
+		 VALUES ($1, $2, NULLIF($3, 0), $4, NULLIF($5, 0), $6, $7)
+		 ON CONFLICT DO NOTHING
+		 RETURNING id, status, created_at`,
+		report.TargetType, report.TargetID, report.TargetAuthorID, report.TargetExcerpt, report.ReporterID,
+		report.Reason, report.Details,
+	).Scan(&report.ID, &report.Status, &report.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrReportExists
+	}
+	return err
+}
+
+func (r *ReportRepo) GetByID(ctx context.Context, id int) (*entity.Report, error) {
+	report, err := scanReport(r.Db.QueryRowContext(ctx, "SELECT "+reportColumns+" FROM reports WHERE id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *ReportRepo) List(ctx context.Context, filter entity.ReportFilter, limit,
+	offset int) ([]entity.Report, int, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if filter.ReporterID != 0 {
+		add("reporter_id = $%d", filter.ReporterID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.Db.QueryRowContext(ctx, "SELECT COUNT(*) FROM reports"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// Старые жалобы первыми, чтобы очередь разбиралась по порядку
+	query := fmt.Sprintf("SELECT %s FROM reports%s ORDER BY id LIMIT $%d OFFSET $%d",
+		reportColumns, where, len(args)+1, len(args)+2)
+	rows, err := r.Db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reports := []entity.Report{}
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, total, rows.Err()
+}
+
+func (r *ReportRepo) ResolveTarget(ctx context.Context, targetType string, targetID int, status string,
+	resolvedBy int, resolution entity.ReportResolution, audit ...events.AuditRecorded) error {
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE reports SET status = $1, action = $2, resolved_by = $3, note = $4, resolved_at = NOW()
+		 WHERE target_type = $5 AND target_id = $6 AND status = 'open'`,
+		status, resolution.Action, resolvedBy, resolution.Note, targetType, targetID,
+	)
+	if err != nil {
+		return err
+	}
+	if err := events.RecordAudit(ctx, tx, audit...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
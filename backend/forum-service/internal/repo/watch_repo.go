@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go-forum-project/forum-service/internal/entity"
+)
+
+type WatchRepository interface {
+	Watch(ctx context.Context, userID, postID int) error
+	Unwatch(ctx context.Context, userID, postID int) error
+	ListByUser(ctx context.Context, userID int) ([]entity.Watch, error)
+	DeleteByUser(ctx context.Context, userID int) error
+
+	// DueForDigest возвращает пользователей с выбранной частотой дайджеста,
+	// которым он не отправлялся после sentBefore. defaultFrequency действует
+	// для пользователей без сохранённых настроек.
+	DueForDigest(ctx context.Context, frequency, defaultFrequency string,
+		sentBefore time.Time) ([]entity.DigestRecipient, error)
+	// GetActivity собирает чужие комментарии в отслеживаемых постах за (since, until].
+	GetActivity(ctx context.Context, userID int, since, until time.Time) ([]entity.DigestItem, error)
+	MarkDigestSent(ctx context.Context, userID int, sentAt time.Time) error
+}
+
+type WatchRepo struct {
+	Db *sql.DB
+}
+
+func NewWatchRepo(db *sql.DB) WatchRepository {
+	return &WatchRepo{Db: db}
+}
+
+func (r *WatchRepo) Watch(ctx context.Context, userID, postID int) error {
+	_, err := r.Db.ExecContext(ctx,
+		"INSERT INTO watches (user_id, post_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, postID)
+	return err
+}
+
+func (r *WatchRepo) Unwatch(ctx context.Context, userID, postID int) error {
+	_, err := r.Db.ExecContext(ctx, "DELETE FROM watches WHERE user_id = $1 AND post_id = $2", userID, postID)
+	return err
+}
+
+func (r *WatchRepo) ListByUser(ctx context.Context, userID int) ([]entity.Watch, error) {
+	query := `SELECT w.post_id, p.title, w.created_at FROM watches w
+		JOIN posts p ON p.id = w.post_id
+		WHERE w.user_id = $1 ORDER BY w.created_at DESC`
+
+	rows, err := r.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	watches := []entity.Watch{}
+	for rows.Next() {
+		var w entity.Watch
+		if err := rows.Scan(&w.PostID, &w.Title, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		watches = append(watches, w)
+	}
+
+	return watches, rows.Err()
+}
+
+func (r *WatchRepo) DeleteByUser(ctx context.Context, userID int) error {
+	if _, err := r.Db.ExecContext(ctx, "DELETE FROM watches WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	_, err := r.Db.ExecContext(ctx, "DELETE FROM digest_deliveries WHERE user_id = $1", userID)
+	return err
+}
+
+func (r *WatchRepo) DueForDigest(ctx context.Context, frequency, defaultFrequency string,
+	sentBefore time.Time) ([]entity.DigestRecipient, error) {
+	query := `
+		SELECT DISTINCT w.user_id, d.sent_at
+		FROM watches w
+		LEFT JOIN notification_preferences p ON p.user_id = w.user_id
+		LEFT JOIN digest_deliveries d ON d.user_id = w.user_id
+		WHERE COALESCE(p.digest, $2) = $1 AND (d.sent_at IS NULL OR d.sent_at <= $3)
+	`
+
+	rows, err := r.Db.QueryContext(ctx, query, frequency, defaultFrequency, sentBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []entity.DigestRecipient
+	for rows.Next() {
+		var (
+			recipient entity.DigestRecipient
+			sentAt    sql.NullTime
+		)
+		if err := rows.Scan(&recipient.UserID, &sentAt); err != nil {
+			return nil, err
+		}
+		recipient.LastSentAt = sentAt.Time
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, rows.Err()
+}
+
+func (r *WatchRepo) GetActivity(ctx context.Context, userID int, since,
+	until time.Time) ([]entity.DigestItem, error) {
+	query := `
+		SELECT p.id, p.title, COUNT(c.id), MAX(c.created_at)
+		FROM watches w
+		JOIN posts p ON p.id = w.post_id
+		JOIN comments c ON c.post_id = w.post_id
+		WHERE w.user_id = $1
+			AND c.created_at > GREATEST($2, w.created_at) AND c.created_at <= $3
+			AND c.author_id IS DISTINCT FROM $1
+			AND NOT c.held AND NOT p.held
+		GROUP BY p.id, p.title
+		ORDER BY MAX(c.created_at) DESC
+	`
+
+	rows, err := r.Db.QueryContext(ctx, query, userID, since, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []entity.DigestItem
+	for rows.Next() {
+		var item entity.DigestItem
+		if err := rows.Scan(&item.PostID, &item.Title, &item.NewComments, &item.LastActivity); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *WatchRepo) MarkDigestSent(ctx context.Context, userID int, sentAt time.Time) error {
+	_, err := r.Db.ExecContext(ctx,
+		`INSERT INTO digest_deliveries (user_id, sent_at) VALUES ($1, $2)
+		 ON CONFLICT (user_id) DO UPDATE SET sent_at = EXCLUDED.sent_at`,
+		userID, sentAt)
+	return err
+}
+
+// insertWatch подписывает автора на пост при публикации поста или комментария.
+func insertWatch(ctx context.Context, tx *sql.Tx, userID, postID int) error {
+	if userID == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO watches (user_id, post_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", userID, postID)
+	return err
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+
+	"go-forum-project/events"
+	"go-forum-project/forum-service/internal/repo"
+)
+
+// AuditUseCase отдаёт журнал действий модераторов forum-service. Записи
+// забирает auth-service в общий журнал.
+type AuditUseCase interface {
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]events.AuditRecorded, error)
+}
+
+type auditUseCase struct {
+	auditRepo repo.AuditRepository
+}
+
+func NewAuditUseCase(ar repo.AuditRepository) AuditUseCase {
+	return &auditUseCase{auditRepo: ar}
+}
+
+func (uc *auditUseCase) ListAfter(ctx context.Context, afterID int64, limit int) ([]events.AuditRecorded, error) {
+	return uc.auditRepo.ListAfter(ctx, afterID, limit)
+}
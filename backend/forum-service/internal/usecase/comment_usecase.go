@@ -2,8 +2,10 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"go-forum-project/contentfilter"
 	"go-forum-project/forum-service/internal/entity"
 	"go-forum-project/forum-service/internal/repo"
 )
@@ -11,47 +13,155 @@ import (
 var (
 	ErrLengthComment = errors.New("content must be between 1 and 200 characters")
 	ErrPostNotFound  = errors.New("post not found")
+
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrNotCommentOwner = errors.New("you can only delete your own comments")
+	ErrInvalidParent   = errors.New("parent comment not found in this post")
 )
 
 type CommentUseCase interface {
-	Create(ctx context.Context, postID int, content, author string) error
-	GetByPostID(ctx context.Context, postID int) ([]entity.Comment, error)
-	DeleteComment(ctx context.Context, commentID int, currentUsername string) error
+	// Create с parentID == 0 создаёт комментарий верхнего уровня, иначе ответ.
+	// ErrContentHeld - комментарий сохранён скрытым до проверки модератором,
+	// ErrBlockedByUser - автор поста или родительского комментария заблокировал пользователя
+	Create(ctx context.Context, postID, parentID int, content, author string, authorID int) error
+	// GetByPostID скрывает комментарии авторов, которых viewerID заблокировал или заглушил
+	GetByPostID(ctx context.Context, postID, viewerID int) ([]entity.Comment, error)
+	DeleteComment(ctx context.Context, commentID, currentUserID int) error
 }
 
 type commentUseCase struct {
-	commentRepo repo.CommentRepository
-	postRepo    repo.PostRepository
+	commentRepo  repo.CommentRepository
+	postRepo     repo.PostRepository
+	mentionRepo  repo.MentionRepository
+	profiles     ProfileSource
+	filter       ContentChecker
+	relationRepo repo.RelationRepository
 }
 
-func NewCommentUseCase(cr repo.CommentRepository, pr repo.PostRepository) CommentUseCase {
+func NewCommentUseCase(cr repo.CommentRepository, pr repo.PostRepository, mr repo.MentionRepository,
+	profiles ProfileSource, filter ContentChecker, rr repo.RelationRepository) CommentUseCase {
 	return &commentUseCase{
-		commentRepo: cr,
-		postRepo:    pr,
+		commentRepo:  cr,
+		postRepo:     pr,
+		mentionRepo:  mr,
+		profiles:     profiles,
+		filter:       filter,
+		relationRepo: rr,
 	}
 }
 
-func (c *commentUseCase) Create(ctx context.Context, postID int, content, author string) error {
+func (c *commentUseCase) Create(ctx context.Context, postID, parentID int, content, author string,
+	authorID int) error {
 	if len(content) == 0 || len(content) > 200 {
 		return ErrLengthComment
 	}
 
-	if _, err := c.postRepo.GetPostByID(ctx, postID); err != nil {
+	post, err := c.postRepo.GetPostByID(ctx, postID)
+	if err != nil || post.Held {
 		return ErrPostNotFound
 	}
+	if post.Archived {
+		return ErrPostArchived
+	}
+	if post.Locked {
+		return ErrPostLocked
+	}
+
+	repliesTo := []int{post.AuthorID}
+	if parentID != 0 {
+		parent, err := c.commentRepo.GetCommentByID(ctx, parentID)
+		if err != nil || parent.PostID != postID || parent.Held {
+			return ErrInvalidParent
+		}
+		repliesTo = append(repliesTo, parent.AuthorID)
+	}
 
-	err := c.commentRepo.CreateComm(ctx, postID, content, author)
+	blocked, err := c.relationRepo.BlockedBy(ctx, authorID, repliesTo)
 	if err != nil {
 		return fmt.Errorf("repository error: %w", err)
 	}
+	if len(blocked) > 0 {
+		return ErrBlockedByUser
+	}
 
+	_, content, holdReasons, err := filterContent(ctx, c.filter, contentfilter.Input{
+		AuthorID: authorID,
+		Author:   author,
+		Kind:     "comment",
+		Text:     content,
+	})
+	if err != nil {
+		return err
+	}
+
+	mentions := resolveMentions(ctx, c.profiles, c.relationRepo, authorID, content)
+	err = c.commentRepo.CreateComm(ctx, postID, parentID, content, author, authorID, mentions, holdReasons)
+	if err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+
+	if len(holdReasons) > 0 {
+		return ErrContentHeld
+	}
 	return nil
 }
 
-func (c *commentUseCase) GetByPostID(ctx context.Context, postID int) ([]entity.Comment, error) {
-	return c.commentRepo.GetByPostID(ctx, postID)
+func (c *commentUseCase) GetByPostID(ctx context.Context, postID, viewerID int) ([]entity.Comment, error) {
+	comments, err := c.commentRepo.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden, err := c.relationRepo.HiddenAuthorIDs(ctx, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hidden authors: %w", err)
+	}
+	if len(hidden) > 0 {
+		visible := comments[:0]
+		for _, comment := range comments {
+			if !hidden[comment.AuthorID] {
+				visible = append(visible, comment)
+			}
+		}
+		comments = visible
+	}
+
+	ids := make([]int, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+
+	mentions, err := c.mentionRepo.GetForComments(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+	for i := range comments {
+		comments[i].Mentions = mentions[comments[i].ID]
+	}
+	return comments, nil
 }
 
-func (c *commentUseCase) DeleteComment(ctx context.Context, commentID int, currentUsername string) error {
+func (c *commentUseCase) DeleteComment(ctx context.Context, commentID, currentUserID int) error {
+	comment, err := c.commentRepo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		return fmt.Errorf("repository error: %w", err)
+	}
+
+	// Без author_id остались комментарии, автора которых не нашла миграция
+	if comment.AuthorID == 0 || comment.AuthorID != currentUserID {
+		return ErrNotCommentOwner
+	}
+
+	post, err := c.postRepo.GetPostByID(ctx, comment.PostID)
+	if err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	if post.Archived {
+		return ErrPostArchived
+	}
+
 	return c.commentRepo.Delete(ctx, commentID)
 }
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-forum-project/contentfilter"
+)
+
+var (
+	ErrContentRejected = errors.New("content rejected by content filter")
+	// ErrContentHeld - контент сохранён, но будет опубликован после проверки модератором
+	ErrContentHeld = errors.New("content is held for moderation")
+)
+
+// ContentChecker - цепочка фильтров контента, в приложении это contentfilter.Reloader.
+type ContentChecker interface {
+	Run(ctx context.Context, in contentfilter.Input) contentfilter.Verdict
+}
+
+// filterContent прогоняет контент через фильтры. Возвращает текст после
+// маскировки и причины, по которым контент нужно скрыть до проверки.
+func filterContent(ctx context.Context, filter ContentChecker, in contentfilter.Input) (string, string, []string,
+	error) {
+	verdict := filter.Run(ctx, in)
+	switch verdict.Action {
+	case contentfilter.ActionReject:
+		return "", "", nil, fmt.Errorf("%w: %s", ErrContentRejected, strings.Join(verdict.Filters, ", "))
+	case contentfilter.ActionHold:
+		// Правку не с чем держать отдельно от уже опубликованного текста
+		if in.Edit {
+			return "", "", nil, fmt.Errorf("%w: %s", ErrContentRejected, strings.Join(verdict.Filters, ", "))
+		}
+		return verdict.Title, verdict.Text, verdict.Reasons, nil
+	}
+	return verdict.Title, verdict.Text, nil, nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/repo"
+	"go-forum-project/mailer"
+)
+
+// Адреса запрашиваются в auth-service пачками не больше этой
+const contactsBatchSize = 500
+
+var digestPeriods = map[string]time.Duration{
+	entity.DigestDaily:  24 * time.Hour,
+	entity.DigestWeekly: 7 * 24 * time.Hour,
+}
+
+// ContactSource - источник email адресов, в приложении это клиент auth-service.
+type ContactSource interface {
+	GetUserContacts(ctx context.Context, userIDs []int) ([]entity.Contact, error)
+}
+
+// DigestUseCase рассылает письма с новой активностью в отслеживаемых постах.
+type DigestUseCase interface {
+	// SendDigests отправляет дайджесты всем, кому подошёл срок. Вызывается по расписанию.
+	SendDigests(ctx context.Context) error
+}
+
+type digestUseCase struct {
+	watchRepo repo.WatchRepository
+	contacts  ContactSource
+	mailer    mailer.Mailer
+	baseURL   string
+}
+
+func NewDigestUseCase(wr repo.WatchRepository, contacts ContactSource, m mailer.Mailer,
+	baseURL string) DigestUseCase {
+	return &digestUseCase{
+		watchRepo: wr,
+		contacts:  contacts,
+		mailer:    m,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (uc *digestUseCase) SendDigests(ctx context.Context) error {
+	now := time.Now()
+	defaultFrequency := entity.DefaultNotificationPreferences().Digest
+
+	for frequency, period := range digestPeriods {
+		recipients, err := uc.watchRepo.DueForDigest(ctx, frequency, defaultFrequency, now.Add(-period))
+		if err != nil {
+			return fmt.Errorf("failed to get digest recipients: %w", err)
+		}
+
+		for start := 0; start < len(recipients); start += contactsBatchSize {
+			end := min(start+contactsBatchSize, len(recipients))
+			if err := uc.sendBatch(ctx, recipients[start:end], period, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (uc *digestUseCase) sendBatch(ctx context.Context, recipients []entity.DigestRecipient, period time.Duration,
+	now time.Time) error {
+	ids := make([]int, 0, len(recipients))
+	for _, r := range recipients {
+		ids = append(ids, r.UserID)
+	}
+
+	contacts, err := uc.contacts.GetUserContacts(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get contacts: %w", err)
+	}
+	byUser := make(map[int]entity.Contact, len(contacts))
+	for _, c := range contacts {
+		byUser[c.UserID] = c
+	}
+
+	for _, r := range recipients {
+		since := r.LastSentAt
+		if since.IsZero() {
+			since = now.Add(-period)
+		}
+
+		items, err := uc.watchRepo.GetActivity(ctx, r.UserID, since, now)
+		if err != nil {
+			return fmt.Errorf("failed to get activity: %w", err)
+		}
+
+		// Письмо уходит только на подтверждённый адрес. Без новой активности
+		// отметка всё равно ставится, чтобы следующий период считался от неё
+		contact, ok := byUser[r.UserID]
+		if ok && contact.EmailVerified && len(items) > 0 {
+			if err := uc.mailer.Send(ctx, uc.render(contact, items)); err != nil {
+				// Повторим при следующем запуске
+				log.Printf("Failed to send digest to user %d: %v", r.UserID, err)
+				continue
+			}
+		}
+
+		if err := uc.watchRepo.MarkDigestSent(ctx, r.UserID, now); err != nil {
+			return fmt.Errorf("failed to mark digest sent: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (uc *digestUseCase) render(contact entity.Contact, items []entity.DigestItem) mailer.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nThere is new activity in the posts you are watching:\n\n", contact.Username)
+	for _, item := range items {
+		fmt.Fprintf(&b, "- %s: %d new comment(s)\n  %s/posts/%d\n", item.Title, item.NewComments, uc.baseURL,
+			item.PostID)
+	}
+	b.WriteString("\nYou can change how often you get this email in your notification settings.\n")
+
+	return mailer.Message{
+		To:      contact.Email,
+		Subject: "New activity in watched posts",
+		Body:    b.String(),
+	}
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go-forum-project/forum-service/internal/client"
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/repo"
+	"go-forum-project/mention"
+)
+
+// resolveMentions проверяет упоминания в тексте и убирает тех, кто
+// заблокировал автора.
+func resolveMentions(ctx context.Context, profiles ProfileSource, relations repo.RelationRepository,
+	authorID int, text string) []entity.Mention {
+	lookup := func(ctx context.Context, username string) (int, string, error) {
+		profile, err := profiles.GetProfile(ctx, username)
+		if err != nil {
+			if errors.Is(err, client.ErrProfileNotFound) {
+				return 0, "", mention.ErrUserNotFound
+			}
+			return 0, "", err
+		}
+		return profile.UserID, profile.Username, nil
+	}
+
+	return mention.DropBlocked(ctx, relations.BlockedBy, authorID, mention.Resolve(ctx, lookup, text))
+}
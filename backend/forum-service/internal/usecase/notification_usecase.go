@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-forum-project/events"
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/repo"
+)
+
+const (
+	defaultNotificationsPageSize = 20
+	maxNotificationsPageSize     = 100
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrInvalidDigest        = errors.New("digest must be one of: off, daily, weekly")
+)
+
+type NotificationUseCase interface {
+	// Notify сохраняет уведомление, если получатель не отключил этот тип и не
+	// заблокировал или заглушил автора действия. Уведомления о собственных
+	// действиях не создаются.
+	Notify(ctx context.Context, n *entity.Notification) error
+	List(ctx context.Context, userID int, unreadOnly bool, page, pageSize int) ([]entity.Notification, int, error)
+	MarkRead(ctx context.Context, userID, id int) error
+	MarkAllRead(ctx context.Context, userID int) error
+	GetPreferences(ctx context.Context, userID int) (entity.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID int,
+		update entity.NotificationPreferencesUpdate) (entity.NotificationPreferences, error)
+
+	// HandleCommentCreated уведомляет автора поста, автора комментария, на который ответили,
+	// и упомянутых пользователей.
+	HandleCommentCreated(ctx context.Context, event events.CommentCreated) error
+	HandlePostCreated(ctx context.Context, event events.PostCreated) error
+	// HandleMessageCreated уведомляет пользователей, упомянутых в чате.
+	HandleMessageCreated(ctx context.Context, event events.MessageCreated) error
+}
+
+type notificationUseCase struct {
+	notificationRepo repo.NotificationRepository
+	postRepo         repo.PostRepository
+	commentRepo      repo.CommentRepository
+	relationRepo     repo.RelationRepository
+}
+
+func NewNotificationUseCase(nr repo.NotificationRepository, pr repo.PostRepository,
+	cr repo.CommentRepository, rr repo.RelationRepository) NotificationUseCase {
+	return &notificationUseCase{
+		notificationRepo: nr,
+		postRepo:         pr,
+		commentRepo:      cr,
+		relationRepo:     rr,
+	}
+}
+
+func (uc *notificationUseCase) Notify(ctx context.Context, n *entity.Notification) error {
+	if n.UserID == 0 || n.UserID == n.ActorID {
+		return nil
+	}
+
+	prefs, err := uc.notificationRepo.GetPreferences(ctx, n.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	if !prefs.Allows(n.Type) {
+		return nil
+	}
+
+	// Предупреждение модератора доходит в любом случае
+	if n.Type != entity.NotificationWarning && n.ActorID != 0 {
+		hidden, err := uc.relationRepo.HiddenAuthorIDs(ctx, n.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get hidden authors: %w", err)
+		}
+		if hidden[n.ActorID] {
+			return nil
+		}
+	}
+
+	if err := uc.notificationRepo.Create(ctx, n); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+func (uc *notificationUseCase) List(ctx context.Context, userID int, unreadOnly bool,
+	page, pageSize int) ([]entity.Notification, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultNotificationsPageSize
+	}
+	if pageSize > maxNotificationsPageSize {
+		pageSize = maxNotificationsPageSize
+	}
+
+	notifications, err := uc.notificationRepo.List(ctx, userID, unreadOnly, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	unread, err := uc.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	return notifications, unread, nil
+}
+
+func (uc *notificationUseCase) MarkRead(ctx context.Context, userID, id int) error {
+	found, err := uc.notificationRepo.MarkRead(ctx, userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (uc *notificationUseCase) MarkAllRead(ctx context.Context, userID int) error {
+	return uc.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+func (uc *notificationUseCase) GetPreferences(ctx context.Context, userID int) (entity.NotificationPreferences, error) {
+	return uc.notificationRepo.GetPreferences(ctx, userID)
+}
+
+func (uc *notificationUseCase) UpdatePreferences(ctx context.Context, userID int,
+	update entity.NotificationPreferencesUpdate) (entity.NotificationPreferences, error) {
+	if update.Digest != nil {
+		switch *update.Digest {
+		case entity.DigestOff, entity.DigestDaily, entity.DigestWeekly:
+		default:
+			return entity.NotificationPreferences{}, ErrInvalidDigest
+		}
+	}
+
+	prefs, err := uc.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return prefs, err
+	}
+
+	apply := func(target *bool, value *bool) {
+		if value != nil {
+			*target = *value
+		}
+	}
+	apply(&prefs.CommentOnPost, update.CommentOnPost)
+	apply(&prefs.CommentReply, update.CommentReply)
+	apply(&prefs.Mention, update.Mention)
+	if update.Digest != nil {
+		prefs.Digest = *update.Digest
+	}
+
+	if err := uc.notificationRepo.SavePreferences(ctx, userID, prefs); err != nil {
+		return prefs, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+func (uc *notificationUseCase) HandleCommentCreated(ctx context.Context, event events.CommentCreated) error {
+	notification := entity.Notification{
+		ActorID:   event.AuthorID,
+		Actor:     event.Author,
+		PostID:    event.PostID,
+		CommentID: event.CommentID,
+		Text:      excerpt(event.Content),
+	}
+
+	// Каждый получатель получает одно уведомление о комментарии: ответ важнее
+	// комментария к посту, комментарий к посту важнее упоминания
+	notified := make(map[int]bool)
+
+	var replyTo int
+	if event.ParentID != 0 {
+		parent, err := uc.commentRepo.GetCommentByID(ctx, event.ParentID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		if err == nil && parent.AuthorID != 0 {
+			replyTo = parent.AuthorID
+			reply := notification
+			reply.UserID = replyTo
+			reply.Type = entity.NotificationCommentReply
+			if err := uc.Notify(ctx, &reply); err != nil {
+				return err
+			}
+			notified[replyTo] = true
+		}
+	}
+
+	post, err := uc.postRepo.GetPostByID(ctx, event.PostID)
+	if err != nil {
+		// Пост могли удалить до обработки события
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get post: %w", err)
+	}
+	if !notified[post.AuthorID] {
+		onPost := notification
+		onPost.UserID = post.AuthorID
+		onPost.Type = entity.NotificationCommentOnPost
+		if err := uc.Notify(ctx, &onPost); err != nil {
+			return err
+		}
+		notified[post.AuthorID] = true
+	}
+
+	for _, userID := range event.MentionedUserIDs {
+		if notified[userID] {
+			continue
+		}
+		mention := notification
+		mention.UserID = userID
+		mention.Type = entity.NotificationMention
+		if err := uc.Notify(ctx, &mention); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *notificationUseCase) HandlePostCreated(ctx context.Context, event events.PostCreated) error {
+	for _, userID := range event.MentionedUserIDs {
+		err := uc.Notify(ctx, &entity.Notification{
+			UserID:  userID,
+			Type:    entity.NotificationMention,
+			ActorID: event.AuthorID,
+			Actor:   event.Author,
+			PostID:  event.PostID,
+			Text:    excerpt(event.Title),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *notificationUseCase) HandleMessageCreated(ctx context.Context, event events.MessageCreated) error {
+	for _, userID := range event.MentionedUserIDs {
+		err := uc.Notify(ctx, &entity.Notification{
+			UserID:    userID,
+			Type:      entity.NotificationMention,
+			ActorID:   event.AuthorID,
+			Actor:     event.Author,
+			MessageID: event.MessageID,
+			Text:      excerpt(event.Text),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,7 +2,12 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"go-forum-project/contentfilter"
+	"go-forum-project/events"
 	"go-forum-project/forum-service/internal/entity"
 	"go-forum-project/forum-service/internal/repo"
 )
@@ -10,25 +15,52 @@ import (
 var (
 	ErrLengthContent = errors.New("content must be between 1 and 250 characters")
 	ErrLengthTitle   = errors.New("title must be between 1 and 100 characters")
+
+	ErrPostArchived    = errors.New("post is archived and read-only")
+	ErrPostLocked      = errors.New("post is locked, new comments are not allowed")
+	ErrInvalidPostFlag = errors.New("flag must be one of: pinned, locked, archived")
 )
 
+// Действия журнала аудита для установки и снятия флага поста
+var postFlagAuditActions = map[string][2]string{
+	entity.PostFlagPinned:   {events.AuditPostPin, events.AuditPostUnpin},
+	entity.PostFlagLocked:   {events.AuditPostLock, events.AuditPostUnlock},
+	entity.PostFlagArchived: {events.AuditPostArchive, events.AuditPostUnarchive},
+}
+
 type PostUseCase interface {
-	CreatePost(ctx context.Context, title, content, author string) error
-	GetAllPosts(ctx context.Context) ([]*entity.Post, error)
+	// CreatePost возвращает ErrContentHeld, если пост сохранён скрытым до проверки
+	CreatePost(ctx context.Context, title, content, author string, authorID int) error
+	// GetAllPosts скрывает посты авторов, которых viewerID заблокировал или заглушил
+	GetAllPosts(ctx context.Context, viewerID int) ([]*entity.Post, error)
 	GetPostById(ctx context.Context, id int) (*entity.Post, error)
 	UpdatePost(ctx context.Context, id int, title, content string) error
 	DeletePost(ctx context.Context, id int) error
+	// SetFlag закрепляет, закрывает или архивирует пост. Только для модераторов.
+	SetFlag(ctx context.Context, moderatorID int, moderatorRole string, postID int, flag string,
+		value bool) (*entity.Post, error)
 }
 
 type postUseCase struct {
-	repo repo.PostRepository
+	repo         repo.PostRepository
+	mentionRepo  repo.MentionRepository
+	profiles     ProfileSource
+	filter       ContentChecker
+	relationRepo repo.RelationRepository
 }
 
-func NewPostUseCase(repo repo.PostRepository) PostUseCase {
-	return &postUseCase{repo: repo}
+func NewPostUseCase(repo repo.PostRepository, mr repo.MentionRepository, profiles ProfileSource,
+	filter ContentChecker, rr repo.RelationRepository) PostUseCase {
+	return &postUseCase{
+		repo:         repo,
+		mentionRepo:  mr,
+		profiles:     profiles,
+		filter:       filter,
+		relationRepo: rr,
+	}
 }
 
-func (uc *postUseCase) CreatePost(ctx context.Context, title, content, author string) error {
+func (uc *postUseCase) CreatePost(ctx context.Context, title, content, author string, authorID int) error {
 	if len(title) == 0 || len(title) > 100 {
 		return ErrLengthTitle
 	}
@@ -36,19 +68,84 @@ func (uc *postUseCase) CreatePost(ctx context.Context, title, content, author st
 		return ErrLengthContent
 	}
 
-	if err := uc.repo.CreatePost(ctx, title, content, author); err != nil {
+	title, content, holdReasons, err := filterContent(ctx, uc.filter, contentfilter.Input{
+		AuthorID: authorID,
+		Author:   author,
+		Kind:     "post",
+		Title:    title,
+		Text:     content,
+	})
+	if err != nil {
+		return err
+	}
+
+	mentions := resolveMentions(ctx, uc.profiles, uc.relationRepo, authorID, content)
+	if err := uc.repo.CreatePost(ctx, title, content, author, authorID, mentions, holdReasons); err != nil {
 		return err
 	}
 
+	if len(holdReasons) > 0 {
+		return ErrContentHeld
+	}
 	return nil
 }
 
-func (uc *postUseCase) GetAllPosts(ctx context.Context) ([]*entity.Post, error) {
-	return uc.repo.GetAllPosts(ctx)
+func (uc *postUseCase) GetAllPosts(ctx context.Context, viewerID int) ([]*entity.Post, error) {
+	posts, err := uc.repo.GetAllPosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden, err := uc.relationRepo.HiddenAuthorIDs(ctx, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hidden authors: %w", err)
+	}
+	if len(hidden) > 0 {
+		visible := posts[:0]
+		for _, p := range posts {
+			if !hidden[p.AuthorID] {
+				visible = append(visible, p)
+			}
+		}
+		posts = visible
+	}
+
+	if err := uc.attachMentions(ctx, posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
 }
 
 func (uc *postUseCase) GetPostById(ctx context.Context, id int) (*entity.Post, error) {
-	return uc.repo.GetPostByID(ctx, id)
+	post, err := uc.repo.GetPostByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Скрытый пост виден только модераторам в очереди жалоб
+	if post.Held {
+		return nil, ErrPostNotFound
+	}
+
+	if err := uc.attachMentions(ctx, []*entity.Post{post}); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+func (uc *postUseCase) attachMentions(ctx context.Context, posts []*entity.Post) error {
+	ids := make([]int, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	mentions, err := uc.mentionRepo.GetForPosts(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get mentions: %w", err)
+	}
+	for _, p := range posts {
+		p.Mentions = mentions[p.ID]
+	}
+	return nil
 }
 
 func (uc *postUseCase) UpdatePost(ctx context.Context, id int, title, content string) error {
@@ -59,9 +156,105 @@ func (uc *postUseCase) UpdatePost(ctx context.Context, id int, title, content st
 		return ErrLengthContent
 	}
 
-	return uc.repo.UpdatePost(ctx, id, title, content)
+	post, err := uc.checkWritable(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	title, content, _, err = filterContent(ctx, uc.filter, contentfilter.Input{
+		AuthorID: post.AuthorID,
+		Author:   post.Author,
+		Kind:     "post",
+		Title:    title,
+		Text:     content,
+		Edit:     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	mentions := resolveMentions(ctx, uc.profiles, uc.relationRepo, post.AuthorID, content)
+	return uc.repo.UpdatePost(ctx, id, title, content, mentions)
 }
 
 func (uc *postUseCase) DeletePost(ctx context.Context, id int) error {
+	if _, err := uc.checkWritable(ctx, id); err != nil {
+		return err
+	}
+
 	return uc.repo.DeletePost(ctx, id)
 }
+
+// checkWritable запрещает автору менять архивированный пост.
+func (uc *postUseCase) checkWritable(ctx context.Context, id int) (*entity.Post, error) {
+	post, err := uc.repo.GetPostByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	if post.Archived {
+		return nil, ErrPostArchived
+	}
+	return post, nil
+}
+
+func (uc *postUseCase) SetFlag(ctx context.Context, moderatorID int, moderatorRole string, postID int, flag string,
+	value bool) (*entity.Post, error) {
+	actions, ok := postFlagAuditActions[flag]
+	if !ok {
+		return nil, ErrInvalidPostFlag
+	}
+
+	before, err := uc.repo.GetPostByID(ctx, postID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	if before.Held {
+		return nil, ErrPostNotFound
+	}
+
+	expected := *before
+	switch flag {
+	case entity.PostFlagPinned:
+		expected.Pinned = value
+	case entity.PostFlagLocked:
+		expected.Locked = value
+	case entity.PostFlagArchived:
+		expected.Archived = value
+	}
+
+	action := actions[0]
+	if !value {
+		action = actions[1]
+	}
+	updated, err := uc.repo.SetFlag(ctx, postID, flag, value, events.AuditRecorded{
+		ActorID:    moderatorID,
+		ActorRole:  moderatorRole,
+		Action:     action,
+		TargetType: "post",
+		TargetID:   postID,
+		Before:     postFlagsSnapshot(before),
+		After:      postFlagsSnapshot(&expected),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set post flag: %w", err)
+	}
+	if !updated {
+		return nil, ErrPostNotFound
+	}
+
+	return uc.GetPostById(ctx, postID)
+}
+
+func postFlagsSnapshot(post *entity.Post) json.RawMessage {
+	return events.Snapshot(map[string]bool{
+		entity.PostFlagPinned:   post.Pinned,
+		entity.PostFlagLocked:   post.Locked,
+		entity.PostFlagArchived: post.Archived,
+	})
+}
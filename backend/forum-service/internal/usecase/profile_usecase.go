@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-forum-project/forum-service/internal/client"
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/repo"
+)
+
+const (
+	recentActivityLimit = 10
+	excerptLength       = 100
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+// RenamedError возвращается, если страницу запросили по прежнему имени
+// пользователя. Username - текущее имя.
+type RenamedError struct {
+	Username string
+}
+
+func (e *RenamedError) Error() string {
+	return fmt.Sprintf("user was renamed to %s", e.Username)
+}
+
+// ProfileSource - источник профилей, в приложении это клиент auth-service.
+type ProfileSource interface {
+	GetProfile(ctx context.Context, username string) (*entity.Profile, error)
+}
+
+type ProfileUseCase interface {
+	GetUserPage(ctx context.Context, username string) (*entity.UserPage, error)
+}
+
+type profileUseCase struct {
+	profiles    ProfileSource
+	postRepo    repo.PostRepository
+	commentRepo repo.CommentRepository
+}
+
+func NewProfileUseCase(profiles ProfileSource, pr repo.PostRepository, cr repo.CommentRepository) ProfileUseCase {
+	return &profileUseCase{
+		profiles:    profiles,
+		postRepo:    pr,
+		commentRepo: cr,
+	}
+}
+
+func (uc *profileUseCase) GetUserPage(ctx context.Context, username string) (*entity.UserPage, error) {
+	profile, err := uc.profiles.GetProfile(ctx, username)
+	if err != nil {
+		if errors.Is(err, client.ErrProfileNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if !strings.EqualFold(profile.Username, username) {
+		return nil, &RenamedError{Username: profile.Username}
+	}
+
+	author := profile.UserID
+
+	postCount, err := uc.postRepo.CountByAuthor(ctx, author)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count posts: %w", err)
+	}
+
+	commentCount, err := uc.commentRepo.CountByAuthor(ctx, author)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	posts, err := uc.postRepo.GetRecentByAuthor(ctx, author, recentActivityLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent posts: %w", err)
+	}
+
+	comments, err := uc.commentRepo.GetRecentByAuthor(ctx, author, recentActivityLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent comments: %w", err)
+	}
+
+	activity := make([]entity.Activity, 0, len(posts)+len(comments))
+	for _, p := range posts {
+		activity = append(activity, entity.Activity{
+			Type:      entity.ActivityPost,
+			PostID:    p.ID,
+			Title:     p.Title,
+			Excerpt:   excerpt(p.Content),
+			CreatedAt: p.CreatedAt,
+		})
+	}
+	for _, c := range comments {
+		activity = append(activity, entity.Activity{
+			Type:      entity.ActivityComment,
+			PostID:    c.PostID,
+			CommentID: c.ID,
+			Excerpt:   excerpt(c.Content),
+			CreatedAt: c.CreatedAt,
+		})
+	}
+
+	sort.Slice(activity, func(i, j int) bool {
+		return activity[i].CreatedAt.After(activity[j].CreatedAt)
+	})
+	if len(activity) > recentActivityLimit {
+		activity = activity[:recentActivityLimit]
+	}
+
+	return &entity.UserPage{
+		Profile:        profile,
+		PostCount:      postCount,
+		CommentCount:   commentCount,
+		RecentActivity: activity,
+	}, nil
+}
+
+func excerpt(content string) string {
+	runes := []rune(content)
+	if len(runes) <= excerptLength {
+		return content
+	}
+	return string(runes[:excerptLength]) + "…"
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-forum-project/forum-service/internal/client"
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/repo"
+)
+
+var (
+	ErrInvalidRelation  = errors.New("relation must be one of: block, mute")
+	ErrRelationSelf     = errors.New("you cannot block or mute yourself")
+	ErrRelationNotFound = errors.New("relation not found")
+	ErrBlockedByUser    = errors.New("you cannot reply to this user")
+)
+
+type RelationUseCase interface {
+	List(ctx context.Context, userID int) ([]entity.Relation, error)
+	// Set блокирует или заглушает пользователя. Заглушение уже заблокированного
+	// пользователя блокировку не снимает, возвращается действующее отношение.
+	Set(ctx context.Context, userID int, username, kind string) (*entity.Relation, error)
+	Remove(ctx context.Context, userID int, username, kind string) error
+}
+
+type relationUseCase struct {
+	relationRepo repo.RelationRepository
+	profiles     ProfileSource
+}
+
+func NewRelationUseCase(rr repo.RelationRepository, profiles ProfileSource) RelationUseCase {
+	return &relationUseCase{relationRepo: rr, profiles: profiles}
+}
+
+func (uc *relationUseCase) List(ctx context.Context, userID int) ([]entity.Relation, error) {
+	return uc.relationRepo.List(ctx, userID)
+}
+
+func (uc *relationUseCase) Set(ctx context.Context, userID int, username, kind string) (*entity.Relation, error) {
+	target, err := uc.resolveTarget(ctx, userID, username, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := uc.relationRepo.Set(ctx, userID, target.UserID, target.Username, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save relation: %w", err)
+	}
+
+	return &entity.Relation{TargetID: target.UserID, TargetUsername: target.Username, Kind: current}, nil
+}
+
+func (uc *relationUseCase) Remove(ctx context.Context, userID int, username, kind string) error {
+	target, err := uc.resolveTarget(ctx, userID, username, kind)
+	if err != nil {
+		return err
+	}
+
+	removed, err := uc.relationRepo.Remove(ctx, userID, target.UserID, kind)
+	if err != nil {
+		return fmt.Errorf("failed to remove relation: %w", err)
+	}
+	if !removed {
+		return ErrRelationNotFound
+	}
+	return nil
+}
+
+func (uc *relationUseCase) resolveTarget(ctx context.Context, userID int, username,
+	kind string) (*entity.Profile, error) {
+	if kind != entity.RelationBlock && kind != entity.RelationMute {
+		return nil, ErrInvalidRelation
+	}
+
+	profile, err := uc.profiles.GetProfile(ctx, username)
+	if err != nil {
+		if errors.Is(err, client.ErrProfileNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	if profile.UserID == userID {
+		return nil, ErrRelationSelf
+	}
+	return profile, nil
+}
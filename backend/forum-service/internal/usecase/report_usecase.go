@@ -0,0 +1,409 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-forum-project/events"
+	"go-forum-project/forum-service/internal/client"
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/repo"
+)
+
+const (
+	maxReportDetailsLength = 1000
+	maxReportNoteLength    = 500
+	defaultReportsPageSize = 20
+	maxReportsPageSize     = 100
+)
+
+var (
+	ErrInvalidReportTarget  = errors.New("target_type must be one of: post, comment, message")
+	ErrInvalidReportReason  = errors.New("reason must be one of: spam, abuse, harassment, illegal, other")
+	ErrReportDetailsLength  = errors.New("details must be at most 1000 characters and are required for reason other")
+	ErrReportTargetNotFound = errors.New("reported content not found")
+	ErrCannotReportOwn      = errors.New("you cannot report your own content")
+	ErrAlreadyReported      = errors.New("you have already reported this content")
+	ErrReportNotFound       = errors.New("report not found")
+	ErrReportClosed         = errors.New("report is already resolved")
+	ErrInvalidReportAction  = errors.New("action must be one of: dismiss, remove, warn, ban")
+	ErrReportNoteLength     = errors.New("note must be at most 500 characters")
+	ErrTargetAuthorUnknown  = errors.New("author of the reported content is unknown")
+	ErrBanForbidden         = errors.New("your role is not allowed to ban users")
+)
+
+// ChatMessageSource - сообщения chat-service, в приложении это client.ChatClient.
+type ChatMessageSource interface {
+	GetMessage(ctx context.Context, id int) (*client.ChatMessage, error)
+	RemoveMessage(ctx context.Context, id, actorID int, actorRole, reason string) error
+	// ReleaseMessage публикует сообщение, скрытое фильтром контента
+	ReleaseMessage(ctx context.Context, id int) error
+}
+
+// UserBanner банит пользователей в auth-service от имени модератора.
+type UserBanner interface {
+	AdminBanUser(ctx context.Context, accessToken string, userID int, reason string, duration time.Duration) error
+}
+
+type ReportUseCase interface {
+	Create(ctx context.Context, reporterID int, targetType string, targetID int, reason,
+		details string) (*entity.Report, error)
+	List(ctx context.Context, filter entity.ReportFilter) ([]entity.Report, int, error)
+	Get(ctx context.Context, id int) (*entity.Report, error)
+	// Resolve применяет решение и закрывает все открытые жалобы на тот же контент.
+	// accessToken модератора нужен для бана через auth-service.
+	Resolve(ctx context.Context, moderatorID int, moderatorRole, accessToken string, reportID int,
+		resolution entity.ReportResolution) (*entity.Report, error)
+	// HandleContentHeld ставит скрытый фильтром контент в очередь модерации.
+	// Отклонение такой жалобы публикует контент, удаление - удаляет.
+	HandleContentHeld(ctx context.Context, event events.ContentHeld) error
+}
+
+type reportUseCase struct {
+	reportRepo     repo.ReportRepository
+	postRepo       repo.PostRepository
+	commentRepo    repo.CommentRepository
+	messages       ChatMessageSource
+	banner         UserBanner
+	notificationUC NotificationUseCase
+}
+
+func NewReportUseCase(rr repo.ReportRepository, pr repo.PostRepository, cr repo.CommentRepository,
+	messages ChatMessageSource, banner UserBanner, notificationUC NotificationUseCase) ReportUseCase {
+	return &reportUseCase{
+		reportRepo:     rr,
+		postRepo:       pr,
+		commentRepo:    cr,
+		messages:       messages,
+		banner:         banner,
+		notificationUC: notificationUC,
+	}
+}
+
+func (uc *reportUseCase) Create(ctx context.Context, reporterID int, targetType string, targetID int, reason,
+	details string) (*entity.Report, error) {
+	switch reason {
+	case entity.ReportReasonSpam, entity.ReportReasonAbuse, entity.ReportReasonHarassment,
+		entity.ReportReasonIllegal, entity.ReportReasonOther:
+	default:
+		return nil, ErrInvalidReportReason
+	}
+
+	details = strings.TrimSpace(details)
+	if len([]rune(details)) > maxReportDetailsLength || (reason == entity.ReportReasonOther && details == "") {
+		return nil, ErrReportDetailsLength
+	}
+
+	authorID, text, err := uc.loadTarget(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if authorID != 0 && authorID == reporterID {
+		return nil, ErrCannotReportOwn
+	}
+
+	report := &entity.Report{
+		TargetType:     targetType,
+		TargetID:       targetID,
+		TargetAuthorID: authorID,
+		TargetExcerpt:  excerpt(text),
+		ReporterID:     reporterID,
+		Reason:         reason,
+		Details:        details,
+	}
+	if err := uc.reportRepo.Create(ctx, report); err != nil {
+		if errors.Is(err, repo.ErrReportExists) {
+			return nil, ErrAlreadyReported
+		}
+		return nil, fmt.Errorf("failed to create report: %w", err)
+	}
+
+	return report, nil
+}
+
+// loadTarget возвращает автора и текст контента, на который жалуются.
+func (uc *reportUseCase) loadTarget(ctx context.Context, targetType string, targetID int) (int, string, error) {
+	switch targetType {
+	case entity.ReportTargetPost:
+		post, err := uc.postRepo.GetPostByID(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, "", ErrReportTargetNotFound
+			}
+			return 0, "", fmt.Errorf("failed to get post: %w", err)
+		}
+		return post.AuthorID, post.Title + "\n" + post.Content, nil
+	case entity.ReportTargetComment:
+		comment, err := uc.commentRepo.GetCommentByID(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, "", ErrReportTargetNotFound
+			}
+			return 0, "", fmt.Errorf("failed to get comment: %w", err)
+		}
+		return comment.AuthorID, comment.Content, nil
+	case entity.ReportTargetMessage:
+		message, err := uc.messages.GetMessage(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, client.ErrMessageNotFound) {
+				return 0, "", ErrReportTargetNotFound
+			}
+			return 0, "", fmt.Errorf("failed to get message: %w", err)
+		}
+		return message.AuthorID, message.Text, nil
+	default:
+		return 0, "", ErrInvalidReportTarget
+	}
+}
+
+func (uc *reportUseCase) List(ctx context.Context, filter entity.ReportFilter) ([]entity.Report, int, error) {
+	if filter.TargetType != "" {
+		switch filter.TargetType {
+		case entity.ReportTargetPost, entity.ReportTargetComment, entity.ReportTargetMessage:
+		default:
+			return nil, 0, ErrInvalidReportTarget
+		}
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = defaultReportsPageSize
+	}
+	if filter.PageSize > maxReportsPageSize {
+		filter.PageSize = maxReportsPageSize
+	}
+
+	return uc.reportRepo.List(ctx, filter, filter.PageSize, (filter.Page-1)*filter.PageSize)
+}
+
+func (uc *reportUseCase) Get(ctx context.Context, id int) (*entity.Report, error) {
+	report, err := uc.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReportNotFound
+		}
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	return report, nil
+}
+
+func (uc *reportUseCase) Resolve(ctx context.Context, moderatorID int, moderatorRole, accessToken string,
+	reportID int, resolution entity.ReportResolution) (*entity.Report, error) {
+	resolution.Note = strings.TrimSpace(resolution.Note)
+	if len([]rune(resolution.Note)) > maxReportNoteLength {
+		return nil, ErrReportNoteLength
+	}
+
+	report, err := uc.Get(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != entity.ReportStatusOpen {
+		return nil, ErrReportClosed
+	}
+
+	// Записи журнала сохраняются вместе с закрытием жалоб
+	var audit []events.AuditRecorded
+	status := entity.ReportStatusResolved
+	switch resolution.Action {
+	case entity.ReportActionDismiss:
+		status = entity.ReportStatusDismissed
+		if report.Reason == entity.ReportReasonFilter {
+			err = uc.releaseTarget(ctx, report)
+		}
+	case entity.ReportActionRemove:
+		err = uc.removeTarget(ctx, moderatorID, moderatorRole, report, resolution.Note)
+	case entity.ReportActionWarn:
+		var warning events.AuditRecorded
+		warning, err = uc.warnAuthor(ctx, moderatorID, moderatorRole, report, resolution.Note)
+		audit = append(audit, warning)
+	case entity.ReportActionBan:
+		err = uc.banAuthor(ctx, accessToken, report, resolution)
+	default:
+		return nil, ErrInvalidReportAction
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	audit = append(audit, events.AuditRecorded{
+		ActorID:    moderatorID,
+		ActorRole:  moderatorRole,
+		Action:     events.AuditReportResolve,
+		TargetType: "report",
+		TargetID:   report.ID,
+		Reason:     resolution.Note,
+		Before:     events.Snapshot(map[string]any{"status": report.Status}),
+		After: events.Snapshot(map[string]any{
+			"status":      status,
+			"action":      resolution.Action,
+			"target_type": report.TargetType,
+			"target_id":   report.TargetID,
+		}),
+	})
+	err = uc.reportRepo.ResolveTarget(ctx, report.TargetType, report.TargetID, status, moderatorID, resolution,
+		audit...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve report: %w", err)
+	}
+
+	return uc.Get(ctx, reportID)
+}
+
+// removeTarget удаляет контент вместе с записью в журнале. Уже удалённый
+// контент ошибкой не считается. Удаление сообщения чата записывает в журнал
+// сам chat-service.
+func (uc *reportUseCase) removeTarget(ctx context.Context, moderatorID int, moderatorRole string,
+	report *entity.Report, note string) error {
+	entry := events.AuditRecorded{
+		ActorID:    moderatorID,
+		ActorRole:  moderatorRole,
+		TargetType: report.TargetType,
+		TargetID:   report.TargetID,
+		Reason:     note,
+	}
+
+	var err error
+	switch report.TargetType {
+	case entity.ReportTargetPost:
+		var post *entity.Post
+		post, err = uc.postRepo.GetPostByID(ctx, report.TargetID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err == nil {
+			entry.Action = events.AuditPostRemove
+			entry.Before = events.Snapshot(map[string]any{
+				"title": post.Title, "content": post.Content, "author_id": post.AuthorID,
+			})
+			err = uc.postRepo.DeletePost(ctx, report.TargetID, entry)
+		}
+	case entity.ReportTargetComment:
+		var comment entity.Comment
+		comment, err = uc.commentRepo.GetCommentByID(ctx, report.TargetID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err == nil {
+			entry.Action = events.AuditCommentRemove
+			entry.Before = events.Snapshot(map[string]any{
+				"post_id": comment.PostID, "content": comment.Content, "author_id": comment.AuthorID,
+			})
+			err = uc.commentRepo.Delete(ctx, report.TargetID, entry)
+		}
+	case entity.ReportTargetMessage:
+		err = uc.messages.RemoveMessage(ctx, report.TargetID, moderatorID, moderatorRole, note)
+		if errors.Is(err, client.ErrMessageNotFound) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove %s: %w", report.TargetType, err)
+	}
+	return nil
+}
+
+// releaseTarget публикует контент, который фильтр ошибочно скрыл.
+// Уже удалённый или опубликованный контент ошибкой не считается.
+func (uc *reportUseCase) releaseTarget(ctx context.Context, report *entity.Report) error {
+	var err error
+	switch report.TargetType {
+	case entity.ReportTargetPost:
+		_, err = uc.postRepo.ReleasePost(ctx, report.TargetID)
+	case entity.ReportTargetComment:
+		_, err = uc.commentRepo.ReleaseComment(ctx, report.TargetID)
+	case entity.ReportTargetMessage:
+		err = uc.messages.ReleaseMessage(ctx, report.TargetID)
+		if errors.Is(err, client.ErrMessageNotFound) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release %s: %w", report.TargetType, err)
+	}
+	return nil
+}
+
+func (uc *reportUseCase) HandleContentHeld(ctx context.Context, event events.ContentHeld) error {
+	report := &entity.Report{
+		TargetType:     event.TargetType,
+		TargetID:       event.TargetID,
+		TargetAuthorID: event.AuthorID,
+		TargetExcerpt:  excerpt(event.Text),
+		Reason:         entity.ReportReasonFilter,
+		Details:        strings.Join(event.Reasons, "; "),
+	}
+	err := uc.reportRepo.Create(ctx, report)
+	if err != nil && !errors.Is(err, repo.ErrReportExists) {
+		return fmt.Errorf("failed to queue held %s: %w", event.TargetType, err)
+	}
+	return nil
+}
+
+// warnAuthor отправляет автору предупреждение и возвращает запись для журнала.
+func (uc *reportUseCase) warnAuthor(ctx context.Context, moderatorID int, moderatorRole string,
+	report *entity.Report, note string) (events.AuditRecorded, error) {
+	if report.TargetAuthorID == 0 {
+		return events.AuditRecorded{}, ErrTargetAuthorUnknown
+	}
+
+	text := note
+	if text == "" {
+		text = fmt.Sprintf("Your %s was reported for %s and reviewed by a moderator", report.TargetType, report.Reason)
+	}
+
+	warning := &entity.Notification{
+		UserID:  report.TargetAuthorID,
+		Type:    entity.NotificationWarning,
+		ActorID: moderatorID,
+		Text:    text,
+	}
+	switch report.TargetType {
+	case entity.ReportTargetPost:
+		warning.PostID = report.TargetID
+	case entity.ReportTargetComment:
+		warning.CommentID = report.TargetID
+	case entity.ReportTargetMessage:
+		warning.MessageID = report.TargetID
+	}
+	if err := uc.notificationUC.Notify(ctx, warning); err != nil {
+		return events.AuditRecorded{}, err
+	}
+
+	return events.AuditRecorded{
+		ActorID:    moderatorID,
+		ActorRole:  moderatorRole,
+		Action:     events.AuditUserWarn,
+		TargetType: "user",
+		TargetID:   report.TargetAuthorID,
+		Reason:     text,
+		After: events.Snapshot(map[string]any{
+			"report_id": report.ID, "content_type": report.TargetType, "content_id": report.TargetID,
+		}),
+	}, nil
+}
+
+func (uc *reportUseCase) banAuthor(ctx context.Context, accessToken string, report *entity.Report,
+	resolution entity.ReportResolution) error {
+	if report.TargetAuthorID == 0 {
+		return ErrTargetAuthorUnknown
+	}
+
+	reason := resolution.Note
+	if reason == "" {
+		reason = fmt.Sprintf("Report #%d: %s", report.ID, report.Reason)
+	}
+
+	// Сам бан записывает в журнал auth-service
+	err := uc.banner.AdminBanUser(ctx, accessToken, report.TargetAuthorID, reason, resolution.BanDuration)
+	if errors.Is(err, client.ErrPermissionDenied) {
+		return ErrBanForbidden
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"go-forum-project/forum-service/internal/config"
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/repo"
+)
+
+// UserDataUseCase выгружает и удаляет данные пользователя по запросу
+// auth-service при экспорте и удалении аккаунта.
+type UserDataUseCase interface {
+	Export(ctx context.Context, userID int) (*entity.UserData, error)
+	Erase(ctx context.Context, userID int) error
+	// RenameAuthor переподписывает контент после смены имени в auth-service.
+	RenameAuthor(ctx context.Context, userID int, username string) error
+}
+
+type userDataUseCase struct {
+	postRepo         repo.PostRepository
+	commentRepo      repo.CommentRepository
+	watchRepo        repo.WatchRepository
+	relationRepo     repo.RelationRepository
+	notificationRepo repo.NotificationRepository
+	mentionRepo      repo.MentionRepository
+	cfg              config.AccountsConfig
+}
+
+func NewUserDataUseCase(pr repo.PostRepository, cr repo.CommentRepository, wr repo.WatchRepository,
+	rr repo.RelationRepository, nr repo.NotificationRepository, mr repo.MentionRepository,
+	cfg config.AccountsConfig) UserDataUseCase {
+	return &userDataUseCase{postRepo: pr, commentRepo: cr, watchRepo: wr, relationRepo: rr,
+		notificationRepo: nr, mentionRepo: mr, cfg: cfg}
+}
+
+func (uc *userDataUseCase) Export(ctx context.Context, userID int) (*entity.UserData, error) {
+	posts, err := uc.postRepo.GetAllByAuthor(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts: %w", err)
+	}
+
+	comments, err := uc.commentRepo.GetAllByAuthor(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	watches, err := uc.watchRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watches: %w", err)
+	}
+
+	relations, err := uc.relationRepo.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get relations: %w", err)
+	}
+
+	notifications, err := uc.notificationRepo.GetAllByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	prefs, err := uc.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	return &entity.UserData{
+		UserID:                  userID,
+		Posts:                   posts,
+		Comments:                comments,
+		Watches:                 watches,
+		Relations:               relations,
+		Notifications:           notifications,
+		NotificationPreferences: prefs,
+	}, nil
+}
+
+// Erase идемпотентен: auth-service повторяет вызов, пока стирание не пройдёт.
+func (uc *userDataUseCase) Erase(ctx context.Context, userID int) error {
+	if err := uc.watchRepo.DeleteByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete watches: %w", err)
+	}
+	if err := uc.relationRepo.DeleteByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete relations: %w", err)
+	}
+	if err := uc.notificationRepo.DeleteByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete notifications: %w", err)
+	}
+	// Текст постов не меняется, упоминание просто перестаёт быть ссылкой
+	if err := uc.mentionRepo.DeleteByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete mentions: %w", err)
+	}
+
+	placeholder := uc.cfg.DeletedAuthor
+	if placeholder == "" {
+		placeholder = "deleted"
+	}
+
+	// Уведомления других пользователей остаются, но без имени автора
+	if err := uc.notificationRepo.AnonymizeActor(ctx, userID, placeholder); err != nil {
+		return fmt.Errorf("failed to anonymize notifications: %w", err)
+	}
+
+	if uc.cfg.DeletedContent == config.DeletedContentRemove {
+		if err := uc.commentRepo.DeleteByAuthor(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete comments: %w", err)
+		}
+		if err := uc.postRepo.DeleteByAuthor(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete posts: %w", err)
+		}
+		return nil
+	}
+
+	if err := uc.commentRepo.AnonymizeAuthor(ctx, userID, placeholder); err != nil {
+		return fmt.Errorf("failed to anonymize comments: %w", err)
+	}
+	if err := uc.postRepo.AnonymizeAuthor(ctx, userID, placeholder); err != nil {
+		return fmt.Errorf("failed to anonymize posts: %w", err)
+	}
+	return nil
+}
+
+func (uc *userDataUseCase) RenameAuthor(ctx context.Context, userID int, username string) error {
+	if err := uc.postRepo.RenameAuthor(ctx, userID, username); err != nil {
+		return fmt.Errorf("failed to rename posts author: %w", err)
+	}
+	if err := uc.commentRepo.RenameAuthor(ctx, userID, username); err != nil {
+		return fmt.Errorf("failed to rename comments author: %w", err)
+	}
+	if err := uc.relationRepo.RenameTarget(ctx, userID, username); err != nil {
+		return fmt.Errorf("failed to rename relations target: %w", err)
+	}
+	if err := uc.notificationRepo.RenameActor(ctx, userID, username); err != nil {
+		return fmt.Errorf("failed to rename notifications actor: %w", err)
+	}
+	if err := uc.mentionRepo.RenameUser(ctx, userID, username); err != nil {
+		return fmt.Errorf("failed to rename mentions: %w", err)
+	}
+	return nil
+}
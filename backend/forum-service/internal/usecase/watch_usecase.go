@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go-forum-project/forum-service/internal/entity"
+	"go-forum-project/forum-service/internal/repo"
+)
+
+// WatchUseCase - подписка на пост без комментирования. Автор поста и
+// комментаторы подписываются автоматически.
+type WatchUseCase interface {
+	Watch(ctx context.Context, userID, postID int) error
+	Unwatch(ctx context.Context, userID, postID int) error
+	List(ctx context.Context, userID int) ([]entity.Watch, error)
+}
+
+type watchUseCase struct {
+	watchRepo repo.WatchRepository
+	postRepo  repo.PostRepository
+}
+
+func NewWatchUseCase(wr repo.WatchRepository, pr repo.PostRepository) WatchUseCase {
+	return &watchUseCase{watchRepo: wr, postRepo: pr}
+}
+
+func (uc *watchUseCase) Watch(ctx context.Context, userID, postID int) error {
+	if _, err := uc.postRepo.GetPostByID(ctx, postID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPostNotFound
+		}
+		return fmt.Errorf("failed to get post: %w", err)
+	}
+
+	return uc.watchRepo.Watch(ctx, userID, postID)
+}
+
+func (uc *watchUseCase) Unwatch(ctx context.Context, userID, postID int) error {
+	return uc.watchRepo.Unwatch(ctx, userID, postID)
+}
+
+func (uc *watchUseCase) List(ctx context.Context, userID int) ([]entity.Watch, error) {
+	return uc.watchRepo.ListByUser(ctx, userID)
+}
@@ -0,0 +1 @@
+CREATE INDEX idx_comments_author ON comments (author);
\ No newline at end of file
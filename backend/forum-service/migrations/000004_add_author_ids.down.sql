@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_comments_author_id;
+DROP INDEX IF EXISTS idx_posts_author_id;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS author_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS author_id;
\ No newline at end of file
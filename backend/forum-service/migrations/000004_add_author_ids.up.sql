@@ -0,0 +1,5 @@
+ALTER TABLE posts ADD COLUMN author_id INTEGER;
+ALTER TABLE comments ADD COLUMN author_id INTEGER;
+
+CREATE INDEX idx_posts_author_id ON posts (author_id);
+CREATE INDEX idx_comments_author_id ON comments (author_id);
\ No newline at end of file
@@ -0,0 +1,28 @@
+ALTER TABLE comments ADD COLUMN parent_id INTEGER REFERENCES comments(id) ON DELETE SET NULL;
+
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    actor_id INTEGER,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    post_id INTEGER REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    text TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications (user_id, id DESC);
+CREATE INDEX idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+-- События доставляются повторно, одно и то же уведомление не должно дублироваться
+CREATE UNIQUE INDEX idx_notifications_unique_comment ON notifications (user_id, type, comment_id)
+    WHERE comment_id IS NOT NULL;
+
+CREATE TABLE notification_preferences (
+    user_id INTEGER PRIMARY KEY,
+    comment_on_post BOOLEAN NOT NULL DEFAULT TRUE,
+    comment_reply BOOLEAN NOT NULL DEFAULT TRUE,
+    mention BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
\ No newline at end of file
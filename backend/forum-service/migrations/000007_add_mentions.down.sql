@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_notifications_unique_message;
+DROP INDEX IF EXISTS idx_notifications_unique_post;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS message_id;
+
+DROP TABLE IF EXISTS mentions;
\ No newline at end of file
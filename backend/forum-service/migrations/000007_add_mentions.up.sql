@@ -0,0 +1,21 @@
+CREATE TABLE mentions (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    -- NULL для упоминаний в самом посте
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    offset_utf16 INTEGER NOT NULL,
+    length_utf16 INTEGER NOT NULL
+);
+
+CREATE INDEX idx_mentions_post_id ON mentions (post_id) WHERE comment_id IS NULL;
+CREATE INDEX idx_mentions_comment_id ON mentions (comment_id);
+CREATE INDEX idx_mentions_user_id ON mentions (user_id);
+
+ALTER TABLE notifications ADD COLUMN message_id INTEGER;
+
+CREATE UNIQUE INDEX idx_notifications_unique_post ON notifications (user_id, type, post_id)
+    WHERE comment_id IS NULL AND post_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_notifications_unique_message ON notifications (user_id, type, message_id)
+    WHERE message_id IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS digest_deliveries;
+
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS digest;
+
+DROP TABLE IF EXISTS watches;
\ No newline at end of file
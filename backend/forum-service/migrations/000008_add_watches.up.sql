@@ -0,0 +1,15 @@
+CREATE TABLE watches (
+    user_id INTEGER NOT NULL,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX idx_watches_post_id ON watches (post_id);
+
+ALTER TABLE notification_preferences ADD COLUMN digest VARCHAR(10) NOT NULL DEFAULT 'weekly';
+
+CREATE TABLE digest_deliveries (
+    user_id INTEGER PRIMARY KEY,
+    sent_at TIMESTAMP NOT NULL
+);
\ No newline at end of file
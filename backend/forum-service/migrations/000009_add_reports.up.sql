@@ -0,0 +1,23 @@
+CREATE TABLE reports (
+    id SERIAL PRIMARY KEY,
+    target_type VARCHAR(16) NOT NULL,
+    target_id INTEGER NOT NULL,
+    target_author_id INTEGER,
+    target_excerpt TEXT NOT NULL DEFAULT '',
+    reporter_id INTEGER NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    action VARCHAR(16),
+    resolved_by INTEGER,
+    note TEXT NOT NULL DEFAULT '',
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_reports_status ON reports (status, id);
+CREATE INDEX idx_reports_target ON reports (target_type, target_id);
+CREATE INDEX idx_reports_reporter_id ON reports (reporter_id);
+-- Пользователь не может пожаловаться на одно и то же дважды, пока жалоба открыта
+CREATE UNIQUE INDEX idx_reports_unique_open ON reports (reporter_id, target_type, target_id)
+    WHERE status = 'open';
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_posts_pinned_created_at;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS pinned,
+    DROP COLUMN IF EXISTS locked,
+    DROP COLUMN IF EXISTS archived;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE posts
+    ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN locked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Закреплённые посты идут в ленте первыми
+CREATE INDEX idx_posts_pinned_created_at ON posts(pinned DESC, created_at DESC);
\ No newline at end of file
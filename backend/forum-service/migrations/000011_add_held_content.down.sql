@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_reports_unique_open_system;
+DELETE FROM reports WHERE reporter_id IS NULL;
+ALTER TABLE reports ALTER COLUMN reporter_id SET NOT NULL;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS held;
+ALTER TABLE posts DROP COLUMN IF EXISTS held;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Контент, скрытый фильтром до проверки модератором
+ALTER TABLE posts ADD COLUMN held BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD COLUMN held BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Жалобы без автора ставит в очередь фильтр контента
+ALTER TABLE reports ALTER COLUMN reporter_id DROP NOT NULL;
+CREATE UNIQUE INDEX idx_reports_unique_open_system ON reports (target_type, target_id)
+    WHERE reporter_id IS NULL AND status = 'open';
\ No newline at end of file
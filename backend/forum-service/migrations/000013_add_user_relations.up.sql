@@ -0,0 +1,12 @@
+-- Блокировки и заглушения между пользователями. target_username хранится
+-- для списка, переименование обновляет его по событию auth-service
+CREATE TABLE user_relations (
+    user_id INTEGER NOT NULL,
+    target_id INTEGER NOT NULL,
+    target_username VARCHAR(255) NOT NULL,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('block', 'mute')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, target_id)
+);
+
+CREATE INDEX idx_user_relations_target_id ON user_relations (target_id);
\ No newline at end of file
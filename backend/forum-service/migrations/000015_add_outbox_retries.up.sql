@@ -0,0 +1,9 @@
+ALTER TABLE outbox
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMP,
+    ADD COLUMN failed_at TIMESTAMP,
+    ADD COLUMN last_error TEXT;
+
+-- Событие с failed_at исчерпало попытки и больше не отправляется
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX idx_outbox_unpublished ON outbox (id) WHERE published_at IS NULL AND failed_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,14 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer пишет письма в лог, удобно для тестов и отладки.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
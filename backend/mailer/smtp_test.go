@@ -0,0 +1,33 @@
+package mailer
+
+import "testing"
+
+func TestNewSMTPMailerSender(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		sender  string
+		wantErr bool
+	}{
+		{"bare address", "no-reply@localhost", "no-reply@localhost", false},
+		{"display name", "Go Forum <no-reply@localhost>", "no-reply@localhost", false},
+		{"quoted display name", `"Go Forum, support" <help@example.com>`, "help@example.com", false},
+		{"invalid", "Go Forum", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewSMTPMailer(SMTPConfig{Host: "localhost", Port: 1025}, tt.from)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSMTPMailer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// В заголовок From идёт строка из конфига как есть
+			if m.sender != tt.sender || m.from != tt.from {
+				t.Errorf("sender = %q, from = %q; want %q, %q", m.sender, m.from, tt.sender, tt.from)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package mention находит упоминания @username в тексте и проверяет их
+// в auth-service. Общий для forum-service и chat-service.
+package mention
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// MaxPerText - сколько разных пользователей можно упомянуть в одном тексте.
+const MaxPerText = 10
+
+// Candidate - упоминание до проверки имени в auth-service. Offset и Length
+// считаются в единицах UTF-16, как индексы строк в JavaScript, и включают
+// символ @.
+type Candidate struct {
+	Username string
+	Offset   int
+	Length   int
+}
+
+// Extract возвращает упоминания в порядке появления. @ внутри слова
+// (например, в email) упоминанием не считается.
+func Extract(text string) []Candidate {
+	var (
+		candidates []Candidate
+		distinct   = make(map[string]struct{})
+		runes      = []rune(text)
+		offsets    = utf16Offsets(runes)
+	)
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' || (i > 0 && isNameRune(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isNameRune(runes[end]) {
+			end++
+		}
+		// Точка или дефис в конце - это пунктуация, а не часть имени
+		for end > i+1 && (runes[end-1] == '.' || runes[end-1] == '-') {
+			end--
+		}
+		if end == i+1 {
+			continue
+		}
+
+		username := string(runes[i+1 : end])
+		key := strings.ToLower(username)
+		if _, ok := distinct[key]; !ok {
+			if len(distinct) == MaxPerText {
+				i = end - 1
+				continue
+			}
+			distinct[key] = struct{}{}
+		}
+
+		candidates = append(candidates, Candidate{
+			Username: username,
+			Offset:   offsets[i],
+			Length:   offsets[end] - offsets[i],
+		})
+		i = end - 1
+	}
+
+	return candidates
+}
+
+// utf16Offsets возвращает позицию каждой руны в UTF-16 и длину всего
+// текста последним элементом.
+func utf16Offsets(runes []rune) []int {
+	offsets := make([]int, len(runes)+1)
+	for i, r := range runes {
+		size := utf16.RuneLen(r)
+		if size < 0 {
+			// Невалидная руна заменяется на U+FFFD
+			size = 1
+		}
+		offsets[i+1] = offsets[i] + size
+	}
+	return offsets
+}
+
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
@@ -0,0 +1,65 @@
+package mention
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Candidate
+	}{
+		{"empty", "", nil},
+		{"no mentions", "hello world", nil},
+		{"single", "hi @alice", []Candidate{{Username: "alice", Offset: 3, Length: 6}}},
+		{"at start", "@bob hi", []Candidate{{Username: "bob", Offset: 0, Length: 4}}},
+		{"several", "@a and @b", []Candidate{
+			{Username: "a", Offset: 0, Length: 2},
+			{Username: "b", Offset: 7, Length: 2},
+		}},
+		{"email is not a mention", "mail me at bob@example.com", nil},
+		{"lone at", "@ @", nil},
+		{"trailing punctuation", "thanks @alice.", []Candidate{{Username: "alice", Offset: 7, Length: 6}}},
+		{"trailing dash", "@bob-", []Candidate{{Username: "bob", Offset: 0, Length: 4}}},
+		{"dots inside name", "@john.doe!", []Candidate{{Username: "john.doe", Offset: 0, Length: 9}}},
+		{"cyrillic name", "привет @вася", []Candidate{{Username: "вася", Offset: 7, Length: 5}}},
+		// Эмодзи вне BMP занимает две единицы UTF-16
+		{"offsets in utf16", "😀 @bob", []Candidate{{Username: "bob", Offset: 3, Length: 4}}},
+		{"repeated name", "@Bob @bob", []Candidate{
+			{Username: "Bob", Offset: 0, Length: 4},
+			{Username: "bob", Offset: 5, Length: 4},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Extract(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractLimit(t *testing.T) {
+	names := make([]string, 0, MaxPerText+2)
+	for i := 0; i < MaxPerText+1; i++ {
+		names = append(names, fmt.Sprintf("@user%d", i))
+	}
+	// Повтор уже упомянутого имени в лимит не входит
+	names = append(names, "@user0")
+
+	got := Extract(strings.Join(names, " "))
+	if len(got) != MaxPerText+1 {
+		t.Fatalf("got %d mentions, want %d", len(got), MaxPerText+1)
+	}
+	for _, c := range got {
+		if c.Username == fmt.Sprintf("user%d", MaxPerText) {
+			t.Errorf("mention over the limit was extracted: %+v", c)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package mention
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+)
+
+// ErrUserNotFound возвращает Lookup, если пользователя с таким именем нет.
+var ErrUserNotFound = errors.New("user not found")
+
+// Mention - упоминание существующего пользователя. Offset и Length - как у
+// Candidate, в единицах UTF-16.
+type Mention struct {
+	UserID   int
+	Username string
+	Offset   int
+	Length   int
+}
+
+// Lookup ищет пользователя по имени и возвращает его id и текущее имя.
+type Lookup func(ctx context.Context, username string) (int, string, error)
+
+// BlockedBy возвращает тех из userIDs, кто заблокировал authorID.
+type BlockedBy func(ctx context.Context, authorID int, userIDs []int) (map[int]bool, error)
+
+// Resolve находит в тексте @username и проверяет их через lookup.
+// Неизвестные имена остаются обычным текстом. Недоступность auth-service
+// не мешает опубликовать текст, упоминания в этом случае теряются.
+func Resolve(ctx context.Context, lookup Lookup, text string) []Mention {
+	candidates := Extract(text)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	type user struct {
+		id       int
+		username string
+	}
+	resolved := make(map[string]*user, len(candidates))
+
+	var mentions []Mention
+	for _, c := range candidates {
+		key := strings.ToLower(c.Username)
+		u, ok := resolved[key]
+		if !ok {
+			id, username, err := lookup(ctx, c.Username)
+			switch {
+			case err == nil:
+				u = &user{id: id, username: username}
+			case !errors.Is(err, ErrUserNotFound):
+				log.Printf("failed to resolve mention @%s: %v", c.Username, err)
+			}
+			resolved[key] = u
+		}
+		if u == nil {
+			continue
+		}
+
+		mentions = append(mentions, Mention{
+			UserID:   u.id,
+			Username: u.username,
+			Offset:   c.Offset,
+			Length:   c.Length,
+		})
+	}
+
+	return mentions
+}
+
+// DropBlocked убирает упоминания пользователей, заблокировавших автора:
+// такое имя остаётся обычным текстом и уведомления не будет.
+func DropBlocked(ctx context.Context, blockedBy BlockedBy, authorID int, mentions []Mention) []Mention {
+	if len(mentions) == 0 {
+		return mentions
+	}
+
+	ids := make([]int, 0, len(mentions))
+	for _, m := range mentions {
+		ids = append(ids, m.UserID)
+	}
+	blocked, err := blockedBy(ctx, authorID, ids)
+	if err != nil {
+		log.Printf("failed to check blocks for mentions: %v", err)
+		return mentions
+	}
+
+	kept := mentions[:0]
+	for _, m := range mentions {
+		if !blocked[m.UserID] {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
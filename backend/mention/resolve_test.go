@@ -0,0 +1,73 @@
+package mention
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	users := map[string]int{"alice": 1, "bob": 2}
+	calls := 0
+	lookup := func(ctx context.Context, username string) (int, string, error) {
+		calls++
+		switch name := strings.ToLower(username); {
+		case name == "down":
+			return 0, "", errors.New("auth-service unavailable")
+		case users[name] != 0:
+			return users[name], name, nil
+		default:
+			return 0, "", ErrUserNotFound
+		}
+	}
+
+	got := Resolve(context.Background(), lookup, "@Alice @ghost @down @alice @bob")
+	want := []Mention{
+		{UserID: 1, Username: "alice", Offset: 0, Length: 6},
+		{UserID: 1, Username: "alice", Offset: 20, Length: 6},
+		{UserID: 2, Username: "bob", Offset: 27, Length: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve() = %+v, want %+v", got, want)
+	}
+	// Одно и то же имя без учёта регистра проверяется один раз
+	if calls != 4 {
+		t.Errorf("lookup called %d times, want 4", calls)
+	}
+}
+
+func TestDropBlocked(t *testing.T) {
+	mentions := []Mention{{UserID: 1}, {UserID: 2}, {UserID: 3}}
+
+	tests := []struct {
+		name      string
+		blockedBy BlockedBy
+		want      []int
+	}{
+		{"nobody blocked", func(context.Context, int, []int) (map[int]bool, error) {
+			return nil, nil
+		}, []int{1, 2, 3}},
+		{"one blocked", func(context.Context, int, []int) (map[int]bool, error) {
+			return map[int]bool{2: true}, nil
+		}, []int{1, 3}},
+		// Без ответа от базы упоминания остаются
+		{"lookup failed", func(context.Context, int, []int) (map[int]bool, error) {
+			return nil, errors.New("db down")
+		}, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]Mention(nil), mentions...)
+			var got []int
+			for _, m := range DropBlocked(context.Background(), tt.blockedBy, 10, input) {
+				got = append(got, m.UserID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DropBlocked() kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
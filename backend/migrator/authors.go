@@ -0,0 +1,182 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// AuthorHistory знает, у кого было имя в каждый момент времени. Строится по
+// users и username_history из auth_db: имя, занятое заново после удаления
+// или переименования, не достаётся новому владельцу вместе со старым контентом.
+type AuthorHistory struct {
+	spans map[string][]nameSpan
+}
+
+// nameSpan - промежуток [from, to), когда имя принадлежало пользователю.
+// Нулевой to - имя у пользователя до сих пор.
+type nameSpan struct {
+	userID int
+	from   time.Time
+	to     time.Time
+}
+
+// nameChange - строка username_history: до changedAt пользователя звали username.
+type nameChange struct {
+	username  string
+	changedAt time.Time
+}
+
+func newAuthorHistory() *AuthorHistory {
+	return &AuthorHistory{spans: make(map[string][]nameSpan)}
+}
+
+// LoadAuthorHistory читает историю имён из базы auth-service. Удалённые
+// пользователи не попадают: их имена и история стёрты при обезличивании.
+func LoadAuthorHistory(ctx context.Context, authDB *sql.DB) (*AuthorHistory, error) {
+	changes := make(map[int][]nameChange)
+
+	rows, err := authDB.QueryContext(ctx,
+		"SELECT user_id, username, changed_at::timestamptz FROM username_history ORDER BY user_id, changed_at")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read username history: %w", err)
+	}
+	for rows.Next() {
+		var (
+			userID int
+			change nameChange
+		)
+		if err := rows.Scan(&userID, &change.username, &change.changedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		changes[userID] = append(changes[userID], change)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = authDB.QueryContext(ctx,
+		"SELECT id, username, created_at::timestamptz FROM users WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+	defer rows.Close()
+
+	history := newAuthorHistory()
+	for rows.Next() {
+		var (
+			userID    int
+			username  string
+			createdAt time.Time
+		)
+		if err := rows.Scan(&userID, &username, &createdAt); err != nil {
+			return nil, err
+		}
+		history.add(userID, username, createdAt, changes[userID])
+	}
+
+	return history, rows.Err()
+}
+
+// add записывает имена пользователя: прежние по порядку смены, затем текущее.
+func (h *AuthorHistory) add(userID int, current string, createdAt time.Time, changes []nameChange) {
+	from := createdAt
+	for _, change := range changes {
+		h.spans[change.username] = append(h.spans[change.username],
+			nameSpan{userID: userID, from: from, to: change.changedAt})
+		from = change.changedAt
+	}
+	h.spans[current] = append(h.spans[current], nameSpan{userID: userID, from: from})
+}
+
+// Resolve возвращает пользователя, которого звали name в момент at.
+func (h *AuthorHistory) Resolve(name string, at time.Time) (int, bool) {
+	for _, span := range h.spans[name] {
+		if at.Before(span.from) {
+			continue
+		}
+		if span.to.IsZero() || at.Before(span.to) {
+			return span.userID, true
+		}
+	}
+	return 0, false
+}
+
+// AuthorBackfill проставляет author_id строкам tables (колонки id, author,
+// created_at) по имени автора на момент создания записи. Строки, которые не
+// удалось сопоставить, остаются без author_id: править и удалять их могут
+// только модераторы. authDSN нужен, только если такие строки есть.
+func AuthorBackfill(db *sql.DB, authDSN string, tables ...string) Backfill {
+	return func(ctx context.Context) error {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		type row struct {
+			table     string
+			id        int
+			author    string
+			createdAt time.Time
+		}
+
+		var pending []row
+		for _, table := range tables {
+			rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+				"SELECT id, author, created_at::timestamptz FROM %s WHERE author_id IS NULL", table))
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", table, err)
+			}
+			for rows.Next() {
+				r := row{table: table}
+				if err := rows.Scan(&r.id, &r.author, &r.createdAt); err != nil {
+					rows.Close()
+					return err
+				}
+				pending = append(pending, r)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		authDB, err := sql.Open("postgres", authDSN)
+		if err != nil {
+			return err
+		}
+		defer authDB.Close()
+
+		history, err := LoadAuthorHistory(ctx, authDB)
+		if err != nil {
+			return err
+		}
+
+		var unresolved int
+		for _, r := range pending {
+			userID, ok := history.Resolve(r.author, r.createdAt)
+			if !ok {
+				unresolved++
+				continue
+			}
+			_, err := tx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET author_id = $2 WHERE id = $1", r.table),
+				r.id, userID)
+			if err != nil {
+				return fmt.Errorf("failed to update %s %d: %w", r.table, r.id, err)
+			}
+		}
+
+		if unresolved > 0 {
+			log.Printf("%d of %d rows have no known author and are left to moderators", unresolved, len(pending))
+		}
+		return tx.Commit()
+	}
+}
@@ -0,0 +1,48 @@
+package migrator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuthorHistoryResolve(t *testing.T) {
+	day := func(n int) time.Time {
+		return time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC)
+	}
+
+	history := newAuthorHistory()
+	// alice с 1-го, 10-го переименовалась в alicia
+	history.add(1, "alicia", day(1), []nameChange{{username: "alice", changedAt: day(10)}})
+	// bob зарегистрировался 15-го и занял освободившееся имя alice
+	history.add(2, "bob", day(15), []nameChange{{username: "alice", changedAt: day(20)}})
+	// carol с 5-го
+	history.add(3, "carol", day(5), nil)
+
+	tests := []struct {
+		name   string
+		author string
+		at     time.Time
+		want   int
+		found  bool
+	}{
+		{"first owner", "alice", day(3), 1, true},
+		{"rename boundary belongs to next name", "alice", day(10), 0, false},
+		{"new name after rename", "alicia", day(12), 1, true},
+		{"new name before rename", "alicia", day(5), 0, false},
+		{"name reused by other user", "alice", day(16), 2, true},
+		{"gap between owners", "alice", day(12), 0, false},
+		{"current name", "carol", day(30), 3, true},
+		{"before registration", "carol", day(4), 0, false},
+		{"case differs", "Carol", day(30), 0, false},
+		{"unknown name", "dave", day(30), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := history.Resolve(tt.author, tt.at)
+			if got != tt.want || found != tt.found {
+				t.Errorf("Resolve(%q, %v) = %d, %v; want %d, %v", tt.author, tt.at, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
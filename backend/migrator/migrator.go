@@ -0,0 +1,72 @@
+// Package migrator применяет миграции сервисов и выполняет между ними шаги
+// на Go - заполнение данных, которое нельзя выразить в SQL.
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// Backfill выполняется сразу после миграции с номером, под которым он
+// записан в Up. Должен быть идемпотентным: если он упал, версия остаётся
+// прежней и при следующем запуске шаг повторяется.
+type Backfill func(ctx context.Context) error
+
+// Up применяет миграции по одной (steps == 0 - все оставшиеся) и после
+// каждой запускает её backfill. Если все миграции уже применены,
+// возвращает migrate.ErrNoChange.
+func Up(m *migrate.Migrate, steps int, backfills map[uint]Backfill) error {
+	// Предыдущий запуск мог остановиться на упавшем backfill
+	if err := runBackfill(m, backfills); err != nil {
+		return err
+	}
+
+	for applied := 0; steps == 0 || applied < steps; applied++ {
+		if err := m.Steps(1); err != nil {
+			if !isLastMigration(err) {
+				return err
+			}
+			if applied == 0 {
+				return migrate.ErrNoChange
+			}
+			return nil
+		}
+		if err := runBackfill(m, backfills); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isLastMigration - Steps не нашёл следующей миграции. Дойдя до конца,
+// migrate отвечает не ErrNoChange, а os.ErrNotExist или ErrShortLimit.
+func isLastMigration(err error) bool {
+	var short migrate.ErrShortLimit
+	return errors.Is(err, migrate.ErrNoChange) || errors.Is(err, os.ErrNotExist) || errors.As(err, &short)
+}
+
+func runBackfill(m *migrate.Migrate, backfills map[uint]Backfill) error {
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	backfill, ok := backfills[version]
+	if !ok || dirty {
+		return nil
+	}
+
+	log.Printf("Running backfill after migration %d", version)
+	if err := backfill(context.Background()); err != nil {
+		return fmt.Errorf("backfill after migration %d: %w", version, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	dbstub "github.com/golang-migrate/migrate/v4/database/stub"
+	"github.com/golang-migrate/migrate/v4/source"
+	sourcestub "github.com/golang-migrate/migrate/v4/source/stub"
+)
+
+// newStubMigrate собирает migrate на заглушках с миграциями 1..count.
+func newStubMigrate(t *testing.T, count int) (*migrate.Migrate, *dbstub.Stub) {
+	t.Helper()
+
+	src, err := sourcestub.WithInstance(nil, &sourcestub.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v := 1; v <= count; v++ {
+		src.(*sourcestub.Stub).Migrations.Append(&source.Migration{
+			Version: uint(v), Direction: source.Up, Identifier: fmt.Sprintf("up %d", v),
+		})
+	}
+
+	db, err := dbstub.WithInstance(nil, &dbstub.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := migrate.NewWithInstance("stub", src, "stub", db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, db.(*dbstub.Stub)
+}
+
+func TestUp(t *testing.T) {
+	tests := []struct {
+		name        string
+		migrations  int
+		from        int
+		steps       int
+		wantErr     error
+		wantVersion int
+		wantRun     []string
+	}{
+		{"all", 3, 0, 0, nil, 3, []string{"up 1", "up 2", "up 3"}},
+		{"some steps", 3, 0, 2, nil, 2, []string{"up 1", "up 2"}},
+		{"steps over remaining", 3, 0, 5, nil, 3, []string{"up 1", "up 2", "up 3"}},
+		{"from middle", 3, 1, 0, nil, 3, []string{"up 2", "up 3"}},
+		{"up to date", 2, 2, 0, migrate.ErrNoChange, 2, nil},
+		{"up to date with steps", 2, 2, 1, migrate.ErrNoChange, 2, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, db := newStubMigrate(t, tt.migrations)
+			if tt.from > 0 {
+				if err := m.Steps(tt.from); err != nil {
+					t.Fatal(err)
+				}
+				db.MigrationSequence = nil
+			}
+
+			var backfilled []uint
+			backfills := map[uint]Backfill{}
+			for v := uint(1); v <= uint(tt.migrations); v++ {
+				v := v
+				backfills[v] = func(context.Context) error {
+					backfilled = append(backfilled, v)
+					return nil
+				}
+			}
+
+			err := Up(m, tt.steps, backfills)
+			if !errors.Is(err, tt.wantErr) || (err != nil && tt.wantErr == nil) {
+				t.Fatalf("Up() error = %v, want %v", err, tt.wantErr)
+			}
+			if db.CurrentVersion != tt.wantVersion {
+				t.Errorf("version = %d, want %d", db.CurrentVersion, tt.wantVersion)
+			}
+			if len(db.MigrationSequence) == 0 {
+				db.MigrationSequence = nil
+			}
+			if !reflect.DeepEqual(db.MigrationSequence, tt.wantRun) {
+				t.Errorf("applied %q, want %q", db.MigrationSequence, tt.wantRun)
+			}
+			// Backfill текущей версии запускается и до первого шага
+			if len(backfilled) == 0 || backfilled[len(backfilled)-1] != uint(tt.wantVersion) {
+				t.Errorf("backfills run %v, want the last for version %d", backfilled, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestUpBackfillFailureStops(t *testing.T) {
+	m, db := newStubMigrate(t, 3)
+	failure := errors.New("backfill failed")
+
+	err := Up(m, 0, map[uint]Backfill{2: func(context.Context) error { return failure }})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Up() error = %v, want %v", err, failure)
+	}
+	if db.CurrentVersion != 2 {
+		t.Errorf("version = %d, want 2", db.CurrentVersion)
+	}
+}
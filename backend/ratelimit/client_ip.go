@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParseTrustedProxies разбирает список адресов из конфига. Допускаются
+// как подсети, так и отдельные IP.
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", value)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
+
+// ClientIP определяет адрес клиента для лимитов по IP. Берётся адрес
+// соединения; если это доверенный прокси, адрес ищется в X-Forwarded-For
+// справа налево до первого недоверенного адреса. Так же адрес определяют
+// gin с SetTrustedProxies и auth-service.
+func (l *Limiter) ClientIP(r *http.Request) string {
+	addr, ok := parseIP(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !l.trustedProxy(addr) {
+		return addr.String()
+	}
+
+	var forwarded []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(value, ",")...)
+	}
+
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedAddr, ok := parseIP(strings.TrimSpace(forwarded[i]))
+		if !ok {
+			break
+		}
+		addr = forwardedAddr
+		if !l.trustedProxy(addr) {
+			break
+		}
+	}
+
+	return addr.String()
+}
+
+func (l *Limiter) trustedProxy(addr netip.Addr) bool {
+	for _, prefix := range l.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIP принимает как "host:port", так и голый адрес.
+func parseIP(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
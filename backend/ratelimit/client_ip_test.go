@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"no proxies", nil, "203.0.113.5:4000", nil, "203.0.113.5"},
+		{"header from untrusted peer ignored", nil, "203.0.113.5:4000", []string{"198.51.100.1"}, "203.0.113.5"},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.0.0.2:4000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed left entries ignored", []string{"10.0.0.0/8"}, "10.0.0.2:4000",
+			[]string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of proxies", []string{"10.0.0.0/8"}, "10.0.0.2:4000",
+			[]string{"198.51.100.1, 10.0.0.7", "10.0.0.3"}, "198.51.100.1"},
+		{"single trusted ip", []string{"127.0.0.1"}, "127.0.0.1:4000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"trusted proxy without header", []string{"10.0.0.0/8"}, "10.0.0.2:4000", nil, "10.0.0.2"},
+		{"invalid entry stops walk", []string{"10.0.0.0/8"}, "10.0.0.2:4000",
+			[]string{"198.51.100.1, garbage"}, "10.0.0.2"},
+		{"ipv4 mapped ipv6", []string{"10.0.0.0/8"}, "[::ffff:10.0.0.2]:4000", []string{"198.51.100.1"},
+			"198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := NewLimiter(Config{TrustedProxies: tt.trusted}, NewMemoryStore())
+			if err != nil {
+				t.Fatalf("NewLimiter() error = %v", err)
+			}
+
+			r := &http.Request{RemoteAddr: tt.remoteAddr, Header: http.Header{}}
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+
+			if got := limiter.ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesInvalid(t *testing.T) {
+	if _, err := ParseTrustedProxies([]string{"10.0.0.0/8", "proxy.local"}); err == nil {
+		t.Error("expected error for host name")
+	}
+}
@@ -0,0 +1,148 @@
+// Package ratelimit ограничивает частоту записи корзинами токенов: отдельно
+// на пользователя и на IP для каждого действия. Лимиты по ролям и выбор
+// хранилища задаются в Config, имена действий - в сервисах.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/netip"
+	"strconv"
+	"time"
+)
+
+// Config - лимиты частоты по действиям. Действие без лимита не ограничено.
+// Сервисы встраивают его в свой конфиг.
+type Config struct {
+	// Store - memory (счётчики в процессе) или postgres (общие для всех
+	// экземпляров сервиса, в его базе)
+	Store   string                 `yaml:"store"`
+	Actions map[string]ActionLimit `yaml:"actions"`
+	// TrustedProxies - адреса, которым доверяем X-Forwarded-For (CIDR или IP).
+	// Пустой список - IP клиента всегда адрес соединения
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// ActionLimit - лимиты одного действия: на пользователя и на IP.
+type ActionLimit struct {
+	User RateLimit `yaml:"user"`
+	IP   RateLimit `yaml:"ip"`
+	// Roles заменяет лимит пользователя для ролей. Лимит с requests: 0
+	// снимает для роли все ограничения действия, в том числе по IP
+	Roles map[string]RateLimit `yaml:"roles"`
+}
+
+// RateLimit - корзина токенов: Requests запросов за Per, подряд не больше
+// Burst (по умолчанию Requests). Requests 0 - без ограничения.
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+// Subject - кто выполняет действие. UserID 0 - анонимный запрос, для него
+// действует только лимит по IP.
+type Subject struct {
+	UserID int
+	Role   string
+	IP     string
+}
+
+type Limiter struct {
+	actions map[string]ActionLimit
+	store   Store
+	trusted []netip.Prefix
+	// idle - после такого простоя корзина снова полная и её можно удалить
+	idle time.Duration
+}
+
+func NewLimiter(cfg Config, store Store) (*Limiter, error) {
+	trusted, err := ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Limiter{actions: cfg.Actions, store: store, trusted: trusted}
+	for action, limit := range cfg.Actions {
+		limits := []RateLimit{limit.User, limit.IP}
+		for _, roleLimit := range limit.Roles {
+			limits = append(limits, roleLimit)
+		}
+		for _, rl := range limits {
+			if rl.Requests < 0 || rl.Burst < 0 || (rl.Requests > 0 && rl.Per <= 0) {
+				return nil, fmt.Errorf("rate limit %s: requests and burst must not be negative, per must be positive",
+					action)
+			}
+			if rl.Requests > 0 && rl.Per > l.idle {
+				l.idle = rl.Per
+			}
+		}
+	}
+	return l, nil
+}
+
+// Allow забирает токен действия. Если токена нет, возвращает, через сколько
+// повторить. Ошибка хранилища только логируется, чтобы сбой базы не
+// останавливал запись.
+func (l *Limiter) Allow(ctx context.Context, action string, subject Subject) (bool, time.Duration) {
+	limit, ok := l.actions[action]
+	if !ok {
+		return true, 0
+	}
+
+	userLimit := limit.User
+	if roleLimit, ok := limit.Roles[subject.Role]; ok && subject.Role != "" {
+		if roleLimit.Requests == 0 {
+			return true, 0
+		}
+		userLimit = roleLimit
+	}
+
+	if subject.UserID != 0 {
+		key := action + ":user:" + strconv.Itoa(subject.UserID)
+		if allowed, retryAfter := l.take(ctx, key, userLimit); !allowed {
+			return false, retryAfter
+		}
+	}
+	if subject.IP != "" {
+		if allowed, retryAfter := l.take(ctx, action+":ip:"+subject.IP, limit.IP); !allowed {
+			return false, retryAfter
+		}
+	}
+	return true, 0
+}
+
+func (l *Limiter) take(ctx context.Context, key string, limit RateLimit) (bool, time.Duration) {
+	if limit.Requests == 0 {
+		return true, 0
+	}
+
+	allowed, retryAfter, err := l.store.Take(ctx, key, limit, time.Now())
+	if err != nil {
+		log.Printf("Rate limit store error for %s: %v", key, err)
+		return true, 0
+	}
+	return allowed, retryAfter
+}
+
+// Run удаляет простаивающие корзины, пока не отменён ctx.
+func (l *Limiter) Run(ctx context.Context) {
+	if l.idle == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := l.store.Prune(ctx, now.Add(-l.idle)); err != nil {
+				log.Printf("Failed to prune rate limit buckets: %v", err)
+			}
+		}
+	}
+}
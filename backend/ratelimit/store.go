@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+)
+
+// Store хранит корзины токенов.
+type Store interface {
+	// Take забирает токен из корзины key. Если токена нет, возвращает,
+	// через сколько он появится.
+	Take(ctx context.Context, key string, limit RateLimit, now time.Time) (bool, time.Duration, error)
+	// Prune удаляет корзины, которые не менялись с before.
+	Prune(ctx context.Context, before time.Time) error
+}
+
+// NewStore выбирает хранилище по конфигу. postgres хранит корзины в базе
+// сервиса, так лимит общий для всех его экземпляров.
+func NewStore(cfg Config, db *sql.DB) (Store, error) {
+	switch cfg.Store {
+	case "", StoreMemory:
+		return NewMemoryStore(), nil
+	case StorePostgres:
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store: %s", cfg.Store)
+	}
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// take пополняет корзину за прошедшее время и забирает из неё токен.
+func (b *bucket) take(limit RateLimit, now time.Time) (bool, time.Duration) {
+	capacity := float64(limit.Burst)
+	if limit.Burst == 0 {
+		capacity = float64(limit.Requests)
+	}
+	perToken := limit.Per / time.Duration(limit.Requests)
+
+	// Часы разных экземпляров могут немного расходиться
+	if elapsed := now.Sub(b.updatedAt); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)/float64(perToken))
+		b.updatedAt = now
+	}
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	b.tokens--
+	return true, 0
+}
+
+func fullBucket(limit RateLimit, now time.Time) bucket {
+	capacity := limit.Burst
+	if capacity == 0 {
+		capacity = limit.Requests
+	}
+	return bucket{tokens: float64(capacity), updatedAt: now}
+}
+
+// MemoryStore держит корзины в памяти процесса.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit RateLimit,
+	now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		full := fullBucket(limit, now)
+		b = &full
+		s.buckets[key] = b
+	}
+	allowed, retryAfter := b.take(limit, now)
+	return allowed, retryAfter, nil
+}
+
+func (s *MemoryStore) Prune(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if b.updatedAt.Before(before) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
+
+// PostgresStore хранит корзины в таблице rate_limit_buckets.
+type PostgresStore struct {
+	Db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{Db: db}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit RateLimit,
+	now time.Time) (bool, time.Duration, error) {
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, 0, err
+	}
+	defer tx.Rollback()
+
+	full := fullBucket(limit, now)
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES ($1, $2, $3) ON CONFLICT (key) DO NOTHING`,
+		key, full.tokens, full.updatedAt,
+	)
+	if err != nil {
+		return false, 0, err
+	}
+
+	// FOR UPDATE, чтобы параллельные запросы не потратили один токен дважды
+	var b bucket
+	err = tx.QueryRowContext(ctx,
+		`SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`, key,
+	).Scan(&b.tokens, &b.updatedAt)
+	if err != nil {
+		return false, 0, err
+	}
+
+	allowed, retryAfter := b.take(limit, now)
+	_, err = tx.ExecContext(ctx,
+		`UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1`,
+		key, b.tokens, b.updatedAt,
+	)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return allowed, retryAfter, tx.Commit()
+}
+
+func (s *PostgresStore) Prune(ctx context.Context, before time.Time) error {
+	_, err := s.Db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < $1`, before)
+	return err
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBucketTake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// 6 запросов в минуту - токен каждые 10 секунд
+	limit := RateLimit{Requests: 6, Per: time.Minute, Burst: 2}
+
+	tests := []struct {
+		name       string
+		tokens     float64
+		elapsed    time.Duration
+		limit      RateLimit
+		allowed    bool
+		retryAfter time.Duration
+		left       float64
+	}{
+		{"full bucket", 2, 0, limit, true, 0, 1},
+		{"last token", 1, 0, limit, true, 0, 0},
+		{"empty bucket", 0, 0, limit, false, 10 * time.Second, 0},
+		{"partly refilled", 0, 4 * time.Second, limit, false, 6 * time.Second, 0.4},
+		{"refilled one token", 0, 10 * time.Second, limit, true, 0, 0},
+		{"refill capped by burst", 0, time.Hour, limit, true, 0, 1},
+		{"burst defaults to requests", 0, time.Hour, RateLimit{Requests: 6, Per: time.Minute}, true, 0, 5},
+		// Часы другого экземпляра отстают - корзина не пополняется
+		{"clock skew", 0, -5 * time.Second, limit, false, 10 * time.Second, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := bucket{tokens: tt.tokens, updatedAt: start}
+
+			allowed, retryAfter := b.take(tt.limit, start.Add(tt.elapsed))
+			if allowed != tt.allowed || retryAfter != tt.retryAfter {
+				t.Errorf("take() = %v, %v; want %v, %v", allowed, retryAfter, tt.allowed, tt.retryAfter)
+			}
+			if diff := b.tokens - tt.left; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("tokens left = %v, want %v", b.tokens, tt.left)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreBurst(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+	limit := RateLimit{Requests: 10, Per: time.Minute, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		if allowed, _, _ := store.Take(ctx, "key", limit, now); !allowed {
+			t.Fatalf("request %d rejected within burst", i+1)
+		}
+	}
+	if allowed, _, _ := store.Take(ctx, "key", limit, now); allowed {
+		t.Error("request over burst allowed")
+	}
+	if allowed, _, _ := store.Take(ctx, "other", limit, now); !allowed {
+		t.Error("other key shares the bucket")
+	}
+}